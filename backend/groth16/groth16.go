@@ -16,6 +16,7 @@
 package groth16
 
 import (
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
 	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
 	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
@@ -75,20 +76,22 @@ func Verify(proof Proof, vk VerifyingKey, solution interface{}) error {
 }
 
 // Prove generate a groth16.Proof
-func Prove(r1cs r1cs.R1CS, pk ProvingKey, solution interface{}) (Proof, error) {
+//
+// opts configure the solver (see backend.SolveOption)
+func Prove(r1cs r1cs.R1CS, pk ProvingKey, solution interface{}, opts ...backend.SolveOption) (Proof, error) {
 	_solution, err := frontend.ParseWitness(solution)
 	if err != nil {
 		return nil, err
 	}
 	switch _r1cs := r1cs.(type) {
 	case *backend_bls377.R1CS:
-		return groth16_bls377.Prove(_r1cs, pk.(*groth16_bls377.ProvingKey), _solution)
+		return groth16_bls377.Prove(_r1cs, pk.(*groth16_bls377.ProvingKey), _solution, opts...)
 	case *backend_bls381.R1CS:
-		return groth16_bls381.Prove(_r1cs, pk.(*groth16_bls381.ProvingKey), _solution)
+		return groth16_bls381.Prove(_r1cs, pk.(*groth16_bls381.ProvingKey), _solution, opts...)
 	case *backend_bn256.R1CS:
-		return groth16_bn256.Prove(_r1cs, pk.(*groth16_bn256.ProvingKey), _solution)
+		return groth16_bn256.Prove(_r1cs, pk.(*groth16_bn256.ProvingKey), _solution, opts...)
 	case *backend_bw761.R1CS:
-		return groth16_bw761.Prove(_r1cs, pk.(*groth16_bw761.ProvingKey), _solution)
+		return groth16_bw761.Prove(_r1cs, pk.(*groth16_bw761.ProvingKey), _solution, opts...)
 	default:
 		panic("unrecognized R1CS curve type")
 	}
@@ -233,6 +236,6 @@ func ReadProof(path string) (Proof, error) {
 }
 
 // GetBN256Proof returns an empty proof
-func GetBN256Proof(path string) groth16_bn256.Proof {
+func GetBN256Proof(path string) *groth16_bn256.Proof {
 	return &groth16_bn256.Proof{}
 }
@@ -0,0 +1,56 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+)
+
+// ErrUnknownHint can be generated when solving a R1CS using a hint that is not registered
+var ErrUnknownHint = errors.New("hint is not registered")
+
+// HintFunction computes the values of the outputs of a hint from the values of its inputs.
+// The values are in regular form (not Montgomery), and reduced modulo the snark field.
+//
+// The solver doesn't check the outputs: a circuit using a hint must constrain its outputs
+// (for example, an inverse computed by a hint is checked with a multiplication).
+type HintFunction func(inputs []big.Int, outputs []big.Int) error
+
+var (
+	hintsLock sync.RWMutex
+	hints     = make(map[string]HintFunction)
+)
+
+// RegisterHint registers f under id, so that the R1CS solver can run the hints with this id.
+// It is typically called in the init() function of the package defining the hint.
+// It panics if id is already registered.
+func RegisterHint(id string, f HintFunction) {
+	hintsLock.Lock()
+	defer hintsLock.Unlock()
+	if _, ok := hints[id]; ok {
+		panic("hint " + id + " is already registered")
+	}
+	hints[id] = f
+}
+
+// GetHint returns the hint function registered under id
+func GetHint(id string) (HintFunction, bool) {
+	hintsLock.RLock()
+	defer hintsLock.RUnlock()
+	f, ok := hints[id]
+	return f, ok
+}
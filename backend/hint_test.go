@@ -0,0 +1,77 @@
+package backend_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+func init() {
+	backend.RegisterHint("backend_test.divMod", func(inputs, outputs []big.Int) error {
+		if inputs[1].Sign() == 0 {
+			return errors.New("division by zero")
+		}
+		outputs[0].DivMod(&inputs[0], &inputs[1], &outputs[1])
+		return nil
+	})
+}
+
+// hintCircuit checks that Q is the quotient of the euclidean division of X+1 by 7,
+// the quotient and remainder being computed by a hint
+type hintCircuit struct {
+	X frontend.Variable
+	Q frontend.Variable `gnark:",public"`
+}
+
+func (circuit *hintCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	qr := cs.NewHint("backend_test.divMod", 2, cs.LinearExpression(cs.Term(circuit.X, big.NewInt(1)), cs.Term(cs.Constant(1), big.NewInt(1))), 7)
+	cs.AssertIsEqual(cs.Add(cs.Mul(qr[0], 7), qr[1]), cs.Add(circuit.X, 1))
+	cs.AssertIsLessOrEqual(qr[1], 6)
+	cs.AssertIsEqual(qr[0], circuit.Q)
+	return nil
+}
+
+func TestHint(t *testing.T) {
+	for _, curveID := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		r1cs, err := frontend.Compile(curveID, &hintCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert := groth16.NewAssert(t)
+
+		var good hintCircuit
+		good.X.Assign(22)
+		good.Q.Assign(3)
+		assert.ProverSucceeded(r1cs, &good)
+
+		var bad hintCircuit
+		bad.X.Assign(22)
+		bad.Q.Assign(4)
+		assert.ProverFailed(r1cs, &bad)
+	}
+}
+
+func TestNewHintUnregistered(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("NewHint should panic on an unregistered hint")
+		}
+	}()
+	var cs frontend.ConstraintSystem
+	cs.NewHint("backend_test.unregistered", 1, 1)
+}
+
+func TestRegisterHintTwice(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("registering a hint twice should panic")
+		}
+	}()
+	backend.RegisterHint("backend_test.divMod", nil)
+}
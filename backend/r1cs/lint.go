@@ -0,0 +1,186 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package r1cs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+)
+
+// Lint issues
+var (
+	ErrUnusedSecretInput  = errors.New("secret input appears in no constraint")
+	ErrUnusedWire         = errors.New("internal wire is never used")
+	ErrConstantConstraint = errors.New("constraint only involves constants")
+)
+
+// LintIssue is a suspicious pattern reported by Lint
+type LintIssue struct {
+	Err        error  // ErrUnusedSecretInput, ErrUnusedWire or ErrConstantConstraint
+	Wire       int    // index of the wire, -1 for constraint issues
+	Constraint int    // index of the constraint, -1 for wire issues
+	Origin     string // where the wire or constraint comes from (cf Lint)
+}
+
+func (issue *LintIssue) Error() string {
+	return issue.Err.Error() + ": " + issue.Origin
+}
+
+func (issue *LintIssue) Unwrap() error {
+	return issue.Err
+}
+
+// LintError is returned by frontend.Compile when the linter is enabled and reports issues
+type LintError struct {
+	Issues []LintIssue
+}
+
+func (err *LintError) Error() string {
+	msgs := make([]string, len(err.Issues))
+	for i := 0; i < len(err.Issues); i++ {
+		msgs[i] = err.Issues[i].Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Is returns true if one of the issues matches target
+func (err *LintError) Is(target error) bool {
+	for i := 0; i < len(err.Issues); i++ {
+		if errors.Is(&err.Issues[i], target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint reports the secret inputs which appear in no constraint, the internal wires which are
+// never used, and the constraints which only involve constants (they always or never hold).
+//
+// An internal wire is unused if it appears in no constraint, or only in the constraint computing it.
+//
+// Only the assertions record the call stack which added them (cf DebugInfo), so the origin of an assertion
+// has its call stack, while a computational constraint, and an internal wire, are only identified by their
+// index and scope.
+func Lint(r1cs R1CS) ([]LintIssue, error) {
+	s, err := untyped(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	constraints := s.wireConstraints()
+
+	// wires used as hint inputs, and hint outputs
+	hintInputs := make([]bool, s.NbWires)
+	hintOutputs := make([]bool, s.NbWires)
+	for _, h := range s.Hints {
+		for _, l := range h.Inputs {
+			for _, t := range l {
+				hintInputs[t.ConstraintID()] = true
+			}
+		}
+		for _, t := range h.Outputs {
+			hintOutputs[t.ConstraintID()] = true
+		}
+	}
+
+	var res []LintIssue
+	secretOffset := s.NbWires - s.NbPublicWires - s.NbSecretWires
+	for wire := 0; wire < secretOffset; wire++ {
+		used := len(constraints[wire]) > 1 || hintInputs[wire]
+		if len(constraints[wire]) == 1 {
+			// the constraint computes the wire, unless it is an assertion or the wire is computed by a hint
+			used = used || constraints[wire][0] >= s.NbCOConstraints || hintOutputs[wire]
+		}
+		if !used {
+			res = append(res, LintIssue{
+				Err:        ErrUnusedWire,
+				Wire:       wire,
+				Constraint: -1,
+				Origin:     s.wireOrigin(wire, constraints),
+			})
+		}
+	}
+	for wire := secretOffset; wire < secretOffset+s.NbSecretWires; wire++ {
+		if len(constraints[wire]) == 0 {
+			res = append(res, LintIssue{
+				Err:        ErrUnusedSecretInput,
+				Wire:       wire,
+				Constraint: -1,
+				Origin:     s.wireOrigin(wire, constraints),
+			})
+		}
+	}
+
+	oneWire := -1
+	for i, name := range s.PublicWires {
+		if name == backend.OneWire {
+			oneWire = s.NbWires - s.NbPublicWires + i
+		}
+	}
+	for i, r := range s.Constraints {
+		if s.isConstant(r.L, oneWire) && s.isConstant(r.R, oneWire) && s.isConstant(r.O, oneWire) {
+			res = append(res, LintIssue{
+				Err:        ErrConstantConstraint,
+				Wire:       -1,
+				Constraint: i,
+				Origin:     s.constraintOrigin(i),
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// isConstant returns true if all the terms of l are on the ONE wire, or have a zero coefficient
+func (s *UntypedR1CS) isConstant(l r1c.LinearExpression, oneWire int) bool {
+	for _, t := range l {
+		if t.ConstraintID() == oneWire {
+			continue
+		}
+		switch t.CoeffValue() {
+		case 0:
+		case -1, 1, 2:
+			return false
+		default:
+			if s.Coefficients[t.CoeffID()].Sign() != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// constraintOrigin returns the debug info of an assertion (with its call stack), where the
+// wire values are replaced by the wire names
+func (s *UntypedR1CS) constraintOrigin(i int) string {
+	res := fmt.Sprintf("constraint #%d", i)
+	if scope := s.ConstraintScope(i); scope != "" {
+		res += fmt.Sprintf(" in scope %q", scope)
+	}
+	if i < s.NbCOConstraints || i-s.NbCOConstraints >= len(s.DebugInfo) {
+		return res
+	}
+	entry := s.DebugInfo[i-s.NbCOConstraints]
+	names := make([]interface{}, len(entry.ToResolve))
+	for j, wire := range entry.ToResolve {
+		names[j] = s.wireName(wire)
+	}
+	return res + ": " + fmt.Sprintf(entry.Format, names...)
+}
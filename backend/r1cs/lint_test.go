@@ -0,0 +1,78 @@
+package r1cs_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type lintCircuit struct {
+	X, Unused frontend.Variable
+	Y         frontend.Variable `gnark:",public"`
+}
+
+func (circuit *lintCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	cs.Mul(circuit.X, circuit.Y)
+	cs.AssertIsEqual(circuit.Y, cs.Mul(circuit.X, circuit.X))
+	cs.AssertIsEqual(2, 3)
+	return nil
+}
+
+// otherR1CS is an R1CS implementation unknown to package r1cs
+type otherR1CS struct {
+	r1cs.R1CS
+}
+
+func TestLint(t *testing.T) {
+	var circuit lintCircuit
+	r, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := r1cs.Lint(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %v", issues)
+	}
+	if issues[0].Err != r1cs.ErrUnusedWire || issues[0].Wire != 0 {
+		t.Fatal("the result of the first multiplication should be unused", issues[0].Error())
+	}
+	if issues[1].Err != r1cs.ErrUnusedSecretInput || !strings.Contains(issues[1].Origin, `"Unused"`) {
+		t.Fatal("the secret input Unused should be reported", issues[1].Error())
+	}
+	if issues[2].Err != r1cs.ErrConstantConstraint || !strings.Contains(issues[2].Origin, "2 == 3") ||
+		!strings.Contains(issues[2].Origin, "lintCircuit).Define") {
+		t.Fatal("the constant assertion should be reported with its call stack", issues[2].Error())
+	}
+
+	// the same issues are reported on the untyped R1CS
+	untyped, err := frontend.Compile(gurvy.UNKNOWN, &lintCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues, err := r1cs.Lint(untyped); err != nil || len(issues) != 3 {
+		t.Fatal("expected 3 issues on the untyped R1CS", err)
+	}
+
+	// an unknown R1CS implementation is an error
+	if _, err := r1cs.Lint(otherR1CS{r}); err == nil {
+		t.Fatal("Lint should fail on an unknown R1CS implementation")
+	}
+
+	_, err = frontend.Compile(gurvy.BN256, &lintCircuit{}, frontend.WithLint())
+	var lintErr *r1cs.LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) != 3 || !errors.Is(err, r1cs.ErrUnusedSecretInput) {
+		t.Fatal("Compile should fail with the lint issues", err)
+	}
+
+	if _, err := frontend.Compile(gurvy.BN256, &bitsCircuit{assertBits: true}, frontend.WithLint()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -34,3 +34,13 @@ const (
 	SingleOutput SolvingMethod = iota
 	BinaryDec
 )
+
+// Hint computes wires outside of the constraint system, with the function registered
+// under ID (cf backend.RegisterHint). It is run by the solver before the computational
+// constraint at Position, the outputs being constrained by the following constraints.
+type Hint struct {
+	ID       string
+	Inputs   []LinearExpression
+	Outputs  []Term // the outputs are wires (coefficient 1)
+	Position int
+}
@@ -16,6 +16,7 @@
 package r1cs
 
 import (
+	"github.com/consensys/gnark/backend"
 	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
 	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
 	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
@@ -28,7 +29,7 @@ import (
 // it's underlying implementation is curve specific (i.e bn256/R1CS, ...)
 type R1CS interface {
 	io.CurveObject
-	IsSolved(solution map[string]interface{}) error
+	IsSolved(solution map[string]interface{}, opts ...backend.SolveOption) error
 	GetNbConstraints() int
 	GetNbWires() int
 	GetNbCoefficients() int
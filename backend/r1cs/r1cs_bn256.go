@@ -17,6 +17,8 @@
 package r1cs
 
 import (
+	"math/big"
+
 	bn256backend "github.com/consensys/gnark/internal/backend/bn256"
 
 	"github.com/consensys/gurvy/bn256/fr"
@@ -25,17 +27,21 @@ import (
 func (r1cs *UntypedR1CS) toBN256() *bn256backend.R1CS {
 
 	toReturn := bn256backend.R1CS{
-		NbWires:         r1cs.NbWires,
-		NbPublicWires:   r1cs.NbPublicWires,
-		NbSecretWires:   r1cs.NbSecretWires,
-		SecretWires:     r1cs.SecretWires,
-		PublicWires:     r1cs.PublicWires,
-		NbConstraints:   r1cs.NbConstraints,
-		NbCOConstraints: r1cs.NbCOConstraints,
-		Constraints:     r1cs.Constraints,
-		Coefficients:    make([]fr.Element, len(r1cs.Coefficients)),
-		Logs:            r1cs.Logs,
-		DebugInfo:       r1cs.DebugInfo,
+		NbWires:          r1cs.NbWires,
+		NbPublicWires:    r1cs.NbPublicWires,
+		NbSecretWires:    r1cs.NbSecretWires,
+		SecretWires:      r1cs.SecretWires,
+		PublicWires:      r1cs.PublicWires,
+		NbConstraints:    r1cs.NbConstraints,
+		NbCOConstraints:  r1cs.NbCOConstraints,
+		Constraints:      r1cs.Constraints,
+		Coefficients:     make([]fr.Element, len(r1cs.Coefficients)),
+		Hints:            r1cs.Hints,
+		Logs:             r1cs.Logs,
+		DebugInfo:        r1cs.DebugInfo,
+		Scopes:           r1cs.Scopes,
+		ConstraintScopes: r1cs.ConstraintScopes,
+		WireScopes:       r1cs.WireScopes,
 	}
 
 	for i := 0; i < len(r1cs.Coefficients); i++ {
@@ -44,3 +50,48 @@ func (r1cs *UntypedR1CS) toBN256() *bn256backend.R1CS {
 
 	return &toReturn
 }
+
+// fromBN256 converts the coefficients of a BN256 R1CS back to big.Int
+func fromBN256(r1cs *bn256backend.R1CS) *UntypedR1CS {
+
+	toReturn := UntypedR1CS{
+		NbWires:          r1cs.NbWires,
+		NbPublicWires:    r1cs.NbPublicWires,
+		NbSecretWires:    r1cs.NbSecretWires,
+		SecretWires:      r1cs.SecretWires,
+		PublicWires:      r1cs.PublicWires,
+		NbConstraints:    r1cs.NbConstraints,
+		NbCOConstraints:  r1cs.NbCOConstraints,
+		Constraints:      r1cs.Constraints,
+		Coefficients:     make([]big.Int, len(r1cs.Coefficients)),
+		Hints:            r1cs.Hints,
+		Logs:             r1cs.Logs,
+		DebugInfo:        r1cs.DebugInfo,
+		Scopes:           r1cs.Scopes,
+		ConstraintScopes: r1cs.ConstraintScopes,
+		WireScopes:       r1cs.WireScopes,
+	}
+
+	for i := 0; i < len(r1cs.Coefficients); i++ {
+		r1cs.Coefficients[i].ToBigIntRegular(&toReturn.Coefficients[i])
+	}
+
+	return &toReturn
+}
+
+// solveBN256 solves the R1CS and returns the wire values in regular form
+func solveBN256(r1cs *bn256backend.R1CS, assignment map[string]interface{}) ([]big.Int, error) {
+	a := make([]fr.Element, r1cs.NbConstraints)
+	b := make([]fr.Element, r1cs.NbConstraints)
+	c := make([]fr.Element, r1cs.NbConstraints)
+	wireValues := make([]fr.Element, r1cs.NbWires)
+	if err := r1cs.Solve(assignment, a, b, c, wireValues); err != nil {
+		return nil, err
+	}
+
+	toReturn := make([]big.Int, len(wireValues))
+	for i := 0; i < len(wireValues); i++ {
+		wireValues[i].ToBigIntRegular(&toReturn[i])
+	}
+	return toReturn, nil
+}
@@ -0,0 +1,108 @@
+package r1cs_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type printCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *printCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	cs.Println("x is", circuit.X)
+	cs.AssertIsEqual(cs.Mul(circuit.X, circuit.X), circuit.Y)
+	return nil
+}
+
+func TestSolverError(t *testing.T) {
+	var circuit, witness printCircuit
+	r, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness.X.Assign(3)
+	witness.Y.Assign(10)
+	assignment, err := frontend.ParseWitness(&witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(err error, constraint int, logs string) {
+		t.Helper()
+		var uerr *backend.UnsatisfiedConstraintError
+		if !errors.As(err, &uerr) || !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+			t.Fatal("expected an UnsatisfiedConstraintError, got", err)
+		}
+		if uerr.Constraint != constraint || uerr.L.Int64() != 9 || uerr.R.Int64() != 1 || uerr.O.Int64() != 10 {
+			t.Fatalf("unexpected constraint %d: %s * %s == %s", uerr.Constraint, uerr.L.String(), uerr.R.String(), uerr.O.String())
+		}
+		if uerr.DebugInfo != "9 == 10" || len(uerr.Stack) == 0 || !strings.Contains(uerr.Stack[len(uerr.Stack)-1], "(*printCircuit).Define") {
+			t.Fatalf("unexpected debug info %q and stack %q", uerr.DebugInfo, uerr.Stack)
+		}
+		if logs != "solve_test.go:20 x is 3\n" {
+			t.Fatalf("unexpected logs %q", logs)
+		}
+	}
+
+	// solver: the assertion is the second constraint
+	var logs bytes.Buffer
+	check(r.IsSolved(assignment, backend.WithLogWriter(&logs)), 1, logs.String())
+
+	// test engine: the index of the assertion is unknown
+	logs.Reset()
+	check(frontend.Execute(gurvy.BN256, &circuit, &witness, backend.WithLogWriter(&logs)), -1, logs.String())
+}
+
+type invertCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *invertCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	inv := cs.Inverse(circuit.X)
+	cs.Println("inv is", inv)
+	square := cs.Mul(inv, inv)
+	cs.Println("square is", square)
+	cs.AssertIsEqual(square, circuit.Y)
+	return nil
+}
+
+func TestSolverInverseOfZero(t *testing.T) {
+	var witness invertCircuit
+	witness.X.Assign(0)
+	witness.Y.Assign(0)
+	assignment, err := frontend.ParseWitness(&witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, curveID := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		r, err := frontend.Compile(curveID, &invertCircuit{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the inverse is the first computational constraint: 0 * 0 == 1
+		// the solver stops there, so the square is not logged
+		var logs bytes.Buffer
+		err = r.IsSolved(assignment, backend.WithLogWriter(&logs))
+		var uerr *backend.UnsatisfiedConstraintError
+		if !errors.As(err, &uerr) {
+			t.Fatal("expected an UnsatisfiedConstraintError, got", err)
+		}
+		if uerr.Constraint != 0 || uerr.L.Sign() != 0 || uerr.R.Sign() != 0 || uerr.O.Int64() != 1 {
+			t.Fatalf("unexpected constraint %d: %s * %s == %s", uerr.Constraint, uerr.L.String(), uerr.R.String(), uerr.O.String())
+		}
+		if logs.String() != "solve_test.go:71 inv is 0\n" {
+			t.Fatalf("unexpected logs %q", logs.String())
+		}
+	}
+}
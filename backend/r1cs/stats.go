@@ -0,0 +1,119 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package r1cs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+)
+
+// ScopeStats counts the constraints and internal wires created in a scope (cf frontend.ConstraintSystem.Scope)
+type ScopeStats struct {
+	Scope           string // "" for the root scope
+	NbConstraints   int
+	NbInternalWires int
+}
+
+// Stats returns the number of constraints and internal wires of each scope, sorted by scope name.
+// The counts of a scope don't include its nested scopes.
+func Stats(r1cs R1CS) ([]ScopeStats, error) {
+	s, err := untyped(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	if s.ConstraintScopes == nil {
+		return []ScopeStats{{
+			NbConstraints:   s.NbConstraints,
+			NbInternalWires: s.NbWires - s.NbPublicWires - s.NbSecretWires,
+		}}, nil
+	}
+
+	res := make([]ScopeStats, len(s.Scopes))
+	for i, scope := range s.Scopes {
+		res[i].Scope = scope
+	}
+	for _, scope := range s.ConstraintScopes {
+		res[scope].NbConstraints++
+	}
+	for _, scope := range s.WireScopes[:s.NbWires-s.NbPublicWires-s.NbSecretWires] {
+		res[scope].NbInternalWires++
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Scope < res[j].Scope
+	})
+	return res, nil
+}
+
+// Dump writes the constraints of r1cs to w, one per line, with their scope:
+//
+//	#12 transfer[3]/merkle: (X + 2*w3) * (1) == (Y)
+//
+// The internal wires are named w<index>, and the terms on the constant wire are written as their coefficient.
+func Dump(w io.Writer, r1cs R1CS) error {
+	s, err := untyped(r1cs)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for i, r := range s.Constraints {
+		scope := s.ConstraintScope(i)
+		if scope != "" {
+			scope = " " + scope
+		}
+		if _, err := fmt.Fprintf(bw, "#%d%s: (%s) * (%s) == (%s)\n", i, scope,
+			s.formatLinearExpression(r.L), s.formatLinearExpression(r.R), s.formatLinearExpression(r.O)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// formatLinearExpression returns l as a sum of coeff*name terms
+func (s *UntypedR1CS) formatLinearExpression(l r1c.LinearExpression) string {
+	var sb strings.Builder
+	for i, t := range l {
+		if i > 0 {
+			sb.WriteString(" + ")
+		}
+		name := s.wireName(t.ConstraintID())
+		var coeff string
+		switch t.CoeffValue() {
+		case -1, 0, 1, 2:
+			coeff = fmt.Sprint(t.CoeffValue())
+		default:
+			coeff = s.Coefficients[t.CoeffID()].String()
+		}
+		switch {
+		case name == backend.OneWire:
+			sb.WriteString(coeff)
+		case coeff == "1":
+			sb.WriteString(name)
+		default:
+			sb.WriteString(coeff + "*" + name)
+		}
+	}
+	if len(l) == 0 {
+		sb.WriteString("0")
+	}
+	return sb.String()
+}
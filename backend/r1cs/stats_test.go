@@ -0,0 +1,97 @@
+package r1cs_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+type scopedCircuit struct {
+	X [2]frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (circuit *scopedCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	for i := 0; i < len(circuit.X); i++ {
+		cs.PushScope(fmt.Sprintf("square[%d]", i))
+		square := cs.Mul(circuit.X[i], circuit.X[i])
+		cs.Scope("check", func() {
+			cs.AssertIsEqual(square, circuit.Y)
+		})
+		cs.PopScope()
+	}
+	cs.AssertIsEqual(circuit.X[0], circuit.X[1])
+	return nil
+}
+
+func TestScopes(t *testing.T) {
+	var circuit scopedCircuit
+	r, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []r1cs.ScopeStats{
+		{Scope: "", NbConstraints: 1},
+		{Scope: "square[0]", NbConstraints: 1, NbInternalWires: 1},
+		{Scope: "square[0]/check", NbConstraints: 1},
+		{Scope: "square[1]", NbConstraints: 1, NbInternalWires: 1},
+		{Scope: "square[1]/check", NbConstraints: 1},
+	}
+	stats, err := r1cs.Stats(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stats, expected) {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+
+	var dump bytes.Buffer
+	if err := r1cs.Dump(&dump, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dump.String(), "#1 square[1]: (X_1) * (X_1) == (w1)\n") ||
+		!strings.Contains(dump.String(), "#3 square[1]/check: (w1) * (1) == (Y)\n") ||
+		!strings.Contains(dump.String(), "#4: (X_0) * (1) == (X_1)\n") {
+		t.Fatal("unexpected dump", dump.String())
+	}
+
+	// the failing assertion is reported with its scope, by the solver and the test engine
+	var witness scopedCircuit
+	witness.X[0].Assign(3)
+	witness.X[1].Assign(-3)
+	witness.Y.Assign(10)
+	assignment, err := frontend.ParseWitness(&witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.IsSolved(assignment)
+	if !errors.Is(err, backend.ErrUnsatisfiedConstraint) || !strings.HasPrefix(err.Error(), `in scope "square[0]/check": `) {
+		t.Fatal("unexpected solver error", err)
+	}
+	err = frontend.Execute(gurvy.BN256, &scopedCircuit{}, &witness)
+	if !errors.Is(err, backend.ErrUnsatisfiedConstraint) || !strings.HasPrefix(err.Error(), `in scope "square[0]/check": `) {
+		t.Fatal("unexpected engine error", err)
+	}
+
+	// without scopes, nothing is recorded
+	r, err = frontend.Compile(gurvy.BN256, &lintCircuit{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err = r1cs.Stats(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].NbConstraints != r.GetNbConstraints() {
+		t.Fatalf("unexpected stats %v", stats)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package r1cs
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
+	backend_bls381 "github.com/consensys/gnark/internal/backend/bls381"
+	backend_bn256 "github.com/consensys/gnark/internal/backend/bn256"
+	backend_bw761 "github.com/consensys/gnark/internal/backend/bw761"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+var errUntypedR1CS = errors.New("the R1CS must be compiled for a curve")
+
+// system is the big.Int view of a curve typed R1CS, used by the analysis tools
+type system struct {
+	*UntypedR1CS
+	modulus *big.Int
+	solve   func(assignment map[string]interface{}) ([]big.Int, error)
+}
+
+func newSystem(r1cs R1CS) (*system, error) {
+	switch r := r1cs.(type) {
+	case *backend_bn256.R1CS:
+		return &system{fromBN256(r), fr_bn256.Modulus(), func(assignment map[string]interface{}) ([]big.Int, error) {
+			return solveBN256(r, assignment)
+		}}, nil
+	case *backend_bls377.R1CS:
+		return &system{fromBLS377(r), fr_bls377.Modulus(), func(assignment map[string]interface{}) ([]big.Int, error) {
+			return solveBLS377(r, assignment)
+		}}, nil
+	case *backend_bls381.R1CS:
+		return &system{fromBLS381(r), fr_bls381.Modulus(), func(assignment map[string]interface{}) ([]big.Int, error) {
+			return solveBLS381(r, assignment)
+		}}, nil
+	case *backend_bw761.R1CS:
+		return &system{fromBW761(r), fr_bw761.Modulus(), func(assignment map[string]interface{}) ([]big.Int, error) {
+			return solveBW761(r, assignment)
+		}}, nil
+	default:
+		return nil, errUntypedR1CS
+	}
+}
+
+// untyped returns the big.Int representation of r1cs
+func untyped(r1cs R1CS) (*UntypedR1CS, error) {
+	if u, ok := r1cs.(*UntypedR1CS); ok {
+		return u, nil
+	}
+	s, err := newSystem(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	return s.UntypedR1CS, nil
+}
+
+// coefficient returns the coefficient of a term
+func (s *system) coefficient(t r1c.Term) *big.Int {
+	switch t.CoeffValue() {
+	case -1:
+		return big.NewInt(-1)
+	case 0:
+		return big.NewInt(0)
+	case 1:
+		return big.NewInt(1)
+	case 2:
+		return big.NewInt(2)
+	default:
+		return &s.Coefficients[t.CoeffID()]
+	}
+}
+
+// evaluate returns the value of l, skipping the term at index skip (-1 to skip none)
+func (s *system) evaluate(l r1c.LinearExpression, values []big.Int, skip int) *big.Int {
+	var res, tmp big.Int
+	for i, t := range l {
+		if i == skip {
+			continue
+		}
+		tmp.Mul(s.coefficient(t), &values[t.ConstraintID()])
+		res.Add(&res, &tmp)
+	}
+	return res.Mod(&res, s.modulus)
+}
+
+// isSatisfied returns true if L * R == O for the i-th constraint
+func (s *system) isSatisfied(i int, values []big.Int) bool {
+	r := &s.Constraints[i]
+	var check big.Int
+	check.Mul(s.evaluate(r.L, values, -1), s.evaluate(r.R, values, -1)).
+		Sub(&check, s.evaluate(r.O, values, -1)).
+		Mod(&check, s.modulus)
+	return check.Sign() == 0
+}
+
+// wireConstraints returns, for each wire, the indexes of the constraints it appears in
+func (s *UntypedR1CS) wireConstraints() [][]int {
+	res := make([][]int, s.NbWires)
+	for i, r := range s.Constraints {
+		for _, l := range []r1c.LinearExpression{r.L, r.R, r.O} {
+			for _, t := range l {
+				cID := t.ConstraintID()
+				if n := len(res[cID]); n == 0 || res[cID][n-1] != i {
+					res[cID] = append(res[cID], i)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// wireName returns the name of an input wire, or w<index> for an internal wire
+func (s *UntypedR1CS) wireName(wire int) string {
+	publicOffset := s.NbWires - s.NbPublicWires
+	secretOffset := publicOffset - s.NbSecretWires
+	switch {
+	case wire >= publicOffset:
+		return s.PublicWires[wire-publicOffset]
+	case wire >= secretOffset:
+		return s.SecretWires[wire-secretOffset]
+	default:
+		return "w" + strconv.Itoa(wire)
+	}
+}
+
+// wireOrigin describes where the value of a wire comes from
+func (s *UntypedR1CS) wireOrigin(wire int, constraints [][]int) string {
+	publicOffset := s.NbWires - s.NbPublicWires
+	secretOffset := publicOffset - s.NbSecretWires
+	switch {
+	case wire >= publicOffset:
+		return fmt.Sprintf("public input %q", s.PublicWires[wire-publicOffset])
+	case wire >= secretOffset:
+		return fmt.Sprintf("secret input %q", s.SecretWires[wire-secretOffset])
+	}
+	for _, h := range s.Hints {
+		for _, t := range h.Outputs {
+			if t.ConstraintID() == wire {
+				if scope := s.WireScope(wire); scope != "" {
+					return fmt.Sprintf("output of hint %q in scope %q", h.ID, scope)
+				}
+				return fmt.Sprintf("output of hint %q", h.ID)
+			}
+		}
+	}
+	res := "internal wire"
+	if len(constraints[wire]) != 0 {
+		res = fmt.Sprintf("internal wire of constraint #%d", constraints[wire][0])
+	}
+	if scope := s.WireScope(wire); scope != "" {
+		res += fmt.Sprintf(" in scope %q", scope)
+	}
+	return res
+}
@@ -0,0 +1,244 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package r1cs
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+)
+
+// nbMutations is the number of alternative values tried for each wire
+const nbMutations = 8
+
+// UnderconstrainedWire is an internal or secret wire which can take another value,
+// without breaking any constraint nor changing the public inputs
+type UnderconstrainedWire struct {
+	ID          int    // wire index, in [internal | secret | public] order
+	Origin      string // secret input, hint or constraint the wire comes from
+	Visibility  backend.Visibility
+	Value       big.Int // value of the wire in the provided witness
+	Alternative big.Int // other value of the wire, which still satisfies every constraint
+}
+
+func (w *UnderconstrainedWire) String() string {
+	return fmt.Sprintf("wire %d (%s) is not uniquely determined: %s and %s satisfy the constraints",
+		w.ID, w.Origin, w.Value.String(), w.Alternative.String())
+}
+
+// FindUnderconstrained solves r1cs with assignment, and searches for alternative values of each internal
+// and secret wire which satisfy every r1c.R1C with the same public inputs.
+//
+// A wire is mutated, then the constraints it breaks are repaired one at a time, by solving for another
+// wire which isn't fixed yet. The search is randomized and not exhaustive: a wire which is not reported
+// may still be underconstrained. Secret inputs are reported if another witness exists for the same public
+// inputs, which may be expected (e.g. if several secret values are valid). It runs in O(nbWires * nbConstraints) and is meant for test circuits.
+func FindUnderconstrained(r1cs R1CS, assignment map[string]interface{}) ([]UnderconstrainedWire, error) {
+	s, err := newSystem(r1cs)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.solve(assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	p := propagator{
+		system:      s,
+		values:      values,
+		current:     make([]big.Int, len(values)),
+		pinned:      make([]int, len(values)),
+		constraints: s.wireConstraints(),
+		rng:         rand.New(rand.NewSource(1)),
+	}
+	for i := 0; i < len(values); i++ {
+		p.current[i].Set(&values[i])
+	}
+
+	var res []UnderconstrainedWire
+	nbPrivateWires := s.NbWires - s.NbPublicWires
+	for wire := 0; wire < nbPrivateWires; wire++ {
+		for i := 0; i < nbMutations; i++ {
+			alternative := p.mutation(wire, i)
+			if !p.try(wire, alternative) {
+				continue
+			}
+			w := UnderconstrainedWire{
+				ID:         wire,
+				Origin:     s.wireOrigin(wire, p.constraints),
+				Visibility: backend.Internal,
+			}
+			if wire >= nbPrivateWires-s.NbSecretWires {
+				w.Visibility = backend.Secret
+			}
+			w.Value.Set(&values[wire])
+			w.Alternative.Set(alternative)
+			res = append(res, w)
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// propagator tries alternative wire assignments
+type propagator struct {
+	*system
+	values      []big.Int // solution of the R1CS
+	current     []big.Int // assignment being tried, equal to values between two tries
+	pinned      []int     // pinned[wire] == trial if the wire can't be modified in this trial
+	trial       int
+	constraints [][]int // constraints each wire appears in
+	rng         *rand.Rand
+}
+
+// mutation returns the i-th alternative value tried for wire
+func (p *propagator) mutation(wire, i int) *big.Int {
+	res := new(big.Int)
+	switch i {
+	case 0:
+		res.Add(&p.values[wire], big.NewInt(1))
+	case 1:
+		res.Sub(&p.values[wire], big.NewInt(1))
+	default:
+		res.Rand(p.rng, p.modulus)
+	}
+	return res.Mod(res, p.modulus)
+}
+
+// try sets wire to value, and propagates the change through the constraints. It returns true if
+// the resulting assignment satisfies every constraint.
+func (p *propagator) try(wire int, value *big.Int) bool {
+	p.trial++
+	changed := []int{wire}
+	defer func() {
+		for _, w := range changed {
+			p.current[w].Set(&p.values[w])
+		}
+	}()
+
+	p.current[wire].Set(value)
+	p.pinned[wire] = p.trial
+	queue := append([]int(nil), p.constraints[wire]...)
+
+	// each repair pins a new wire, so this terminates
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if p.isSatisfied(c, p.current) {
+			continue
+		}
+		repaired := -1
+		candidates := p.candidates(c)
+		p.rng.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+		for _, candidate := range candidates {
+			if p.repair(c, candidate) {
+				repaired = candidate
+				break
+			}
+		}
+		if repaired == -1 {
+			return false
+		}
+		p.pinned[repaired] = p.trial
+		changed = append(changed, repaired)
+		queue = append(queue, p.constraints[repaired]...)
+	}
+
+	return true
+}
+
+// candidates returns the wires of constraint c which can be isolated: they are not pinned, not public,
+// and appear in a single term
+func (p *propagator) candidates(c int) []int {
+	r := &p.Constraints[c]
+	count := make(map[int]int)
+	var res []int
+	for _, l := range []r1c.LinearExpression{r.L, r.R, r.O} {
+		for _, t := range l {
+			cID := t.ConstraintID()
+			if t.ConstraintVisibility() == backend.Public || p.pinned[cID] == p.trial {
+				continue
+			}
+			if count[cID] == 0 {
+				res = append(res, cID)
+			}
+			count[cID]++
+		}
+	}
+	j := 0
+	for _, cID := range res {
+		if count[cID] == 1 {
+			res[j] = cID
+			j++
+		}
+	}
+	return res[:j]
+}
+
+// repair sets wire such that constraint c is satisfied, and returns false if it can't
+func (p *propagator) repair(c, wire int) bool {
+	r := &p.Constraints[c]
+	for loc, l := range []r1c.LinearExpression{r.L, r.R, r.O} {
+		for i, t := range l {
+			if t.ConstraintID() != wire {
+				continue
+			}
+			var coeff big.Int
+			coeff.Mod(p.coefficient(t), p.modulus)
+			if coeff.Sign() == 0 {
+				return false
+			}
+
+			// value of the linear expression without the wire, and of the two others
+			skip := [3]int{-1, -1, -1}
+			skip[loc] = i
+			a := p.evaluate(r.L, p.current, skip[0])
+			b := p.evaluate(r.R, p.current, skip[1])
+			o := p.evaluate(r.O, p.current, skip[2])
+
+			// solve coeff * wire + rest == target
+			var target, rest big.Int
+			switch loc {
+			case 0:
+				if b.Sign() == 0 {
+					return false
+				}
+				target.ModInverse(b, p.modulus).Mul(&target, o)
+				rest.Set(a)
+			case 1:
+				if a.Sign() == 0 {
+					return false
+				}
+				target.ModInverse(a, p.modulus).Mul(&target, o)
+				rest.Set(b)
+			case 2:
+				target.Mul(a, b)
+				rest.Set(o)
+			}
+			value := &p.current[wire]
+			value.Sub(&target, &rest).
+				Mul(value, coeff.ModInverse(&coeff, p.modulus)).
+				Mod(value, p.modulus)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package r1cs_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+// bitsCircuit decomposes X on 2 bits
+type bitsCircuit struct {
+	assertBits bool
+	B0, B1     frontend.Variable
+	X          frontend.Variable `gnark:",public"`
+}
+
+func (circuit *bitsCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	if circuit.assertBits {
+		cs.AssertIsBoolean(circuit.B0)
+		cs.AssertIsBoolean(circuit.B1)
+	}
+	cs.AssertIsEqual(circuit.X, cs.Add(circuit.B0, cs.Mul(circuit.B1, 2)))
+	return nil
+}
+
+// inverseCircuit computes the inverse of X with a hint, which also outputs an unused square
+type inverseCircuit struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func init() {
+	backend.RegisterHint("r1cs_test.inverseAndSquare", func(inputs, outputs []big.Int) error {
+		outputs[0].ModInverse(&inputs[0], fr.Modulus())
+		outputs[1].Mul(&inputs[0], &inputs[0])
+		return nil
+	})
+}
+
+func (circuit *inverseCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	res := cs.NewHint("r1cs_test.inverseAndSquare", 2, circuit.X)
+	cs.AssertIsEqual(cs.Mul(circuit.X, res[0]), 1)
+	cs.AssertIsEqual(cs.Add(res[0], circuit.X), circuit.Y)
+	return nil
+}
+
+func findUnderconstrained(t *testing.T, circuit, witness frontend.Circuit) []r1cs.UnderconstrainedWire {
+	t.Helper()
+	r, err := frontend.Compile(gurvy.BN256, circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := frontend.ParseWitness(witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := r1cs.FindUnderconstrained(r, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestFindUnderconstrained(t *testing.T) {
+	var witness bitsCircuit
+	witness.B0.Assign(0)
+	witness.B1.Assign(1)
+	witness.X.Assign(2)
+
+	if res := findUnderconstrained(t, &bitsCircuit{assertBits: true}, &witness); len(res) != 0 {
+		t.Fatalf("expected no underconstrained wire, got %v", res)
+	}
+
+	// without the boolean assertions, (B0, B1) = (2, 0) is another solution
+	res := findUnderconstrained(t, &bitsCircuit{}, &witness)
+	secrets := make(map[string]bool)
+	for i := range res {
+		if res[i].Visibility == backend.Secret {
+			secrets[res[i].Origin] = true
+		}
+	}
+	if !secrets[`secret input "B0"`] || !secrets[`secret input "B1"`] {
+		t.Fatalf("B0 and B1 should be underconstrained, got %v", res)
+	}
+
+	// the square output by the hint is never constrained
+	var inverse inverseCircuit
+	inverse.X.Assign(3)
+	inverse.Y.Assign(new(big.Int).Add(new(big.Int).ModInverse(big.NewInt(3), fr.Modulus()), big.NewInt(3)))
+	res = findUnderconstrained(t, &inverseCircuit{}, &inverse)
+	if len(res) != 1 || !strings.Contains(res[0].String(), `output of hint "r1cs_test.inverseAndSquare"`) {
+		t.Fatalf("the second output of the hint should be underconstrained, got %v", res)
+	}
+	if res[0].Value.Cmp(big.NewInt(9)) != 0 {
+		t.Fatal("unexpected witness value", res[0].Value.String())
+	}
+}
@@ -40,6 +40,12 @@ type UntypedR1CS struct {
 	NbCOConstraints int // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []r1c.R1C
 	Coefficients    []big.Int
+	Hints           []r1c.Hint // hints run by the solver, ordered by position
+
+	// Scopes (cf frontend.ConstraintSystem.Scope)
+	Scopes           []string // scope names, the first one is the root scope ""
+	ConstraintScopes []int    // index in Scopes of each constraint, nil if no scope was used
+	WireScopes       []int    // index in Scopes of each wire, nil if no scope was used
 }
 
 // GetNbConstraints returns the number of constraints
@@ -57,13 +63,29 @@ func (r1cs *UntypedR1CS) GetNbCoefficients() int {
 	return len(r1cs.Coefficients)
 }
 
+// ConstraintScope returns the scope of the i-th constraint, "" if it is in the root scope
+func (r1cs *UntypedR1CS) ConstraintScope(i int) string {
+	if r1cs.ConstraintScopes == nil {
+		return ""
+	}
+	return r1cs.Scopes[r1cs.ConstraintScopes[i]]
+}
+
+// WireScope returns the scope of the i-th wire, "" if it is in the root scope
+func (r1cs *UntypedR1CS) WireScope(i int) string {
+	if r1cs.WireScopes == nil {
+		return ""
+	}
+	return r1cs.Scopes[r1cs.WireScopes[i]]
+}
+
 // GetCurveID returns gurvy.UNKNOWN as this R1CS is Untyped and have big.Int coefficients
 func (r1cs *UntypedR1CS) GetCurveID() gurvy.ID {
 	return gurvy.UNKNOWN
 }
 
 // IsSolved call will panic as we can't solve a UntypedR1CS
-func (r1cs *UntypedR1CS) IsSolved(solution map[string]interface{}) error {
+func (r1cs *UntypedR1CS) IsSolved(solution map[string]interface{}, opts ...backend.SolveOption) error {
 	panic("not implemented")
 }
 
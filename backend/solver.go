@@ -0,0 +1,80 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// UnsatisfiedConstraintError is returned by the solvers when a constraint L * R == O is not satisfied.
+// It wraps ErrUnsatisfiedConstraint.
+type UnsatisfiedConstraintError struct {
+	Constraint int      // index of the constraint in the R1CS, -1 if unknown
+	L, R, O    big.Int  // values of the linear expressions of the constraint
+	Scope      string   // scope of the constraint (cf frontend.ConstraintSystem.Scope)
+	DebugInfo  string   // assertion, with the values of its wires (empty for a computational constraint)
+	Stack      []string // call stack of the instruction which added the constraint, from the innermost frame
+}
+
+func (err *UnsatisfiedConstraintError) Error() string {
+	var sb strings.Builder
+	if err.Scope != "" {
+		fmt.Fprintf(&sb, "in scope %q: ", err.Scope)
+	}
+	sb.WriteString(ErrUnsatisfiedConstraint.Error())
+	if err.DebugInfo != "" {
+		sb.WriteString(": " + err.DebugInfo)
+	} else {
+		fmt.Fprintf(&sb, ": %s * %s != %s", err.L.String(), err.R.String(), err.O.String())
+	}
+	for _, frame := range err.Stack {
+		sb.WriteString("\n" + frame)
+	}
+	return sb.String()
+}
+
+func (err *UnsatisfiedConstraintError) Unwrap() error {
+	return ErrUnsatisfiedConstraint
+}
+
+// SolveOption configures the solver (cf R1CS.IsSolved, groth16.Prove and frontend.Execute)
+type SolveOption func(*SolveConfig)
+
+// SolveConfig is the configuration of the solver, set by SolveOptions
+type SolveConfig struct {
+	LogWriter io.Writer // output of the logs (cf frontend.ConstraintSystem.Println), os.Stdout by default
+}
+
+// WithLogWriter sets the output of the logs printed while solving (cf frontend.ConstraintSystem.Println)
+func WithLogWriter(w io.Writer) SolveOption {
+	return func(cfg *SolveConfig) {
+		cfg.LogWriter = w
+	}
+}
+
+// NewSolveConfig returns the configuration set by opts
+func NewSolveConfig(opts ...SolveOption) SolveConfig {
+	cfg := SolveConfig{LogWriter: os.Stdout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/spf13/cobra"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:     "lint [circuit.r1cs]",
+	Short:   "reports unused secret inputs and internal wires, and constant constraints of a given circuit",
+	Run:     cmdLint,
+	Version: Version,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
+
+func cmdLint(cmd *cobra.Command, args []string) {
+	if len(args) < 1 {
+		fmt.Println("missing circuit path -- gnark lint -h for help")
+		os.Exit(-1)
+	}
+	circuitPath := filepath.Clean(args[0])
+
+	// load circuit
+	if !fileExists(circuitPath) {
+		fmt.Println("error:", errNotFound)
+		os.Exit(-1)
+	}
+
+	// read R1CS
+	circuit, err := r1cs.Read(circuitPath)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+
+	fmt.Printf("%-30s %-30s %-d constraints\n", "loaded circuit", circuitPath, circuit.GetNbConstraints())
+	issues, err := r1cs.Lint(circuit)
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(-1)
+	}
+	for i := 0; i < len(issues); i++ {
+		fmt.Println(issues[i].Error())
+	}
+	fmt.Printf("%-30s %-d\n", "lint issues", len(issues))
+	if len(issues) != 0 {
+		os.Exit(-1)
+	}
+}
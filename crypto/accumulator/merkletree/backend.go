@@ -0,0 +1,177 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkletree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrNotFound is returned by a Backend when a key is not stored
+var ErrNotFound = errors.New("key not found")
+
+// Backend key-value storage of the nodes of a Store
+type Backend interface {
+	// Get returns the value stored at key, or ErrNotFound
+	Get(key []byte) ([]byte, error)
+
+	// Put stores value at key, replacing the previous value if any
+	Put(key, value []byte) error
+}
+
+// MemoryBackend in-memory Backend
+type MemoryBackend struct {
+	lock   sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemoryBackend returns an empty in-memory Backend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{values: make(map[string][]byte)}
+}
+
+// Get implements Backend
+func (b *MemoryBackend) Get(key []byte) ([]byte, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	v, ok := b.values[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte{}, v...), nil
+}
+
+// Put implements Backend
+func (b *MemoryBackend) Put(key, value []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.values[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+// FileBackend Backend stored in an append-only file. Each Put appends a record
+// [len(key) uint32 | len(value) uint32 | key | value] (big endian) to the file,
+// and the offsets of the values are indexed in memory when the file is opened, the last record
+// of a key giving its value.
+type FileBackend struct {
+	lock    sync.RWMutex
+	file    *os.File
+	size    int64
+	offsets map[string]record
+}
+
+// record position of a value in the file
+type record struct {
+	offset int64
+	length uint32
+}
+
+const recordHeaderSize = 8
+
+// OpenFileBackend opens the file at path, creating it if needed, and indexes its records.
+// A truncated record at the end of the file (interrupted write) is discarded.
+func OpenFileBackend(path string) (*FileBackend, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	b := &FileBackend{file: file, offsets: make(map[string]record)}
+
+	reader := bufio.NewReader(file)
+	var header [recordHeaderSize]byte
+	for {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			file.Close()
+			return nil, err
+		}
+		keyLen := binary.BigEndian.Uint32(header[:4])
+		valueLen := binary.BigEndian.Uint32(header[4:])
+		key := make([]byte, keyLen)
+		if _, err := io.ReadFull(reader, key); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			file.Close()
+			return nil, err
+		}
+		if _, err := reader.Discard(int(valueLen)); err != nil {
+			if err == io.EOF {
+				break
+			}
+			file.Close()
+			return nil, err
+		}
+		b.offsets[string(key)] = record{offset: b.size + recordHeaderSize + int64(keyLen), length: valueLen}
+		b.size += recordHeaderSize + int64(keyLen) + int64(valueLen)
+	}
+
+	// drops the truncated record, if any
+	if err := file.Truncate(b.size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return b, nil
+}
+
+// Get implements Backend
+func (b *FileBackend) Get(key []byte) ([]byte, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	r, ok := b.offsets[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	res := make([]byte, r.length)
+	if _, err := b.file.ReadAt(res, r.offset); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Put implements Backend
+func (b *FileBackend) Put(key, value []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	buf := make([]byte, recordHeaderSize+len(key)+len(value))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(key)))
+	binary.BigEndian.PutUint32(buf[4:recordHeaderSize], uint32(len(value)))
+	copy(buf[recordHeaderSize:], key)
+	copy(buf[recordHeaderSize+len(key):], value)
+	if _, err := b.file.WriteAt(buf, b.size); err != nil {
+		return err
+	}
+	b.offsets[string(key)] = record{offset: b.size + recordHeaderSize + int64(len(key)), length: uint32(len(value))}
+	b.size += int64(len(buf))
+	return nil
+}
+
+// Sync commits the content of the file to stable storage
+func (b *FileBackend) Sync() error {
+	return b.file.Sync()
+}
+
+// Close closes the file
+func (b *FileBackend) Close() error {
+	return b.file.Close()
+}
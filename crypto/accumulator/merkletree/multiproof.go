@@ -0,0 +1,163 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"sort"
+)
+
+var (
+	errNoLeavesToProve = errors.New("at least one leaf must be proven")
+	errDuplicateIndex  = errors.New("the indexes of the leaves to prove must be distinct")
+	errIndexOutOfRange = errors.New("the index of a leaf to prove is out of range")
+)
+
+// NodeIndex position of a node in a tree: the node at Height 0 are the leaves
+type NodeIndex struct {
+	Height int
+	Index  uint64
+}
+
+// MultiProofIndexes returns the positions of the nodes of the multiproof of the leaves at indexes
+// in a tree of the given depth, in the order of the multiproof
+func MultiProofIndexes(depth int, indexes []uint64) ([]NodeIndex, error) {
+
+	if len(indexes) == 0 {
+		return nil, errNoLeavesToProve
+	}
+
+	// current nodes which are computed from the leaves, sorted
+	known := append([]uint64{}, indexes...)
+	sort.Slice(known, func(i, j int) bool { return known[i] < known[j] })
+	for i := range known {
+		if known[i] >= uint64(1)<<uint(depth) {
+			return nil, errIndexOutOfRange
+		}
+		if i > 0 && known[i] == known[i-1] {
+			return nil, errDuplicateIndex
+		}
+	}
+
+	var res []NodeIndex
+	for height := 0; height < depth; height++ {
+		var parents []uint64
+		for i := 0; i < len(known); i++ {
+			if known[i]%2 == 0 && i+1 < len(known) && known[i+1] == known[i]+1 {
+				// both children are known
+				i++
+			} else {
+				res = append(res, NodeIndex{height, known[i] ^ 1})
+			}
+			parents = append(parents, known[i]/2)
+		}
+		known = parents
+	}
+
+	return res, nil
+}
+
+// BuildMultiProof returns the root of the fixed depth tree built from leaves (cf NewFixedDepth),
+// and the multiproof of the leaves at indexes.
+//
+// A multiproof proves several leaves at once. It contains the nodes which are needed to compute the root
+// from the leaves, and can't be computed from them: a node shared by several paths appears once, and
+// the siblings computed from other leaves are skipped. The nodes are ordered by height, then by index
+// (cf MultiProofIndexes).
+func BuildMultiProof(h hash.Hash, leaves [][]byte, depth int, indexes []uint64) (root []byte, proof [][]byte, err error) {
+
+	if uint64(len(leaves)) > uint64(1)<<uint(depth) {
+		return nil, nil, errors.New("too many leaves for the depth of the tree")
+	}
+	positions, err := MultiProofIndexes(depth, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// nodes of the tree, level by level, the missing nodes being padding subtrees
+	empty := make([][]byte, depth+1)
+	empty[0] = leafSum(h, make([]byte, h.Size()))
+	for i := 1; i <= depth; i++ {
+		empty[i] = nodeSum(h, empty[i-1], empty[i-1])
+	}
+	levels := make([][][]byte, depth+1)
+	for _, leaf := range leaves {
+		levels[0] = append(levels[0], leafSum(h, leaf))
+	}
+	node := func(height int, index uint64) []byte {
+		if index < uint64(len(levels[height])) {
+			return levels[height][index]
+		}
+		return empty[height]
+	}
+	for height := 1; height <= depth; height++ {
+		for i := uint64(0); i < (uint64(len(levels[height-1]))+1)/2; i++ {
+			levels[height] = append(levels[height], nodeSum(h, node(height-1, 2*i), node(height-1, 2*i+1)))
+		}
+	}
+
+	proof = make([][]byte, len(positions))
+	for i, p := range positions {
+		proof[i] = node(p.Height, p.Index)
+	}
+
+	return node(depth, 0), proof, nil
+}
+
+// VerifyMultiProof returns true if the leaves are at indexes in the fixed depth tree of root
+// merkleRoot, proof being their multiproof (cf BuildMultiProof)
+func VerifyMultiProof(h hash.Hash, merkleRoot []byte, depth int, indexes []uint64, leaves, proof [][]byte) bool {
+	if len(indexes) != len(leaves) {
+		return false
+	}
+	positions, err := MultiProofIndexes(depth, indexes)
+	if err != nil || len(positions) != len(proof) {
+		return false
+	}
+
+	// nodes computed so far, by index, at the current height
+	nodes := make(map[uint64][]byte)
+	for i, index := range indexes {
+		nodes[index] = leafSum(h, leaves[i])
+	}
+	for i, p := range positions {
+		if p.Height == 0 {
+			nodes[p.Index] = proof[i]
+		}
+	}
+	next := 0
+	for next < len(positions) && positions[next].Height == 0 {
+		next++
+	}
+
+	for height := 1; height <= depth; height++ {
+		parents := make(map[uint64][]byte)
+		for index := range nodes {
+			if _, ok := parents[index/2]; !ok {
+				parents[index/2] = nodeSum(h, nodes[index&^1], nodes[index|1])
+			}
+		}
+		for ; next < len(positions) && positions[next].Height == height; next++ {
+			parents[positions[next].Index] = proof[next]
+		}
+		nodes = parents
+	}
+
+	return bytes.Equal(nodes[0], merkleRoot)
+}
@@ -70,3 +70,26 @@ func BuildReaderProof(r io.Reader, h hash.Hash, segmentSize int, index uint64) (
 	}
 	return
 }
+
+// BuildFixedDepthProof returns a proof that leaves[index] is in the fixed depth merkle tree
+// (cf NewFixedDepth) built from leaves. The proof set has depth+1 elements: the leaf followed
+// by its depth siblings.
+func BuildFixedDepthProof(h hash.Hash, leaves [][]byte, depth int, index uint64) (root []byte, proofSet [][]byte, err error) {
+	if uint64(len(leaves)) > uint64(1)<<uint(depth) {
+		err = errors.New("too many leaves for the depth of the tree")
+		return
+	}
+	tree := NewFixedDepth(h, depth)
+	if err = tree.SetIndex(index); err != nil {
+		panic(err) // unreachable, the tree is empty
+	}
+	for _, leaf := range leaves {
+		tree.Push(leaf)
+	}
+	root, proofSet, _, _ = tree.Prove()
+	if len(proofSet) == 0 {
+		err = errors.New("index was not reached while creating proof")
+		return
+	}
+	return
+}
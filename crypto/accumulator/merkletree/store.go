@@ -0,0 +1,235 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkletree
+
+import (
+	"errors"
+	"hash"
+)
+
+var (
+	errStoreDepth     = errors.New("the depth of the tree must be between 1 and 64")
+	errDepthMismatch  = errors.New("the backend stores a tree of a different depth")
+	errUnknownRoot    = errors.New("the root is not stored in the backend")
+	errCorruptedNode  = errors.New("a node stored in the backend has a wrong size")
+	errLeafOutOfRange = errors.New("the index of the leaf is out of range")
+	headKey           = []byte("head")
+)
+
+// Store Merkle tree of fixed depth whose nodes are stored in a Backend. It is the tree of NewFixedDepth,
+// the leaves which are never updated being padding leaves (zero bytes). Update, Proof and Root cost O(depth)
+// hashes and backend accesses, whatever the number of leaves.
+//
+// The nodes are stored by hash and never modified: an update stores the new nodes of the path of the leaf,
+// so that every root computed so far stays a readable version of the tree (cf Snapshot).
+// A Store is not safe for concurrent use, since it uses the hash function.
+type Store struct {
+	h     hash.Hash
+	depth int
+	db    Backend
+	root  []byte
+	empty [][]byte // empty[i] root of a subtree of height i with padding leaves
+}
+
+// NewStore returns a Store of the given depth backed by db. If a root was committed in db (cf Commit),
+// the Store starts at this root, otherwise it starts with the tree whose leaves are all padding leaves.
+func NewStore(h hash.Hash, depth int, db Backend) (*Store, error) {
+	if depth < 1 || depth > 64 {
+		return nil, errStoreDepth
+	}
+	s := &Store{h: h, depth: depth, db: db}
+
+	s.empty = make([][]byte, depth+1)
+	padding := make([]byte, h.Size())
+	s.empty[0] = leafSum(h, padding)
+	for i := 1; i <= depth; i++ {
+		s.empty[i] = nodeSum(h, s.empty[i-1], s.empty[i-1])
+	}
+	s.root = s.empty[depth]
+
+	head, err := db.Get(headKey)
+	if err == nil {
+		if len(head) != 1+h.Size() {
+			return nil, errCorruptedNode
+		}
+		if int(head[0]) != depth {
+			return nil, errDepthMismatch
+		}
+		s.root = head[1:]
+		return s, nil
+	}
+	if err != ErrNotFound {
+		return nil, err
+	}
+
+	// the padding subtrees are stored, so that reading them is the same as reading any subtree
+	// they are stored from the leaf up, so they are all in db if the padding root is
+	_, err = db.Get(nodeKey(depth, s.empty[depth]))
+	if err == nil {
+		return s, nil
+	}
+	if err != ErrNotFound {
+		return nil, err
+	}
+	if err := db.Put(nodeKey(0, s.empty[0]), padding); err != nil {
+		return nil, err
+	}
+	for i := 1; i <= depth; i++ {
+		if err := db.Put(nodeKey(i, s.empty[i]), append(append([]byte{}, s.empty[i-1]...), s.empty[i-1]...)); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// nodeKey returns the key of the node of the given height and hash in the backend
+func nodeKey(height int, sum []byte) []byte {
+	return append([]byte{byte(height)}, sum...)
+}
+
+// Depth returns the depth of the tree
+func (s *Store) Depth() int {
+	return s.depth
+}
+
+// Root returns the root of the current version of the tree
+func (s *Store) Root() []byte {
+	return append([]byte{}, s.root...)
+}
+
+// Commit stores the current root as the head of the backend, from which NewStore starts
+func (s *Store) Commit() error {
+	return s.db.Put(headKey, append([]byte{byte(s.depth)}, s.root...))
+}
+
+// Snapshot returns a Store at the version of the tree of the given root, which shares the backend of s.
+// The updates of the snapshot and of s don't interfere: they create different versions.
+func (s *Store) Snapshot(root []byte) (*Store, error) {
+	if _, err := s.db.Get(nodeKey(s.depth, root)); err != nil {
+		if err == ErrNotFound {
+			return nil, errUnknownRoot
+		}
+		return nil, err
+	}
+	res := *s
+	res.root = append([]byte{}, root...)
+	return &res, nil
+}
+
+// Leaf returns the leaf at index
+func (s *Store) Leaf(index uint64) ([]byte, error) {
+	proofSet, err := s.Proof(index)
+	if err != nil {
+		return nil, err
+	}
+	return proofSet[0], nil
+}
+
+// Proof returns the proof set of the leaf at index: the leaf, then the siblings of the nodes from
+// the leaf to the root, as Tree.Prove for a fixed depth tree (cf VerifyProof, with 2**depth leaves,
+// and std/accumulator/merkle.VerifyProofAtIndex).
+func (s *Store) Proof(index uint64) ([][]byte, error) {
+	leaf, path, err := s.path(index)
+	if err != nil {
+		return nil, err
+	}
+	leafData, err := s.db.Get(nodeKey(0, leaf))
+	if err != nil {
+		return nil, err
+	}
+	return append([][]byte{leafData}, path...), nil
+}
+
+// Update sets the leaf at index to leaf, and moves the Store to the resulting version of the tree
+func (s *Store) Update(index uint64, leaf []byte) error {
+	_, path, err := s.path(index)
+	if err != nil {
+		return err
+	}
+
+	sum := leafSum(s.h, leaf)
+	if err := s.db.Put(nodeKey(0, sum), leaf); err != nil {
+		return err
+	}
+	for height := 1; height <= s.depth; height++ {
+		var left, right []byte
+		if (index>>uint(height-1))&1 == 1 {
+			left, right = path[height-1], sum
+		} else {
+			left, right = sum, path[height-1]
+		}
+		sum = nodeSum(s.h, left, right)
+		if err := s.db.Put(nodeKey(height, sum), append(append([]byte{}, left...), right...)); err != nil {
+			return err
+		}
+	}
+	s.root = sum
+	return nil
+}
+
+// UpdateWithProof updates the leaf at index (cf Update), and returns the witness of the update
+// (cf std/accumulator/merkle.VerifyUpdate)
+func (s *Store) UpdateWithProof(index uint64, leaf []byte) (UpdateProof, error) {
+	oldRoot := s.Root()
+	proofSet, err := s.Proof(index)
+	if err != nil {
+		return UpdateProof{}, err
+	}
+	if err := s.Update(index, leaf); err != nil {
+		return UpdateProof{}, err
+	}
+	indexBits := make([]int, s.depth)
+	for i := range indexBits {
+		indexBits[i] = int((index >> uint(i)) & 1)
+	}
+	return UpdateProof{
+		OldRoot:   oldRoot,
+		NewRoot:   s.Root(),
+		OldLeaf:   proofSet[0],
+		NewLeaf:   append([]byte{}, leaf...),
+		Path:      proofSet[1:],
+		IndexBits: indexBits,
+	}, nil
+}
+
+// path returns the hash of the leaf at index, and the siblings of the nodes from the leaf to the root
+func (s *Store) path(index uint64) ([]byte, [][]byte, error) {
+	if s.depth < 64 && index>>uint(s.depth) != 0 {
+		return nil, nil, errLeafOutOfRange
+	}
+	size := s.h.Size()
+	path := make([][]byte, s.depth)
+	node := s.root
+	for height := s.depth; height > 0; height-- {
+		children, err := s.db.Get(nodeKey(height, node))
+		if err == ErrNotFound {
+			return nil, nil, errUnknownRoot
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(children) != 2*size {
+			return nil, nil, errCorruptedNode
+		}
+		if (index>>uint(height-1))&1 == 1 {
+			node, path[height-1] = children[size:], children[:size]
+		} else {
+			node, path[height-1] = children[:size], children[size:]
+		}
+	}
+	return node, path, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkletree
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+func randomLeaf() []byte {
+	var leaf fr.Element
+	leaf.SetRandom()
+	return leaf.Bytes()
+}
+
+// checkStore checks that s is the fixed depth tree of leaves, and that its proofs are valid
+func checkStore(t *testing.T, s *Store, leaves [][]byte) {
+	t.Helper()
+	h := bn256.NewMiMC("seed")
+	tree := NewFixedDepth(h, s.Depth())
+	for _, leaf := range leaves {
+		tree.Push(leaf)
+	}
+	if !bytes.Equal(s.Root(), tree.Root()) {
+		t.Fatal("the root of the store should be the root of the fixed depth tree")
+	}
+	for _, index := range []uint64{0, 5, uint64(len(leaves) - 1), 1<<uint(s.Depth()) - 1} {
+		proofSet, err := s.Proof(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyProof(h, s.Root(), proofSet, index, 1<<uint(s.Depth())) {
+			t.Fatal("the proof of the store should pass")
+		}
+	}
+}
+
+func testStore(t *testing.T, db Backend) {
+
+	const depth = 10
+	s, err := NewStore(bn256.NewMiMC("seed"), depth, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaves := make([][]byte, 20)
+	for i := range leaves {
+		leaves[i] = randomLeaf()
+		if err := s.Update(uint64(i), leaves[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkStore(t, s, leaves)
+	version := s.Root()
+	versionLeaves := append([][]byte{}, leaves...)
+
+	// random updates, with their witnesses
+	for i := 0; i < 10; i++ {
+		index := uint64(rand.Intn(len(leaves)))
+		leaves[index] = randomLeaf()
+		proof, err := s.UpdateWithProof(index, leaves[index])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyUpdateProof(bn256.NewMiMC("seed"), proof) {
+			t.Fatal("the update proof of the store should pass")
+		}
+	}
+	checkStore(t, s, leaves)
+
+	// the previous version is still readable, and can be updated independently
+	snapshot, err := s.Snapshot(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStore(t, snapshot, versionLeaves)
+	versionLeaves[3] = randomLeaf()
+	if err := snapshot.Update(3, versionLeaves[3]); err != nil {
+		t.Fatal(err)
+	}
+	checkStore(t, snapshot, versionLeaves)
+	checkStore(t, s, leaves)
+
+	if _, err := s.Snapshot(randomLeaf()); err != errUnknownRoot {
+		t.Fatal("a snapshot of an unknown root should fail")
+	}
+	if err := s.Update(1<<depth, randomLeaf()); err != errLeafOutOfRange {
+		t.Fatal("updating a leaf out of range should fail")
+	}
+
+	// the committed version is restored by a new store
+	if err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewStore(bn256.NewMiMC("seed"), depth, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStore(t, restored, leaves)
+	if _, err := NewStore(bn256.NewMiMC("seed"), depth+1, db); err != errDepthMismatch {
+		t.Fatal("opening a store with a different depth should fail")
+	}
+}
+
+func TestStoreMemory(t *testing.T) {
+	testStore(t, NewMemoryBackend())
+}
+
+func TestStoreFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "merkletree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "store")
+
+	db, err := OpenFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, db)
+
+	// opening a store doesn't write to the backend
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStore(bn256.NewMiMC("seed"), 11, db); err != errDepthMismatch {
+		t.Fatal("opening a store with a different depth should fail")
+	}
+	s, err := NewStore(bn256.NewMiMC("seed"), 10, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() != before.Size() {
+		t.Fatal("opening a store shouldn't write to the file")
+	}
+	root := s.Root()
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// reopening the file, after an interrupted write
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{0, 0, 0, 4, 0, 0, 0, 32, 'h'}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	db, err = OpenFileBackend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	s, err = NewStore(bn256.NewMiMC("seed"), 10, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.Root(), root) {
+		t.Fatal("the committed root should be restored from the file")
+	}
+	if err := s.Update(0, randomLeaf()); err != nil {
+		t.Fatal(err)
+	}
+}
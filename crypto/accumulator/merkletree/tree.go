@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+	"math/bits"
 )
 
 // A Tree takes data as leaves and returns the Merkle root. Each call to 'Push'
@@ -34,6 +35,9 @@ type Tree struct {
 	proofSet     [][]byte
 	proofTree    bool
 
+	// depth of a fixed depth tree (cf NewFixedDepth), 0 otherwise
+	depth int
+
 	// The cachedTree flag indicates that the tree is cached, meaning that
 	// different code is used in 'Push' for creating a new head subtree. Adding
 	// this flag is somewhat gross, but eliminates needing to duplicate the
@@ -105,6 +109,51 @@ func New(h hash.Hash) *Tree {
 	}
 }
 
+// NewFixedDepth creates a new Tree with 2**depth leaves, the leaves which are not pushed being
+// padding leaves (zeros of the size of the hash). All the proofs have depth siblings, the position of
+// the nodes of the path being given by the bits of the index of the leaf (cf std/accumulator/merkle.VerifyProofAtIndex).
+func NewFixedDepth(h hash.Hash, depth int) *Tree {
+	return &Tree{
+		hash:  h,
+		depth: depth,
+	}
+}
+
+// padded returns a copy of t in which the missing leaves of a fixed depth tree are pushed.
+// The padding subtrees are pushed from the smallest to the largest, so that it costs O(depth) hashes.
+func (t *Tree) padded() *Tree {
+	if t.depth == 0 {
+		return t
+	}
+	numLeaves := uint64(1) << uint(t.depth)
+	if t.currentIndex > numLeaves {
+		panic("wrong usage: too many leaves pushed in a fixed depth tree")
+	}
+	res := *t
+	res.proofSet = append([][]byte{}, t.proofSet...)
+	if res.proofIndex >= res.currentIndex {
+		res.proofTree = false // the leaf to prove is a padding leaf, no proof can be built
+	}
+
+	// empty[i] root of a subtree of height i with padding leaves
+	empty := make([][]byte, t.depth+1)
+	empty[0] = leafSum(t.hash, make([]byte, t.hash.Size()))
+	for i := 1; i <= t.depth; i++ {
+		empty[i] = nodeSum(t.hash, empty[i-1], empty[i-1])
+	}
+
+	for res.currentIndex < numLeaves {
+		height := t.depth
+		if res.currentIndex != 0 {
+			height = bits.TrailingZeros64(res.currentIndex)
+		}
+		if err := res.PushSubTree(height, empty[height]); err != nil {
+			panic(err) // unreachable, the subtree is smaller than the smallest subtree and after the proof index
+		}
+	}
+	return &res
+}
+
 // Prove creates a proof that the leaf at the established index (established by
 // SetIndex) is an element of the Merkle tree. Prove will return a nil proof
 // set if used incorrectly. Prove does not modify the Tree. Prove can only be
@@ -113,6 +162,11 @@ func (t *Tree) Prove() (merkleRoot []byte, proofSet [][]byte, proofIndex uint64,
 	if !t.proofTree {
 		panic("wrong usage: can't call prove on a tree if SetIndex wasn't called")
 	}
+	if t.depth != 0 && t.proofIndex >= t.currentIndex {
+		// the leaf to prove is a padding leaf (or not in the tree)
+		return t.Root(), nil, t.proofIndex, t.currentIndex
+	}
+	t = t.padded()
 
 	// Return nil if the Tree is empty, or if the proofIndex hasn't yet been
 	// reached.
@@ -272,6 +326,8 @@ func (t *Tree) PushSubTree(height int, sum []byte) error {
 
 // Root returns the Merkle root of the data that has been pushed.
 func (t *Tree) Root() []byte {
+	t = t.padded()
+
 	// If the Tree is empty, return nil.
 	if t.head == nil {
 		return nil
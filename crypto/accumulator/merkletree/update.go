@@ -0,0 +1,159 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+// UpdateProof witness of the update of a leaf of a Merkle tree from OldLeaf to NewLeaf
+// (cf std/accumulator/merkle.VerifyUpdate)
+type UpdateProof struct {
+	OldRoot, NewRoot []byte
+	OldLeaf, NewLeaf []byte
+
+	// Path siblings of the nodes from the leaf to the root, which are the same in both trees
+	Path [][]byte
+
+	// IndexBits[i] is 1 if the node of the path at height i is a right child, 0 otherwise.
+	// When the number of leaves is a power of 2, they are the bits of the index of the leaf.
+	IndexBits []int
+}
+
+// BuildUpdateProof returns the witness of the update of the leaf at index in the tree built
+// from leaves, to newLeaf. leaves is not modified.
+func BuildUpdateProof(h hash.Hash, leaves [][]byte, index uint64, newLeaf []byte) (UpdateProof, error) {
+	proofs, err := BuildUpdateProofs(h, leaves, []uint64{index}, [][]byte{newLeaf})
+	if err != nil {
+		return UpdateProof{}, err
+	}
+	return proofs[0], nil
+}
+
+// BuildUpdateProofs returns the witnesses of consecutive updates of the tree built from leaves:
+// the i-th update sets the leaf at indexes[i] to newLeaves[i], in the tree resulting from the
+// previous updates. leaves is not modified.
+func BuildUpdateProofs(h hash.Hash, leaves [][]byte, indexes []uint64, newLeaves [][]byte) ([]UpdateProof, error) {
+
+	if len(indexes) != len(newLeaves) {
+		return nil, errors.New("the number of indexes and new leaves should match")
+	}
+
+	current := make([][]byte, len(leaves))
+	copy(current, leaves)
+	numLeaves := uint64(len(leaves))
+
+	res := make([]UpdateProof, len(indexes))
+	for i, index := range indexes {
+		if index >= numLeaves {
+			return nil, errors.New("the index of the leaf to update is out of range")
+		}
+
+		oldRoot, oldProofSet := buildProof(h, current, index)
+		current[index] = newLeaves[i]
+		newRoot, newProofSet := buildProof(h, current, index)
+
+		// sanity check: the update doesn't change the siblings
+		for j := 1; j < len(oldProofSet); j++ {
+			if !bytes.Equal(oldProofSet[j], newProofSet[j]) {
+				return nil, errors.New("the paths of the leaf before and after the update differ")
+			}
+		}
+
+		res[i] = UpdateProof{
+			OldRoot:   oldRoot,
+			NewRoot:   newRoot,
+			OldLeaf:   oldProofSet[0],
+			NewLeaf:   newProofSet[0],
+			Path:      oldProofSet[1:],
+			IndexBits: GenerateIndexBits(index, numLeaves, len(oldProofSet)-1),
+		}
+	}
+
+	return res, nil
+}
+
+// VerifyUpdateProof returns true if proof is a valid update from OldRoot to NewRoot
+func VerifyUpdateProof(h hash.Hash, proof UpdateProof) bool {
+	if len(proof.Path) != len(proof.IndexBits) {
+		return false
+	}
+	oldSum := leafSum(h, proof.OldLeaf)
+	newSum := leafSum(h, proof.NewLeaf)
+	for i, sibling := range proof.Path {
+		if proof.IndexBits[i] == 1 {
+			oldSum = nodeSum(h, sibling, oldSum)
+			newSum = nodeSum(h, sibling, newSum)
+		} else {
+			oldSum = nodeSum(h, oldSum, sibling)
+			newSum = nodeSum(h, newSum, sibling)
+		}
+	}
+	return bytes.Equal(oldSum, proof.OldRoot) && bytes.Equal(newSum, proof.NewRoot)
+}
+
+// GenerateIndexBits returns the position of the nodes of the path of the leaf at proofIndex in a
+// tree of numLeaves leaves: 1 if the node at height i is a right child, 0 otherwise. nbSiblings is
+// the length of the proof set minus one (the first element of the proof set being the leaf).
+// The orphans being elevated (cf Tree), they are not the bits of proofIndex when numLeaves
+// is not a power of 2.
+func GenerateIndexBits(proofIndex, numLeaves uint64, nbSiblings int) []int {
+
+	res := make([]int, nbSiblings)
+
+	// heights at which the subtree containing proofIndex is complete: position given by proofIndex
+	height := 1
+	stableEnd := proofIndex
+	for {
+		subTreeStartIndex := (proofIndex / (1 << uint(height))) * (1 << uint(height))
+		subTreeEndIndex := subTreeStartIndex + (1 << (uint(height))) - 1
+		if subTreeEndIndex >= numLeaves {
+			break
+		}
+		stableEnd = subTreeEndIndex
+		if proofIndex-subTreeStartIndex >= 1<<uint(height-1) {
+			res[height-1] = 1
+		}
+		height++
+	}
+
+	// the orphans on the right are joined to the subtree as a right sibling,
+	// all the remaining siblings are on the left
+	if stableEnd != numLeaves-1 {
+		height++
+	}
+	for ; height <= nbSiblings; height++ {
+		res[height-1] = 1
+	}
+
+	return res
+}
+
+// buildProof returns the root of the tree built from leaves and the proof set of the leaf at index
+func buildProof(h hash.Hash, leaves [][]byte, index uint64) ([]byte, [][]byte) {
+	tree := New(h)
+	if err := tree.SetIndex(index); err != nil {
+		panic(err) // unreachable, the tree is empty
+	}
+	for _, leaf := range leaves {
+		tree.Push(leaf)
+	}
+	root, proofSet, _, _ := tree.Prove()
+	return root, proofSet
+}
@@ -0,0 +1,280 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smt implements a sparse Merkle tree storing key-value pairs, with membership and
+// non-membership proofs which can be verified in a circuit (cf std/accumulator/smt).
+//
+// Keys and values are field elements (big endian, cf fr.Element.Bytes), and the hash function
+// is a snark friendly hash on the same field (mimc, poseidon). The tree has 2**depth leaves, the key
+// k being stored at the position given by the depth lowest bits of H(k), in a leaf H(k, v).
+// The empty leaves are zero, and the parent of two nodes a, b (a on the left) is H(a, b).
+//
+// Two keys at the same position can't be stored in the tree, depth should be large enough so that
+// this doesn't happen for the number of keys to store (typically depth >= 2*log2(nbKeys) + 64).
+package smt
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"math/big"
+)
+
+var (
+	errInvalidDepth     = errors.New("the depth must be positive and smaller than the size of the hash")
+	errInvalidSize      = errors.New("keys and values must be field elements of the size of the hash")
+	errKeyExists        = errors.New("the key is already in the tree")
+	errKeyNotFound      = errors.New("the key is not in the tree")
+	errPositionOccupied = errors.New("another key is stored at the position of the key")
+)
+
+// Tree sparse Merkle tree, only the non empty nodes being stored
+type Tree struct {
+	hFunc  hash.Hash
+	depth  int
+	empty  [][]byte          // empty[i] root of an empty subtree of height i
+	nodes  map[nodeID][]byte // non empty nodes
+	leaves map[string]leaf   // stored key-value pairs, by position
+}
+
+// nodeID identifies a node by its height and its index among the nodes of this height
+type nodeID struct {
+	height int
+	index  string // big endian bytes of the index
+}
+
+type leaf struct {
+	key, value []byte
+}
+
+// Proof (non) membership proof of a key
+type Proof struct {
+	Siblings [][]byte // siblings of the nodes on the path from the leaf to the root
+	Value    []byte   // value of the key, nil if the key isn't in the tree
+}
+
+// New returns an empty tree with 2**depth leaves, hashed with hFunc
+func New(hFunc hash.Hash, depth int) (*Tree, error) {
+	if depth <= 0 || depth > 8*hFunc.Size() {
+		return nil, errInvalidDepth
+	}
+
+	t := &Tree{
+		hFunc:  hFunc,
+		depth:  depth,
+		empty:  make([][]byte, depth+1),
+		nodes:  make(map[nodeID][]byte),
+		leaves: make(map[string]leaf),
+	}
+	t.empty[0] = make([]byte, hFunc.Size())
+	for i := 1; i <= depth; i++ {
+		t.empty[i] = hashElements(hFunc, t.empty[i-1], t.empty[i-1])
+	}
+
+	return t, nil
+}
+
+// Depth returns the number of levels of the tree, that is the length of the proofs
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() []byte {
+	return t.node(t.depth, new(big.Int))
+}
+
+// Get returns the value of key, and false if the key isn't in the tree
+func (t *Tree) Get(key []byte) ([]byte, bool) {
+	if len(key) != t.hFunc.Size() {
+		return nil, false
+	}
+	l, ok := t.leaves[string(t.position(key).Bytes())]
+	if !ok || !bytes.Equal(l.key, key) {
+		return nil, false
+	}
+	return l.value, true
+}
+
+// Insert adds key to the tree with value. It fails if the key is already in the tree,
+// or if another key is stored at its position.
+func (t *Tree) Insert(key, value []byte) error {
+	if len(key) != t.hFunc.Size() || len(value) != t.hFunc.Size() {
+		return errInvalidSize
+	}
+	pos := t.position(key)
+	if l, ok := t.leaves[string(pos.Bytes())]; ok {
+		if bytes.Equal(l.key, key) {
+			return errKeyExists
+		}
+		return errPositionOccupied
+	}
+	t.setLeaf(pos, key, value)
+	return nil
+}
+
+// Update sets the value of key, which must be in the tree
+func (t *Tree) Update(key, value []byte) error {
+	if len(value) != t.hFunc.Size() {
+		return errInvalidSize
+	}
+	if _, ok := t.Get(key); !ok {
+		return errKeyNotFound
+	}
+	t.setLeaf(t.position(key), key, value)
+	return nil
+}
+
+// Delete removes key from the tree
+func (t *Tree) Delete(key []byte) error {
+	if _, ok := t.Get(key); !ok {
+		return errKeyNotFound
+	}
+	t.setLeaf(t.position(key), nil, nil)
+	return nil
+}
+
+// Prove returns a membership proof of key if it is in the tree, a non membership proof otherwise.
+// It fails if another key is stored at the position of key (the non membership can't be proven).
+func (t *Tree) Prove(key []byte) (Proof, error) {
+	var res Proof
+	if len(key) != t.hFunc.Size() {
+		return res, errInvalidSize
+	}
+	pos := t.position(key)
+	if l, ok := t.leaves[string(pos.Bytes())]; ok {
+		if !bytes.Equal(l.key, key) {
+			return res, errPositionOccupied
+		}
+		res.Value = append([]byte{}, l.value...)
+	}
+
+	res.Siblings = make([][]byte, t.depth)
+	var index, one big.Int
+	one.SetUint64(1)
+	index.Set(pos)
+	for i := 0; i < t.depth; i++ {
+		var sibling big.Int
+		sibling.Xor(&index, &one)
+		res.Siblings[i] = append([]byte{}, t.node(i, &sibling)...)
+		index.Rsh(&index, 1)
+	}
+
+	return res, nil
+}
+
+// VerifyMembership returns true if proof shows that key is stored with value in the tree of root root
+func VerifyMembership(hFunc hash.Hash, root, key, value []byte, proof Proof) bool {
+	if proof.Value == nil || !bytes.Equal(proof.Value, value) {
+		return false
+	}
+	return verify(hFunc, root, key, hashElements(hFunc, key, value), proof.Siblings)
+}
+
+// VerifyNonMembership returns true if proof shows that key isn't in the tree of root root
+func VerifyNonMembership(hFunc hash.Hash, root, key []byte, proof Proof) bool {
+	if proof.Value != nil {
+		return false
+	}
+	return verify(hFunc, root, key, make([]byte, hFunc.Size()), proof.Siblings)
+}
+
+// verify returns true if the root computed from the leaf at the position of key and siblings is root
+func verify(hFunc hash.Hash, root, key, leaf []byte, siblings [][]byte) bool {
+	if len(siblings) == 0 || len(siblings) > 8*hFunc.Size() {
+		return false
+	}
+	pos := position(hFunc, key, len(siblings))
+	node := leaf
+	for i := 0; i < len(siblings); i++ {
+		if pos.Bit(i) == 0 {
+			node = hashElements(hFunc, node, siblings[i])
+		} else {
+			node = hashElements(hFunc, siblings[i], node)
+		}
+	}
+	return bytes.Equal(node, root)
+}
+
+// setLeaf sets the leaf at pos to H(key, value), or to the empty leaf if key is nil,
+// and updates the nodes on its path to the root
+func (t *Tree) setLeaf(pos *big.Int, key, value []byte) {
+
+	node := t.empty[0]
+	if key != nil {
+		t.leaves[string(pos.Bytes())] = leaf{key: append([]byte{}, key...), value: append([]byte{}, value...)}
+		node = hashElements(t.hFunc, key, value)
+	} else {
+		delete(t.leaves, string(pos.Bytes()))
+	}
+
+	var index, sibling, one big.Int
+	one.SetUint64(1)
+	index.Set(pos)
+	for i := 0; i <= t.depth; i++ {
+		t.setNode(i, &index, node)
+		if i == t.depth {
+			break
+		}
+		sibling.Xor(&index, &one)
+		if index.Bit(0) == 0 {
+			node = hashElements(t.hFunc, node, t.node(i, &sibling))
+		} else {
+			node = hashElements(t.hFunc, t.node(i, &sibling), node)
+		}
+		index.Rsh(&index, 1)
+	}
+}
+
+// node returns the node of height height at index
+func (t *Tree) node(height int, index *big.Int) []byte {
+	if n, ok := t.nodes[nodeID{height, string(index.Bytes())}]; ok {
+		return n
+	}
+	return t.empty[height]
+}
+
+// setNode sets the node of height height at index, empty nodes being removed
+func (t *Tree) setNode(height int, index *big.Int, node []byte) {
+	id := nodeID{height, string(index.Bytes())}
+	if bytes.Equal(node, t.empty[height]) {
+		delete(t.nodes, id)
+	} else {
+		t.nodes[id] = node
+	}
+}
+
+// position returns the index of the leaf storing key
+func (t *Tree) position(key []byte) *big.Int {
+	return position(t.hFunc, key, t.depth)
+}
+
+// position returns the depth lowest bits of H(key)
+func position(hFunc hash.Hash, key []byte, depth int) *big.Int {
+	var res, mask big.Int
+	res.SetBytes(hashElements(hFunc, key))
+	mask.Lsh(big.NewInt(1), uint(depth)).Sub(&mask, big.NewInt(1))
+	return res.And(&res, &mask)
+}
+
+// hashElements returns H(elements[0], elements[1], ...), as computed by the hash gadgets
+func hashElements(hFunc hash.Hash, elements ...[]byte) []byte {
+	hFunc.Reset()
+	for _, e := range elements {
+		hFunc.Write(e)
+	}
+	return hFunc.Sum(nil)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+func TestSMT(t *testing.T) {
+
+	hFunc := bn256.NewMiMC("seed")
+	tree, err := New(hFunc, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyRoot := tree.Root()
+
+	keys := make([][]byte, 10)
+	values := make([][]byte, 10)
+	for i := range keys {
+		var k, v fr.Element
+		k.SetRandom()
+		v.SetRandom()
+		keys[i], values[i] = k.Bytes(), v.Bytes()
+		if err := tree.Insert(keys[i], values[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tree.Insert(keys[0], values[1]); err != errKeyExists {
+		t.Fatal("inserting a key twice should fail")
+	}
+
+	root := tree.Root()
+	for i := range keys {
+		proof, err := tree.Prove(keys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !VerifyMembership(hFunc, root, keys[i], values[i], proof) {
+			t.Fatal("membership proof of a key of the tree should pass")
+		}
+		if VerifyMembership(hFunc, root, keys[i], values[(i+1)%len(keys)], proof) {
+			t.Fatal("membership proof with a wrong value should fail")
+		}
+		if VerifyNonMembership(hFunc, root, keys[i], Proof{Siblings: proof.Siblings}) {
+			t.Fatal("non membership proof of a key of the tree should fail")
+		}
+	}
+
+	// deleting a key: non membership
+	if err := tree.Delete(keys[3]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tree.Get(keys[3]); ok {
+		t.Fatal("a deleted key shouldn't be in the tree")
+	}
+	proof, err := tree.Prove(keys[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyNonMembership(hFunc, tree.Root(), keys[3], proof) {
+		t.Fatal("non membership proof of a deleted key should pass")
+	}
+	if err := tree.Update(keys[3], values[3]); err != errKeyNotFound {
+		t.Fatal("updating a key which isn't in the tree should fail")
+	}
+
+	// updating a value changes the root, setting it back restores it
+	if err := tree.Insert(keys[3], values[3]); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(tree.Root(), root) {
+		t.Fatal("the root should only depend on the key-value pairs")
+	}
+	if err := tree.Update(keys[0], values[1]); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(tree.Root(), root) {
+		t.Fatal("updating a value should change the root")
+	}
+	if v, ok := tree.Get(keys[0]); !ok || !bytes.Equal(v, values[1]) {
+		t.Fatal("Get should return the updated value")
+	}
+
+	// removing all the keys gives the empty tree back, with no stored node
+	for i := range keys {
+		if err := tree.Delete(keys[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !bytes.Equal(tree.Root(), emptyRoot) || len(tree.nodes) != 0 {
+		t.Fatal("the tree should be empty")
+	}
+}
+
+func TestSMTCollision(t *testing.T) {
+
+	// 4 leaves: the fifth key collides with another one
+	tree, err := New(bn256.NewMiMC("seed"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var collision error
+	for i := 0; i < 5 && collision == nil; i++ {
+		var k fr.Element
+		k.SetUint64(uint64(i))
+		collision = tree.Insert(k.Bytes(), k.Bytes())
+		if collision != nil {
+			if _, err := tree.Prove(k.Bytes()); err != errPositionOccupied {
+				t.Fatal("a non membership proof can't be built at an occupied position")
+			}
+		}
+	}
+	if collision != errPositionOccupied {
+		t.Fatal("inserting a key at an occupied position should fail")
+	}
+}
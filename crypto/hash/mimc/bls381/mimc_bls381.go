@@ -34,14 +34,19 @@ type Params []fr.Element
 
 // NewParams creates new mimc object
 func NewParams(seed string) Params {
+	return newParams(seed, mimcNbRounds)
+}
+
+// newParams returns the n first constants obtained by chaining sha3 from seed
+func newParams(seed string, n int) Params {
 
 	// set the constants
-	res := make(Params, mimcNbRounds)
+	res := make(Params, n)
 
 	rnd := sha3.Sum256([]byte(seed))
 	value := new(big.Int).SetBytes(rnd[:])
 
-	for i := 0; i < mimcNbRounds; i++ {
+	for i := 0; i < n; i++ {
 		rnd = sha3.Sum256(value.Bytes())
 		value.SetBytes(rnd[:])
 		res[i].SetBigInt(value)
@@ -71,7 +76,7 @@ func NewMiMC(seed string) hash.Hash {
 // Reset resets the Hash to its initial state.
 func (d *digest) Reset() {
 	d.data = nil
-	d.h = fr.Element{0, 0, 0, 0}
+	d.h.SetZero()
 }
 
 // Sum appends the current hash to b and returns the resulting slice.
@@ -110,7 +115,7 @@ func (d *digest) Write(p []byte) (n int, err error) {
 // The XOR operation is replaced by field addition, data is in Montgomery form
 func (d *digest) checksum() fr.Element {
 
-	var buffer [32]byte
+	var buffer [BlockSize]byte
 	var x fr.Element
 
 	// if data size is not multiple of BlockSizes we padd:
@@ -128,7 +133,7 @@ func (d *digest) checksum() fr.Element {
 	}
 
 	if len(d.data) == 0 {
-		d.data = make([]byte, 32)
+		d.data = make([]byte, BlockSize)
 	}
 
 	nbChunks := len(d.data) / BlockSize
@@ -149,17 +154,23 @@ func (d *digest) checksum() fr.Element {
 func (d *digest) encrypt(m fr.Element) {
 
 	for _, cons := range d.Params {
-		// m = (m+k+c)^7
-		var tmp fr.Element
-		tmp.Add(&m, &d.h).Add(&tmp, &cons)
-		m.Square(&tmp).
-			Square(&m).
-			Mul(&m, &tmp)
+		// m = sBox(m+k+c)
+		m.Add(&m, &d.h).Add(&m, &cons)
+		sBox(&m)
 	}
 	m.Add(&m, &d.h)
 	d.h = m
 }
 
+// sBox x -> x^5
+func sBox(x *fr.Element) {
+	var tmp fr.Element
+	tmp.Set(x)
+	x.Square(&tmp).
+		Square(x).
+		Mul(x, &tmp)
+}
+
 // Sum computes the mimc hash of msg from seed
 func Sum(seed string, msg []byte) []byte {
 	params := NewParams(seed)
@@ -169,3 +180,65 @@ func Sum(seed string, msg []byte) []byte {
 	h := d.checksum()
 	return h.Bytes()
 }
+
+// NewSpongeParams returns the round constants of the permutation used by a sponge of the given rate:
+// (rate+1)*mimcNbRounds constants chained from seed as in NewParams
+func NewSpongeParams(seed string, rate int) Params {
+	if rate < 1 {
+		panic("mimc: the rate of the sponge must be positive")
+	}
+	return newParams(seed, (rate+1)*mimcNbRounds)
+}
+
+// permute applies on state (of size rate+1) the generalized Feistel permutation (GMiMC, expanding round function):
+// at round i, f = sBox(state[0] + params[i]) is added to the other branches, then the branches are rotated
+// to the left. Each branch goes through mimcNbRounds S-boxes.
+func (params Params) permute(state []fr.Element) {
+	for i := 0; i < len(params); i++ {
+		var f fr.Element
+		f.Add(&state[0], &params[i])
+		sBox(&f)
+		for j := 1; j < len(state); j++ {
+			state[j].Add(&state[j], &f)
+		}
+		x0 := state[0]
+		copy(state, state[1:])
+		state[len(state)-1] = x0
+	}
+}
+
+// Sponge absorbs inputs in a sponge of the given rate, and squeezes nbOutputs elements from it.
+//
+// The state has rate+1 elements, the last one being the capacity. The inputs are padded with a one
+// followed by zeros up to a multiple of the rate, and each block of rate elements is added to the state
+// before a permutation (see NewSpongeParams). The outputs are read rate elements at a time,
+// the state being permuted in between.
+func Sponge(seed string, rate, nbOutputs int, inputs ...fr.Element) []fr.Element {
+	params := NewSpongeParams(seed, rate)
+
+	padded := make([]fr.Element, len(inputs), len(inputs)+rate)
+	copy(padded, inputs)
+	padded = append(padded, fr.One())
+	for len(padded)%rate != 0 {
+		padded = append(padded, fr.Element{})
+	}
+
+	state := make([]fr.Element, rate+1)
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate; j++ {
+			state[j].Add(&state[j], &padded[i+j])
+		}
+		params.permute(state)
+	}
+
+	res := make([]fr.Element, 0, nbOutputs)
+	for {
+		for j := 0; j < rate && len(res) < nbOutputs; j++ {
+			res = append(res, state[j])
+		}
+		if len(res) == nbOutputs {
+			return res
+		}
+		params.permute(state)
+	}
+}
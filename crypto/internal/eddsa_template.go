@@ -10,7 +10,11 @@ import (
 	"math/big"
 
 	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	{{- if or (eq .Curve "BLS377") (eq .Curve "BW761")}}
+	twistededwards "github.com/consensys/gnark/crypto/twistededwards/{{toLower .Curve}}"
+	{{- else}}
 	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+	{{- end}}
 	"golang.org/x/crypto/blake2b"
 )
 
@@ -89,7 +93,7 @@ func Sign(message fr.Element, pub PublicKey, priv PrivateKey) (Signature, error)
 	var randScalar fr.Element
 
 	// randSrc = privKey.randSrc || msg (-> message = MSB message .. LSB message)
-	randSrc := make([]byte, 64)
+	randSrc := make([]byte, 32+fr.Limbs*8)
 	for i, v := range priv.randSrc {
 		randSrc[i] = v
 	}
@@ -99,7 +103,7 @@ func Sign(message fr.Element, pub PublicKey, priv PrivateKey) (Signature, error)
 		return res, err
 	}
 	bufb := buf.Bytes()
-	for i := 0; i < 32; i++ {
+	for i := 0; i < fr.Limbs*8; i++ {
 		randSrc[32+i] = bufb[i]
 	}
 
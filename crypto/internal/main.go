@@ -8,7 +8,7 @@ import (
 	"github.com/consensys/bavard"
 )
 
-//go:generate go run main.go eddsa_template.go eddsa_test_template.go mimc_template.go
+//go:generate go run main.go eddsa_template.go eddsa_test_template.go mimc_template.go poseidon_template.go merkletree_template.go pedersen_template.go twistededwards_template.go schnorr_template.go
 func main() {
 
 	// -----------------------------------------------------
@@ -43,6 +43,82 @@ func main() {
 		Package:  "eddsa",
 	}
 
+	eddsabls377 := templateData{
+		Curve:    "BLS377",
+		Path:     "../signature/eddsa/bls377/",
+		FileName: "eddsa.go",
+		Src:      []string{eddsaTemplate},
+		Package:  "eddsa",
+	}
+	eddsabls377Test := templateData{
+		Curve:    "BLS377",
+		Path:     "../signature/eddsa/bls377/",
+		FileName: "eddsa_test.go",
+		Src:      []string{eddsaTestTemplate},
+		Package:  "eddsa",
+	}
+
+	eddsabw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../signature/eddsa/bw761/",
+		FileName: "eddsa.go",
+		Src:      []string{eddsaTemplate},
+		Package:  "eddsa",
+	}
+	eddsabw761Test := templateData{
+		Curve:    "BW761",
+		Path:     "../signature/eddsa/bw761/",
+		FileName: "eddsa_test.go",
+		Src:      []string{eddsaTestTemplate},
+		Package:  "eddsa",
+	}
+
+	// -----------------------------------------------------
+	// twisted Edwards files (the ones of bn256 and bls381 are in gurvy)
+	twistededwardsbls377 := templateData{
+		Curve:    "BLS377",
+		Path:     "../twistededwards/bls377/",
+		FileName: "twistededwards.go",
+		Src:      []string{twistededwardsCurveTemplate},
+		Package:  "twistededwards",
+	}
+	twistededwardsbls377Point := templateData{
+		Curve:    "BLS377",
+		Path:     "../twistededwards/bls377/",
+		FileName: "point.go",
+		Src:      []string{twistededwardsPointTemplate},
+		Package:  "twistededwards",
+	}
+	twistededwardsbls377Test := templateData{
+		Curve:    "BLS377",
+		Path:     "../twistededwards/bls377/",
+		FileName: "twistededwards_test.go",
+		Src:      []string{twistededwardsTestTemplate},
+		Package:  "twistededwards",
+	}
+
+	twistededwardsbw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../twistededwards/bw761/",
+		FileName: "twistededwards.go",
+		Src:      []string{twistededwardsCurveTemplate},
+		Package:  "twistededwards",
+	}
+	twistededwardsbw761Point := templateData{
+		Curve:    "BW761",
+		Path:     "../twistededwards/bw761/",
+		FileName: "point.go",
+		Src:      []string{twistededwardsPointTemplate},
+		Package:  "twistededwards",
+	}
+	twistededwardsbw761Test := templateData{
+		Curve:    "BW761",
+		Path:     "../twistededwards/bw761/",
+		FileName: "twistededwards_test.go",
+		Src:      []string{twistededwardsTestTemplate},
+		Package:  "twistededwards",
+	}
+
 	// -----------------------------------------------------
 	// mimc files
 	mimcbn256 := templateData{
@@ -69,14 +145,247 @@ func main() {
 		Package:  "bls377",
 	}
 
+	mimcbw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../hash/mimc/bw761/",
+		FileName: "mimc_bw761.go",
+		Src:      []string{mimcCommonTemplate, mimcCurveTemplate, mimcEncryptTemplate},
+		Package:  "bw761",
+	}
+
+	// -----------------------------------------------------
+	// poseidon files
+	poseidonbn256 := templateData{
+		Curve:    "BN256",
+		Path:     "../hash/poseidon/bn256/",
+		FileName: "poseidon_bn256.go",
+		Src:      []string{poseidonTemplate},
+		Package:  "bn256",
+	}
+
+	poseidonbls381 := templateData{
+		Curve:    "BLS381",
+		Path:     "../hash/poseidon/bls381/",
+		FileName: "poseidon_bls381.go",
+		Src:      []string{poseidonTemplate},
+		Package:  "bls381",
+	}
+
+	poseidonbls377 := templateData{
+		Curve:    "BLS377",
+		Path:     "../hash/poseidon/bls377/",
+		FileName: "poseidon_bls377.go",
+		Src:      []string{poseidonTemplate},
+		Package:  "bls377",
+	}
+
+	poseidonbw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../hash/poseidon/bw761/",
+		FileName: "poseidon_bw761.go",
+		Src:      []string{poseidonTemplate},
+		Package:  "bw761",
+	}
+
+	// -----------------------------------------------------
+	// merkle tree files
+	merkletreebn256 := templateData{
+		Curve:    "BN256",
+		Path:     "../accumulator/merkletree/bn256/",
+		FileName: "merkletree.go",
+		Src:      []string{merkleTreeTemplate},
+		Package:  "bn256",
+	}
+	merkletreebn256Test := templateData{
+		Curve:    "BN256",
+		Path:     "../accumulator/merkletree/bn256/",
+		FileName: "merkletree_test.go",
+		Src:      []string{merkleTreeTestTemplate},
+		Package:  "bn256",
+	}
+
+	merkletreebls381 := templateData{
+		Curve:    "BLS381",
+		Path:     "../accumulator/merkletree/bls381/",
+		FileName: "merkletree.go",
+		Src:      []string{merkleTreeTemplate},
+		Package:  "bls381",
+	}
+	merkletreebls381Test := templateData{
+		Curve:    "BLS381",
+		Path:     "../accumulator/merkletree/bls381/",
+		FileName: "merkletree_test.go",
+		Src:      []string{merkleTreeTestTemplate},
+		Package:  "bls381",
+	}
+
+	merkletreebls377 := templateData{
+		Curve:    "BLS377",
+		Path:     "../accumulator/merkletree/bls377/",
+		FileName: "merkletree.go",
+		Src:      []string{merkleTreeTemplate},
+		Package:  "bls377",
+	}
+	merkletreebls377Test := templateData{
+		Curve:    "BLS377",
+		Path:     "../accumulator/merkletree/bls377/",
+		FileName: "merkletree_test.go",
+		Src:      []string{merkleTreeTestTemplate},
+		Package:  "bls377",
+	}
+
+	merkletreebw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../accumulator/merkletree/bw761/",
+		FileName: "merkletree.go",
+		Src:      []string{merkleTreeTemplate},
+		Package:  "bw761",
+	}
+	merkletreebw761Test := templateData{
+		Curve:    "BW761",
+		Path:     "../accumulator/merkletree/bw761/",
+		FileName: "merkletree_test.go",
+		Src:      []string{merkleTreeTestTemplate},
+		Package:  "bw761",
+	}
+
+	// -----------------------------------------------------
+	// pedersen files
+	pedersenbn256 := templateData{
+		Curve:    "BN256",
+		Path:     "../commitment/pedersen/bn256/",
+		FileName: "pedersen.go",
+		Src:      []string{pedersenTemplate},
+		Package:  "pedersen",
+	}
+	pedersenbn256Test := templateData{
+		Curve:    "BN256",
+		Path:     "../commitment/pedersen/bn256/",
+		FileName: "pedersen_test.go",
+		Src:      []string{pedersenTestTemplate},
+		Package:  "pedersen",
+	}
+
+	pedersenbls381 := templateData{
+		Curve:    "BLS381",
+		Path:     "../commitment/pedersen/bls381/",
+		FileName: "pedersen.go",
+		Src:      []string{pedersenTemplate},
+		Package:  "pedersen",
+	}
+	pedersenbls381Test := templateData{
+		Curve:    "BLS381",
+		Path:     "../commitment/pedersen/bls381/",
+		FileName: "pedersen_test.go",
+		Src:      []string{pedersenTestTemplate},
+		Package:  "pedersen",
+	}
+
+	// -----------------------------------------------------
+	// schnorr files
+	schnorrbn256 := templateData{
+		Curve:    "BN256",
+		Path:     "../signature/schnorr/bn256/",
+		FileName: "schnorr.go",
+		Src:      []string{schnorrTemplate},
+		Package:  "schnorr",
+	}
+	schnorrbn256Test := templateData{
+		Curve:    "BN256",
+		Path:     "../signature/schnorr/bn256/",
+		FileName: "schnorr_test.go",
+		Src:      []string{schnorrTestTemplate},
+		Package:  "schnorr",
+	}
+
+	schnorrbls381 := templateData{
+		Curve:    "BLS381",
+		Path:     "../signature/schnorr/bls381/",
+		FileName: "schnorr.go",
+		Src:      []string{schnorrTemplate},
+		Package:  "schnorr",
+	}
+	schnorrbls381Test := templateData{
+		Curve:    "BLS381",
+		Path:     "../signature/schnorr/bls381/",
+		FileName: "schnorr_test.go",
+		Src:      []string{schnorrTestTemplate},
+		Package:  "schnorr",
+	}
+
+	schnorrbls377 := templateData{
+		Curve:    "BLS377",
+		Path:     "../signature/schnorr/bls377/",
+		FileName: "schnorr.go",
+		Src:      []string{schnorrTemplate},
+		Package:  "schnorr",
+	}
+	schnorrbls377Test := templateData{
+		Curve:    "BLS377",
+		Path:     "../signature/schnorr/bls377/",
+		FileName: "schnorr_test.go",
+		Src:      []string{schnorrTestTemplate},
+		Package:  "schnorr",
+	}
+
+	schnorrbw761 := templateData{
+		Curve:    "BW761",
+		Path:     "../signature/schnorr/bw761/",
+		FileName: "schnorr.go",
+		Src:      []string{schnorrTemplate},
+		Package:  "schnorr",
+	}
+	schnorrbw761Test := templateData{
+		Curve:    "BW761",
+		Path:     "../signature/schnorr/bw761/",
+		FileName: "schnorr_test.go",
+		Src:      []string{schnorrTestTemplate},
+		Package:  "schnorr",
+	}
+
 	data := []templateData{
 		eddsabls381,
 		eddsabls381Test,
 		eddsabn256,
 		eddsabn256Test,
+		eddsabls377,
+		eddsabls377Test,
+		eddsabw761,
+		eddsabw761Test,
+		twistededwardsbls377,
+		twistededwardsbls377Point,
+		twistededwardsbls377Test,
+		twistededwardsbw761,
+		twistededwardsbw761Point,
+		twistededwardsbw761Test,
 		mimcbn256,
 		mimcbls381,
 		mimcbls377,
+		mimcbw761,
+		poseidonbn256,
+		poseidonbls381,
+		poseidonbls377,
+		poseidonbw761,
+		merkletreebn256,
+		merkletreebn256Test,
+		merkletreebls381,
+		merkletreebls381Test,
+		merkletreebls377,
+		merkletreebls377Test,
+		merkletreebw761,
+		merkletreebw761Test,
+		pedersenbn256,
+		pedersenbn256Test,
+		pedersenbls381,
+		pedersenbls381Test,
+		schnorrbn256,
+		schnorrbn256Test,
+		schnorrbls381,
+		schnorrbls381Test,
+		schnorrbls377,
+		schnorrbls377Test,
+		schnorrbw761,
+		schnorrbw761Test,
 	}
 
 	for _, d := range data {
@@ -0,0 +1,237 @@
+package main
+
+const merkleTreeTemplate = `
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+// Domain separation tags of the tree: a leaf is hashed as H(LeafPrefix, leaf) and a node
+// as H(NodePrefix, left, right), each field element being one block of the hash function.
+// std/accumulator/merkle.FieldTree computes the same hashes in a circuit.
+const (
+	LeafPrefix = 0
+	NodePrefix = 1
+)
+
+var (
+	errBlockSize       = errors.New("the block size of the hash function must be the size of a field element")
+	errTooManyLeaves   = errors.New("too many leaves for the depth of the tree")
+	errIndexOutOfRange = errors.New("the index of the leaf is out of range")
+)
+
+// LeafSum returns the hash of leaf, H(LeafPrefix, leaf)
+func LeafSum(h hash.Hash, leaf fr.Element) fr.Element {
+	var prefix fr.Element
+	prefix.SetUint64(LeafPrefix)
+	return sum(h, prefix, leaf)
+}
+
+// NodeSum returns the hash of the node of children left and right, H(NodePrefix, left, right)
+func NodeSum(h hash.Hash, left, right fr.Element) fr.Element {
+	var prefix fr.Element
+	prefix.SetUint64(NodePrefix)
+	return sum(h, prefix, left, right)
+}
+
+// sum returns the hash of data, each element being written as one block
+func sum(h hash.Hash, data ...fr.Element) fr.Element {
+	h.Reset()
+	for _, d := range data {
+		b := d.Bytes()
+		// the Hash interface specifies that Write never returns an error
+		_, _ = h.Write(b[:])
+	}
+	var res fr.Element
+	res.SetBytes(h.Sum(nil))
+	return res
+}
+
+// Tree Merkle tree of fixed depth whose leaves are field elements, the leaves which
+// are not pushed being zero
+type Tree struct {
+	h      hash.Hash
+	depth  int
+	leaves []fr.Element
+}
+
+// New returns an empty tree of the given depth. The blocks of h must be field elements,
+// as for the mimc and poseidon hash functions of the curve.
+func New(h hash.Hash, depth int) (*Tree, error) {
+	if h.BlockSize() != fr.Limbs*8 {
+		return nil, errBlockSize
+	}
+	if depth < 0 || depth > 63 {
+		return nil, errTooManyLeaves
+	}
+	return &Tree{h: h, depth: depth}, nil
+}
+
+// Depth returns the depth of the tree
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Push appends leaf to the leaves of the tree
+func (t *Tree) Push(leaf fr.Element) error {
+	if uint64(len(t.leaves)) >= uint64(1)<<uint(t.depth) {
+		return errTooManyLeaves
+	}
+	t.leaves = append(t.leaves, leaf)
+	return nil
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() fr.Element {
+	root, _ := t.build(0)
+	return root
+}
+
+// Prove returns the root of the tree and the path of the leaf at index: the siblings
+// of the nodes from the leaf to the root (cf VerifyProof)
+func (t *Tree) Prove(index uint64) (root fr.Element, path []fr.Element, err error) {
+	if index >= uint64(1)<<uint(t.depth) {
+		return fr.Element{}, nil, errIndexOutOfRange
+	}
+	root, path = t.build(index)
+	return root, path, nil
+}
+
+// build returns the root of the tree and the path of the leaf at index. The subtrees made of
+// zero leaves are not computed, so that it costs O(len(leaves) + depth) hashes.
+func (t *Tree) build(index uint64) (fr.Element, []fr.Element) {
+	var zero fr.Element
+	empty := LeafSum(t.h, zero)
+
+	level := make([]fr.Element, len(t.leaves))
+	for i := range t.leaves {
+		level[i] = LeafSum(t.h, t.leaves[i])
+	}
+	path := make([]fr.Element, t.depth)
+	for height := 0; height < t.depth; height++ {
+		sibling := index ^ 1
+		if sibling < uint64(len(level)) {
+			path[height] = level[sibling]
+		} else {
+			path[height] = empty
+		}
+		if len(level)%2 == 1 {
+			level = append(level, empty)
+		}
+		parents := make([]fr.Element, len(level)/2)
+		for i := range parents {
+			parents[i] = NodeSum(t.h, level[2*i], level[2*i+1])
+		}
+		level = parents
+		empty = NodeSum(t.h, empty, empty)
+		index /= 2
+	}
+	if len(level) == 0 {
+		return empty, path
+	}
+	return level[0], path
+}
+
+// VerifyProof returns true if leaf is the leaf at index in the tree of root merkleRoot,
+// path being the siblings of the nodes from the leaf to the root
+func VerifyProof(h hash.Hash, merkleRoot, leaf fr.Element, path []fr.Element, index uint64) bool {
+	if len(path) < 64 && index >= uint64(1)<<uint(len(path)) {
+		return false
+	}
+	node := LeafSum(h, leaf)
+	for i := range path {
+		if (index>>uint(i))&1 == 1 {
+			node = NodeSum(h, path[i], node)
+		} else {
+			node = NodeSum(h, node, path[i])
+		}
+	}
+	return node.Equal(&merkleRoot)
+}
+`
+
+const merkleTreeTestTemplate = `
+
+import (
+	"hash"
+	"testing"
+
+	mimc "github.com/consensys/gnark/crypto/hash/mimc/{{toLower .Curve}}"
+	poseidon "github.com/consensys/gnark/crypto/hash/poseidon/{{toLower .Curve}}"
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+func TestTree(t *testing.T) {
+
+	for _, h := range []hash.Hash{mimc.NewMiMC("seed"), poseidon.NewPoseidon("seed")} {
+
+		tree, err := New(h, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves := make([]fr.Element, 11)
+		for i := range leaves {
+			leaves[i].SetRandom()
+			if err := tree.Push(leaves[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the root of a full tree, the padding leaves being zero
+		full, err := New(h, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 16; i++ {
+			var leaf fr.Element
+			if i < len(leaves) {
+				leaf = leaves[i]
+			}
+			if err := full.Push(leaf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := full.Push(leaves[0]); err != errTooManyLeaves {
+			t.Fatal("pushing a leaf in a full tree should fail")
+		}
+		root := tree.Root()
+		fullRoot := full.Root()
+		if !root.Equal(&fullRoot) {
+			t.Fatal("the missing leaves should be zero")
+		}
+
+		for _, index := range []uint64{0, 5, 10, 15} {
+			proofRoot, path, err := tree.Prove(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proofRoot.Equal(&root) {
+				t.Fatal("Prove should return the root of the tree")
+			}
+			var leaf fr.Element
+			if index < uint64(len(leaves)) {
+				leaf = leaves[index]
+			}
+			if !VerifyProof(h, root, leaf, path, index) {
+				t.Fatal("the proof of a leaf should pass")
+			}
+			if VerifyProof(h, root, leaves[(index+1)%uint64(len(leaves))], path, index) {
+				t.Fatal("the proof of a wrong leaf should fail")
+			}
+			if index < uint64(len(leaves)) && VerifyProof(h, root, leaf, path, index^1) {
+				t.Fatal("the proof of a leaf at another index should fail")
+			}
+		}
+
+		// a leaf and a node have different hashes
+		leafSum := LeafSum(h, leaves[0])
+		nodeSum := NodeSum(h, fr.Element{}, leaves[0])
+		if leafSum.Equal(&nodeSum) {
+			t.Fatal("leaves and nodes should be domain separated")
+		}
+	}
+}
+`
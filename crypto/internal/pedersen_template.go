@@ -0,0 +1,267 @@
+package main
+
+const pedersenTemplate = `
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+	"golang.org/x/crypto/sha3"
+)
+
+// WindowSize number of bits consumed by one window of the Pedersen hash
+const WindowSize = 3
+
+{{ if eq .Curve "BN256" }}
+// NbWindowsPerSegment maximum number of windows hashed with the same generator, such that
+// the scalar of a segment (in [-(order-1)/2, (order-1)/2]) never wraps around the subgroup order
+const NbWindowsPerSegment = 62
+{{ else if eq .Curve "BLS381" }}
+// NbWindowsPerSegment maximum number of windows hashed with the same generator, such that
+// the scalar of a segment (in [-(order-1)/2, (order-1)/2]) never wraps around the subgroup order
+const NbWindowsPerSegment = 63
+{{ end }}
+
+var (
+	errTooManyValues = errors.New("pedersen: more values than generators")
+	errTooManyBits   = errors.New("pedersen: more bits than the generators can hash")
+)
+
+// Params generators of the Pedersen commitment (and hash) on the twisted Edwards curve
+// of {{.Curve}}'s Fr. All generators are in the subgroup of prime order, and no discrete
+// log relation between them is known.
+type Params struct {
+	H twistededwards.Point   // blinding generator
+	G []twistededwards.Point // G[i] is the generator of the i-th value (commitment) or segment (hash)
+}
+
+// NewParams derives n+1 generators from seed: H, and G[0..n-1]
+//
+// Commitments to (up to) n values and Pedersen hashes of (up to) n*WindowSize*NbWindowsPerSegment bits
+// can be computed with the resulting Params. Use distinct seeds to get independent instances.
+func NewParams(seed string, n int) Params {
+	res := Params{
+		H: Generator(seed, 0),
+		G: make([]twistededwards.Point, n),
+	}
+	for i := 0; i < n; i++ {
+		res.G[i] = Generator(seed, i+1)
+	}
+	return res
+}
+
+// Generator derives the index-th generator from seed, by try and increment:
+// y is obtained by chaining sha3 from seed || index until (1-y^2)/(a-dy^2) is a square x^2,
+// then the cofactor of (x, y) is cleared
+func Generator(seed string, index int) twistededwards.Point {
+
+	curve := twistededwards.GetEdwardsCurve()
+
+	rnd := sha3.Sum256([]byte(seed + strconv.Itoa(index)))
+	value := new(big.Int).SetBytes(rnd[:])
+
+	for {
+		rnd = sha3.Sum256(value.Bytes())
+		value.SetBytes(rnd[:])
+
+		var x, y, y2, den fr.Element
+		y.SetBigInt(value)
+		y2.Square(&y)
+		den.Mul(&curve.D, &y2).Sub(&curve.A, &den)
+		if den.IsZero() {
+			continue
+		}
+		x.SetOne().Sub(&x, &y2).Div(&x, &den)
+		if x.Sqrt(&x) == nil {
+			continue
+		}
+
+		p := twistededwards.NewPoint(x, y)
+		p.ScalarMul(&p, curve.Cofactor)
+
+		// reject the points of small order, which are mapped on (0, ±1)
+		if !p.X.IsZero() {
+			return p
+		}
+	}
+}
+
+// Commit returns the hiding commitment randomness*H + sum(values[i]*G[i])
+//
+// values and randomness are elements of {{.Curve}}'s Fr, as they would be assigned in a circuit.
+func (p *Params) Commit(values []fr.Element, randomness fr.Element) (twistededwards.Point, error) {
+	if len(values) > len(p.G) {
+		return twistededwards.Point{}, errTooManyValues
+	}
+
+	var res, tmp twistededwards.Point
+	res.ScalarMul(&p.H, randomness.ToRegular())
+	for i := 0; i < len(values); i++ {
+		tmp.ScalarMul(&p.G[i], values[i].ToRegular())
+		res.Add(&res, &tmp)
+	}
+
+	return res, nil
+}
+
+// Hash returns the windowed Pedersen hash of a bit string
+//
+// bits is padded with zeros to a multiple of WindowSize, and split in segments of NbWindowsPerSegment windows.
+// Each window (b0, b1, b2) encodes enc = (1 + b0 + 2*b1) * (1 - 2*b2), and segment j is hashed as
+// sum(enc_i * 2^(4*i)) * G[j]. As the padding is implicit, the hash is collision resistant
+// for bit strings of a given length only.
+func (p *Params) Hash(bits []bool) (twistededwards.Point, error) {
+
+	nbWindows := (len(bits) + WindowSize - 1) / WindowSize
+	nbSegments := (nbWindows + NbWindowsPerSegment - 1) / NbWindowsPerSegment
+	if nbSegments > len(p.G) {
+		return twistededwards.Point{}, errTooManyBits
+	}
+
+	bit := func(i int) int64 {
+		if i < len(bits) && bits[i] {
+			return 1
+		}
+		return 0
+	}
+
+	curve := twistededwards.GetEdwardsCurve()
+
+	var res, tmp twistededwards.Point
+	res.X.SetZero()
+	res.Y.SetOne()
+
+	for j := 0; j < nbSegments; j++ {
+		var scalar, enc, shift big.Int
+		for i := 0; i < NbWindowsPerSegment; i++ {
+			w := j*NbWindowsPerSegment + i
+			if w >= nbWindows {
+				break
+			}
+			b := w * WindowSize
+			enc.SetInt64((1 + bit(b) + 2*bit(b+1)) * (1 - 2*bit(b+2)))
+			shift.Lsh(&enc, uint(4*i))
+			scalar.Add(&scalar, &shift)
+		}
+		scalar.Mod(&scalar, &curve.Order)
+
+		var s fr.Element
+		s.SetBigInt(&scalar)
+		tmp.ScalarMul(&p.G[j], s.ToRegular())
+		res.Add(&res, &tmp)
+	}
+
+	return res, nil
+}
+`
+
+const pedersenTestTemplate = `
+
+import (
+	"testing"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+)
+
+func TestGenerators(t *testing.T) {
+
+	params := NewParams("seed", 4)
+	curve := twistededwards.GetEdwardsCurve()
+
+	generators := append([]twistededwards.Point{params.H}, params.G...)
+	for i := 0; i < len(generators); i++ {
+		if !generators[i].IsOnCurve() {
+			t.Fatal("generator not on curve")
+		}
+		var o twistededwards.Point
+		var order, one fr.Element
+		order.SetBigInt(&curve.Order)
+		one.SetOne()
+		o.ScalarMul(&generators[i], order.ToRegular())
+		if !o.X.IsZero() || !o.Y.Equal(&one) {
+			t.Fatal("generator not in the prime order subgroup")
+		}
+		for j := 0; j < i; j++ {
+			if generators[i].X.Equal(&generators[j].X) && generators[i].Y.Equal(&generators[j].Y) {
+				t.Fatal("generators should be distinct")
+			}
+		}
+	}
+}
+
+func TestCommitHomomorphic(t *testing.T) {
+
+	params := NewParams("seed", 2)
+
+	var a, b [2]fr.Element
+	var r1, r2 fr.Element
+	a[0].SetUint64(12)
+	a[1].SetUint64(42)
+	b[0].SetUint64(7)
+	b[1].SetUint64(1)
+	r1.SetUint64(1000)
+	r2.SetUint64(2000)
+
+	ca, err := params.Commit(a[:], r1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := params.Commit(b[:], r2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum [2]fr.Element
+	var rsum fr.Element
+	sum[0].Add(&a[0], &b[0])
+	sum[1].Add(&a[1], &b[1])
+	rsum.Add(&r1, &r2)
+	csum, err := params.Commit(sum[:], rsum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca.Add(&ca, &cb)
+	if !ca.X.Equal(&csum.X) || !ca.Y.Equal(&csum.Y) {
+		t.Fatal("commitments should be additively homomorphic")
+	}
+
+	if _, err := params.Commit(make([]fr.Element, 3), r1); err == nil {
+		t.Fatal("committing to more values than generators should fail")
+	}
+}
+
+func TestHashPadding(t *testing.T) {
+
+	params := NewParams("seed", 2)
+
+	// bits are padded with zeros up to a multiple of WindowSize
+	h1, err := params.Hash([]bool{true, false, true, true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := params.Hash([]bool{true, false, true, true, false, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !h1.X.Equal(&h2.X) || !h1.Y.Equal(&h2.Y) {
+		t.Fatal("padding with zeros shouldn't change the hash")
+	}
+
+	h3, err := params.Hash([]bool{true, false, true, false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1.X.Equal(&h3.X) && h1.Y.Equal(&h3.Y) {
+		t.Fatal("distinct bit strings should have distinct hashes")
+	}
+
+	if _, err := params.Hash(make([]bool, 2*WindowSize*NbWindowsPerSegment+1)); err == nil {
+		t.Fatal("hashing more bits than the generators allow should fail")
+	}
+}
+`
@@ -0,0 +1,263 @@
+package main
+
+const poseidonTemplate = `
+
+import (
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	"golang.org/x/crypto/sha3"
+)
+
+{{ if eq .Curve "BLS377" }}
+// Alpha exponent of the S-box x -> x^Alpha (smallest alpha such that gcd(alpha, r-1) = 1)
+const Alpha = 11
+{{ else }}
+// Alpha exponent of the S-box x -> x^Alpha (smallest alpha such that gcd(alpha, r-1) = 1)
+const Alpha = 5
+{{ end }}
+
+// BlockSize size that poseidon consumes
+const BlockSize = fr.Limbs * 8
+
+// SpongeWidth size of the state used in sponge mode (rate SpongeWidth-1, capacity 1)
+const SpongeWidth = 3
+
+// NbFullRounds number of full rounds, half of them are applied before the partial rounds
+const NbFullRounds = 8
+
+// MinWidth and MaxWidth bounds on the size of the state
+const (
+	MinWidth = 2
+	MaxWidth = 17
+)
+
+// nbPartialRounds[width-MinWidth] number of partial rounds for a state of size width.
+// The figures are the reference ones for x^5 over 254 bits fields at 128 bits of security,
+// larger exponents or fields only increase the security margin.
+var nbPartialRounds = [MaxWidth - MinWidth + 1]int{56, 57, 56, 60, 60, 63, 64, 63, 60, 66, 60, 65, 70, 60, 64, 68}
+
+// Params constants for the poseidon permutation on a state of size Width
+type Params struct {
+	Width           int
+	NbFullRounds    int
+	NbPartialRounds int
+	RoundKeys       [][]fr.Element // RoundKeys[i] are the Width constants added at round i
+	MDS             [][]fr.Element // Width x Width mixing matrix
+}
+
+// NewParams creates new poseidon parameters for a state of size width
+//
+// The round keys are derived from seed (and width) by chaining sha3, the MDS matrix
+// is the Cauchy matrix M[i][j] = 1 / (i + width + j)
+func NewParams(seed string, width int) Params {
+	if width < MinWidth || width > MaxWidth {
+		panic("poseidon: unsupported state width")
+	}
+
+	res := Params{
+		Width:           width,
+		NbFullRounds:    NbFullRounds,
+		NbPartialRounds: nbPartialRounds[width-MinWidth],
+	}
+	nbRounds := res.NbFullRounds + res.NbPartialRounds
+
+	// round keys
+	rnd := sha3.Sum256(append([]byte(seed), byte(width)))
+	value := new(big.Int).SetBytes(rnd[:])
+
+	res.RoundKeys = make([][]fr.Element, nbRounds)
+	for i := 0; i < nbRounds; i++ {
+		res.RoundKeys[i] = make([]fr.Element, width)
+		for j := 0; j < width; j++ {
+			rnd = sha3.Sum256(value.Bytes())
+			value.SetBytes(rnd[:])
+			res.RoundKeys[i][j].SetBigInt(value)
+		}
+	}
+
+	// mds matrix
+	res.MDS = make([][]fr.Element, width)
+	for i := 0; i < width; i++ {
+		res.MDS[i] = make([]fr.Element, width)
+		for j := 0; j < width; j++ {
+			res.MDS[i][j].SetUint64(uint64(i + width + j)).Inverse(&res.MDS[i][j])
+		}
+	}
+
+	return res
+}
+
+// IsFullRound returns true if the i-th round applies the S-box on the whole state
+func (p *Params) IsFullRound(i int) bool {
+	return i < p.NbFullRounds/2 || i >= p.NbFullRounds/2+p.NbPartialRounds
+}
+
+// Permute applies the poseidon permutation on state, in place
+func (p *Params) Permute(state []fr.Element) {
+	if len(state) != p.Width {
+		panic("poseidon: state size doesn't match the parameters")
+	}
+
+	tmp := make([]fr.Element, p.Width)
+	nbRounds := p.NbFullRounds + p.NbPartialRounds
+
+	for i := 0; i < nbRounds; i++ {
+
+		// add round keys
+		for j := 0; j < p.Width; j++ {
+			state[j].Add(&state[j], &p.RoundKeys[i][j])
+		}
+
+		// S-box
+		if p.IsFullRound(i) {
+			for j := 0; j < p.Width; j++ {
+				sBox(&state[j])
+			}
+		} else {
+			sBox(&state[0])
+		}
+
+		// mix
+		for j := 0; j < p.Width; j++ {
+			var t fr.Element
+			tmp[j].SetZero()
+			for k := 0; k < p.Width; k++ {
+				t.Mul(&p.MDS[j][k], &state[k])
+				tmp[j].Add(&tmp[j], &t)
+			}
+		}
+		copy(state, tmp)
+	}
+}
+
+{{ if eq .Curve "BLS377" }}
+// sBox x -> x^11
+func sBox(x *fr.Element) {
+	var x2, x8 fr.Element
+	x2.Square(x)
+	x8.Square(&x2).Square(&x8)
+	x.Mul(x, &x2).Mul(x, &x8)
+}
+{{ else }}
+// sBox x -> x^5
+func sBox(x *fr.Element) {
+	var x4 fr.Element
+	x4.Square(x).Square(&x4)
+	x.Mul(x, &x4)
+}
+{{ end }}
+
+// HashFixed hashes exactly len(inputs) elements with a single permutation on a state of size len(inputs)+1.
+// The capacity element is set to len(inputs), the digest is the first element of the rate.
+func HashFixed(seed string, inputs ...fr.Element) fr.Element {
+	params := NewParams(seed, len(inputs)+1)
+	state := make([]fr.Element, len(inputs)+1)
+	state[0].SetUint64(uint64(len(inputs)))
+	copy(state[1:], inputs)
+	params.Permute(state)
+	return state[1]
+}
+
+// HashSponge hashes an arbitrary number of elements with a sponge of width SpongeWidth.
+// The inputs are padded with a one followed by zeros up to a multiple of the rate,
+// the digest is the first element of the rate.
+func HashSponge(seed string, inputs ...fr.Element) fr.Element {
+	params := NewParams(seed, SpongeWidth)
+	return sponge(&params, inputs)
+}
+
+func sponge(params *Params, inputs []fr.Element) fr.Element {
+	rate := params.Width - 1
+
+	padded := make([]fr.Element, len(inputs), len(inputs)+rate)
+	copy(padded, inputs)
+	padded = append(padded, fr.One())
+	for len(padded)%rate != 0 {
+		padded = append(padded, fr.Element{})
+	}
+
+	state := make([]fr.Element, params.Width)
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate; j++ {
+			state[j+1].Add(&state[j+1], &padded[i+j])
+		}
+		params.Permute(state)
+	}
+	return state[1]
+}
+
+// digest represents the data absorbed so far
+// along with the params of the poseidon permutation
+type digest struct {
+	Params Params
+	data   []byte // data to hash
+}
+
+// NewPoseidon returns a hash.Hash computing poseidon in sponge mode, pure-go reference implementation
+func NewPoseidon(seed string) hash.Hash {
+	d := new(digest)
+	d.Params = NewParams(seed, SpongeWidth)
+	d.Reset()
+	return d
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = nil
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := d.checksum()
+	hash := h.Bytes()
+	b = append(b, hash[:]...)
+	return b
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+// It never returns an error.
+func (d *digest) Write(p []byte) (n int, err error) {
+	n = len(p)
+	d.data = append(d.data, p...)
+	return
+}
+
+// checksum splits the data in chunks of BlockSize bytes (the last one being
+// read as a big endian integer if it is shorter), and hashes the resulting
+// field elements in sponge mode
+func (d *digest) checksum() fr.Element {
+	nbChunks := (len(d.data) + BlockSize - 1) / BlockSize
+	inputs := make([]fr.Element, nbChunks)
+	for i := 0; i < nbChunks; i++ {
+		end := (i + 1) * BlockSize
+		if end > len(d.data) {
+			end = len(d.data)
+		}
+		inputs[i].SetBytes(d.data[i*BlockSize : end])
+	}
+	return sponge(&d.Params, inputs)
+}
+
+// Sum computes the poseidon hash (sponge mode) of msg from seed
+func Sum(seed string, msg []byte) []byte {
+	d := NewPoseidon(seed)
+	d.Write(msg)
+	return d.Sum(nil)
+}
+`
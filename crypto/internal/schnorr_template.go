@@ -0,0 +1,355 @@
+package main
+
+const schnorrTemplate = `
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+	{{- if or (eq .Curve "BLS377") (eq .Curve "BW761")}}
+	twistededwards "github.com/consensys/gnark/crypto/twistededwards/{{toLower .Curve}}"
+	{{- else}}
+	"github.com/consensys/gurvy/{{toLower .Curve}}/twistededwards"
+	{{- end}}
+	"golang.org/x/crypto/blake2b"
+)
+
+var (
+	errNotOnCurve      = errors.New("point not on curve")
+	errLengthMismatch  = errors.New("the number of signatures, messages and public keys should match")
+	errScalarTooLarge  = errors.New("S must be lower than the order of the subgroup")
+)
+
+// Signature represents a Schnorr signature (R, S), such that
+// S*Base = R + H(R, A, M)*A, where A is the public key of the signer
+type Signature struct {
+	R twistededwards.Point
+	S fr.Element
+}
+
+// PublicKey Schnorr public key A = x*Base, along with the hash function
+// computing the challenges H(R, A, M)
+type PublicKey struct {
+	A     twistededwards.Point
+	HFunc hash.Hash
+}
+
+// PrivateKey Schnorr private key
+type PrivateKey struct {
+	randSrc [32]byte // randomizer of the nonces (nonce = H(randSrc, msg))
+	scalar  big.Int  // secret scalar x, in [1, Order)
+}
+
+// GetCurveParams get the parameters of the Edwards curve used
+func GetCurveParams() twistededwards.CurveParams {
+	return twistededwards.GetEdwardsCurve()
+}
+
+// New derives a key pair from seed, the challenges being computed with hFunc
+//
+// hFunc is typically a snark friendly hash function (mimc, poseidon), so that the
+// signatures can be verified in a circuit with the same hash function.
+func New(seed [32]byte, hFunc hash.Hash) (PublicKey, PrivateKey) {
+
+	c := GetCurveParams()
+
+	var pub PublicKey
+	var priv PrivateKey
+
+	h := blake2b.Sum512(seed[:])
+
+	// 512 bits reduced mod Order, so that the bias is negligible
+	priv.scalar.SetBytes(h[:]).Mod(&priv.scalar, &c.Order)
+	if priv.scalar.Sign() == 0 {
+		priv.scalar.SetUint64(1)
+	}
+	priv.randSrc = blake2b.Sum256(h[:])
+
+	pub.A.ScalarMul(&c.Base, scalar(&priv.scalar))
+	pub.HFunc = hFunc
+
+	return pub, priv
+}
+
+// Sign signs a message (in Montgomery form)
+//
+// The nonce is derived deterministically from the private key and the message.
+func Sign(message fr.Element, pub PublicKey, priv PrivateKey) (Signature, error) {
+
+	curveParams := GetCurveParams()
+
+	var res Signature
+
+	// nonce = H(randSrc, msg) mod Order
+	randSrc := make([]byte, 0, 32+fr.Limbs*8)
+	randSrc = append(randSrc, priv.randSrc[:]...)
+	randSrc = append(randSrc, message.Bytes()...)
+	randBytes := blake2b.Sum512(randSrc)
+	var nonce big.Int
+	nonce.SetBytes(randBytes[:]).Mod(&nonce, &curveParams.Order)
+
+	// R = nonce*Base
+	res.R.ScalarMul(&curveParams.Base, scalar(&nonce))
+	if !res.R.IsOnCurve() {
+		return Signature{}, errNotOnCurve
+	}
+
+	// S = nonce + H(R, A, M)*x mod Order
+	var e, s big.Int
+	challenge(pub.HFunc, &res.R, &pub.A, &message).ToBigIntRegular(&e)
+	s.Mul(&e, &priv.scalar).
+		Add(&s, &nonce).
+		Mod(&s, &curveParams.Order)
+	res.S.SetBigInt(&s)
+
+	return res, nil
+}
+
+// Verify verifies a Schnorr signature, that is it checks that
+// cofactor*S*Base = cofactor*(R + H(R,A,M)*A)
+func Verify(sig Signature, message fr.Element, pub PublicKey) (bool, error) {
+
+	curveParams := GetCurveParams()
+
+	if !pub.A.IsOnCurve() || !sig.R.IsOnCurve() {
+		return false, errNotOnCurve
+	}
+
+	// S < Order, otherwise S+Order would be a second valid signature
+	var s big.Int
+	if sig.S.ToBigIntRegular(&s).Cmp(&curveParams.Order) >= 0 {
+		return false, errScalarTooLarge
+	}
+
+	// lhs = cofactor*S*Base
+	var lhs twistededwards.Point
+	lhs.ScalarMul(&curveParams.Base, sig.S.ToRegular()).
+		ScalarMul(&lhs, curveParams.Cofactor)
+
+	// rhs = cofactor*(R + H(R,A,M)*A)
+	var rhs twistededwards.Point
+	e := challenge(pub.HFunc, &sig.R, &pub.A, &message)
+	rhs.ScalarMul(&pub.A, e.ToRegular()).
+		Add(&rhs, &sig.R).
+		ScalarMul(&rhs, curveParams.Cofactor)
+
+	return lhs.X.Equal(&rhs.X) && lhs.Y.Equal(&rhs.Y), nil
+}
+
+// BatchVerify verifies several signatures at once, possibly from different signers. It checks
+// that cofactor*(sum(z_i*S_i)*Base - sum(z_i*R_i) - sum(z_i*H(R_i,A_i,M_i)*A_i)) is the neutral element,
+// where the z_i are random 128 bits coefficients.
+//
+// It returns true if and only if all the signatures are valid, except with probability 2^-128,
+// and costs a single scalar multiplication of the base point instead of len(sigs).
+func BatchVerify(sigs []Signature, messages []fr.Element, pubs []PublicKey) (bool, error) {
+
+	if len(sigs) != len(messages) || len(sigs) != len(pubs) {
+		return false, errLengthMismatch
+	}
+
+	curveParams := GetCurveParams()
+
+	var sum twistededwards.PointProj
+	sum.X.SetZero()
+	sum.Y.SetOne()
+	sum.Z.SetOne()
+
+	var zs, zInt, tmpInt big.Int
+	var tmp twistededwards.Point
+	var tmpProj twistededwards.PointProj
+	var z [16]byte
+
+	for i := 0; i < len(sigs); i++ {
+
+		if !pubs[i].A.IsOnCurve() || !sigs[i].R.IsOnCurve() {
+			return false, errNotOnCurve
+		}
+
+		if _, err := rand.Read(z[:]); err != nil {
+			return false, err
+		}
+		zInt.SetBytes(z[:])
+
+		// zs += z_i*S_i, S_i < Order
+		if sigs[i].S.ToBigIntRegular(&tmpInt).Cmp(&curveParams.Order) >= 0 {
+			return false, errScalarTooLarge
+		}
+		tmpInt.Mul(&tmpInt, &zInt)
+		zs.Add(&zs, &tmpInt)
+
+		// sum += z_i*R_i + z_i*H(R_i,A_i,M_i)*A_i
+		tmp.ScalarMul(&sigs[i].R, scalar(&zInt))
+		sum.Add(&sum, tmpProj.FromAffine(&tmp))
+
+		challenge(pubs[i].HFunc, &sigs[i].R, &pubs[i].A, &messages[i]).ToBigIntRegular(&tmpInt)
+		tmpInt.Mul(&tmpInt, &zInt).Mod(&tmpInt, &curveParams.Order)
+		tmp.ScalarMul(&pubs[i].A, scalar(&tmpInt))
+		sum.Add(&sum, tmpProj.FromAffine(&tmp))
+	}
+
+	// lhs = cofactor*sum(z_i*S_i)*Base, rhs = cofactor*sum
+	var lhs, rhs twistededwards.Point
+	zs.Mod(&zs, &curveParams.Order)
+	lhs.ScalarMul(&curveParams.Base, scalar(&zs)).
+		ScalarMul(&lhs, curveParams.Cofactor)
+	rhs.FromProj(&sum).
+		ScalarMul(&rhs, curveParams.Cofactor)
+
+	return lhs.X.Equal(&rhs.X) && lhs.Y.Equal(&rhs.Y), nil
+}
+
+// challenge returns H(R, A, M), all parameters being written in Montgomery form
+func challenge(hFunc hash.Hash, r, a *twistededwards.Point, message *fr.Element) fr.Element {
+	data := []fr.Element{
+		r.X,
+		r.Y,
+		a.X,
+		a.Y,
+		*message,
+	}
+	hFunc.Reset()
+	for i := 0; i < len(data); i++ {
+		hFunc.Write(data[i].Bytes())
+	}
+	var res fr.Element
+	res.SetBytes(hFunc.Sum(nil))
+	return res
+}
+
+// scalar returns s as a scalar for twistededwards.Point.ScalarMul (not in Montgomery form)
+// s must be smaller than the modulus of fr
+func scalar(s *big.Int) fr.Element {
+	var res fr.Element
+	return res.SetBigInt(s).ToRegular()
+}
+`
+
+const schnorrTestTemplate = `
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/{{toLower .Curve}}"
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+func TestSchnorr(t *testing.T) {
+
+	var seed [32]byte
+	s := []byte("schnorr")
+	for i, v := range s {
+		seed[i] = v
+	}
+
+	hFunc := {{toLower .Curve}}.NewMiMC("seed")
+
+	// create a key pair and sign a message
+	pubKey, privKey := New(seed, hFunc)
+	var msg fr.Element
+	msg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	signature, err := Sign(msg, pubKey, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verifies correct msg
+	res, err := Verify(signature, msg, pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature should return true")
+	}
+
+	// S+Order is rejected, otherwise signatures would be malleable
+	curveParams := GetCurveParams()
+	var order fr.Element
+	order.SetBigInt(&curveParams.Order)
+	malleated := signature
+	malleated.S.Add(&malleated.S, &order)
+	if res, err := Verify(malleated, msg, pubKey); err == nil || res {
+		t.Fatal("Verify should reject a signature with S >= Order")
+	}
+
+	// verifies wrong msg
+	msg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035979")
+	res, err = Verify(signature, msg, pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verify wrong signature should be false")
+	}
+
+}
+
+func TestBatchVerify(t *testing.T) {
+
+	const n = 4
+
+	sigs := make([]Signature, n)
+	msgs := make([]fr.Element, n)
+	pubs := make([]PublicKey, n)
+
+	for i := 0; i < n; i++ {
+		var seed [32]byte
+		seed[0] = byte(i)
+		pubKey, privKey := New(seed, {{toLower .Curve}}.NewMiMC("seed"))
+		msgs[i].SetUint64(uint64(42 + i))
+		sig, err := Sign(msgs[i], pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i], pubs[i] = sig, pubKey
+	}
+
+	res, err := BatchVerify(sigs, msgs, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("BatchVerify correct signatures should return true")
+	}
+
+	// swapping two signatures should invalidate the batch
+	sigs[n-1], sigs[n-2] = sigs[n-2], sigs[n-1]
+	res, err = BatchVerify(sigs, msgs, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("BatchVerify wrong signatures should return false")
+	}
+
+	if _, err := BatchVerify(sigs, msgs[1:], pubs); err == nil {
+		t.Fatal("BatchVerify should fail when the lengths don't match")
+	}
+}
+
+// benchmarks
+
+func BenchmarkVerify(b *testing.B) {
+
+	var seed [32]byte
+	s := []byte("schnorr")
+	for i, v := range s {
+		seed[i] = v
+	}
+
+	hFunc := {{toLower .Curve}}.NewMiMC("seed")
+
+	pubKey, privKey := New(seed, hFunc)
+	var msg fr.Element
+	msg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	signature, _ := Sign(msg, pubKey, privKey)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Verify(signature, msg, pubKey)
+	}
+}
+`
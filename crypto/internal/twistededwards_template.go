@@ -0,0 +1,447 @@
+package main
+
+// twisted Edwards curves embedded in the scalar fields which are not covered by gurvy
+
+const twistededwardsCurveTemplate = `
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+// CurveParams curve parameters: ax^2 + y^2 = 1 + d*x^2*y^2
+type CurveParams struct {
+	A, D     fr.Element // in Montgomery form
+	Cofactor fr.Element // not in Montgomery form
+	Order    big.Int
+	Base     Point
+}
+
+var edwards CurveParams
+var initOnce sync.Once
+
+// GetEdwardsCurve returns the twisted Edwards curve on {{.Curve}}'s Fr
+func GetEdwardsCurve() CurveParams {
+	initOnce.Do(initEd{{.Curve}})
+	return edwards
+}
+
+func initEd{{.Curve}}() {
+
+	// a = -1 and d is a non square, so that the addition law is complete
+	edwards.A.SetOne().Neg(&edwards.A)
+{{- if eq .Curve "BLS377"}}
+	edwards.D.SetUint64(3021)
+	edwards.Cofactor.SetUint64(4).FromMont()
+	edwards.Order.SetString("2111115437357092606062206234695386632838870926408408195193685246394721360383", 10)
+
+	edwards.Base.X.SetString("4497879464030519973909970603271755437257548612157028181994697785683032656389")
+	edwards.Base.Y.SetString("4357141146396347889246900916607623952598927460421559113092863576544024487809")
+{{- else if eq .Curve "BW761"}}
+	edwards.D.SetUint64(79743)
+	edwards.Cofactor.SetUint64(8).FromMont()
+	edwards.Order.SetString("32333053251621136751331591711861691692049189094364332567435817881934511297123972799646723302813083835942624121493", 10)
+
+	edwards.Base.X.SetString("174701772324485506941690903512423551998294352968833659960042362742684869862495746426366187462669992073196420267127")
+	edwards.Base.Y.SetString("208487200052258845495340374451540775445408439654930191324011635560142523886549663106522691296420655144190624954833")
+{{- end}}
+}
+`
+
+const twistededwardsPointTemplate = `
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+// Point point on a twisted Edwards curve
+type Point struct {
+	X, Y fr.Element
+}
+
+// PointProj point in projective coordinates
+type PointProj struct {
+	X, Y, Z fr.Element
+}
+
+// Set sets p to p1 and return it
+func (p *PointProj) Set(p1 *PointProj) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.Set(&p1.Z)
+	return p
+}
+
+// NewPoint creates a new instance of Point
+func NewPoint(x, y fr.Element) Point {
+	return Point{x, y}
+}
+
+// IsOnCurve checks if a point is on the twisted Edwards curve
+func (p *Point) IsOnCurve() bool {
+
+	ecurve := GetEdwardsCurve()
+
+	var lhs, rhs, tmp fr.Element
+
+	tmp.Mul(&p.Y, &p.Y)
+	lhs.Mul(&p.X, &p.X).
+		Mul(&lhs, &ecurve.A).
+		Add(&lhs, &tmp)
+
+	tmp.Mul(&p.X, &p.X).
+		Mul(&tmp, &p.Y).
+		Mul(&tmp, &p.Y).
+		Mul(&tmp, &ecurve.D)
+	rhs.SetOne().Add(&rhs, &tmp)
+
+	return lhs.Equal(&rhs)
+}
+
+// Add adds two points (x,y), (u,v) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *Point) Add(p1, p2 *Point) *Point {
+
+	ecurve := GetEdwardsCurve()
+
+	var xu, yv, xv, yu, dxyuv, one, denx, deny fr.Element
+	pRes := new(Point)
+	xv.Mul(&p1.X, &p2.Y)
+	yu.Mul(&p1.Y, &p2.X)
+	pRes.X.Add(&xv, &yu)
+
+	xu.Mul(&p1.X, &p2.X).Mul(&xu, &ecurve.A)
+	yv.Mul(&p1.Y, &p2.Y)
+	pRes.Y.Sub(&yv, &xu)
+
+	dxyuv.Mul(&xv, &yu).Mul(&dxyuv, &ecurve.D)
+	one.SetOne()
+	denx.Add(&one, &dxyuv)
+	deny.Sub(&one, &dxyuv)
+
+	p.X.Div(&pRes.X, &denx)
+	p.Y.Div(&pRes.Y, &deny)
+
+	return p
+}
+
+// Double doubles point (x,y) on a twisted Edwards curve with parameters a, d
+// modifies p
+func (p *Point) Double(p1 *Point) *Point {
+	p.Add(p1, p1)
+	return p
+}
+
+// FromProj sets p in affine from p in projective
+func (p *Point) FromProj(p1 *PointProj) *Point {
+	p.X.Div(&p1.X, &p1.Z)
+	p.Y.Div(&p1.Y, &p1.Z)
+	return p
+}
+
+// FromAffine sets p in projective from p in affine
+func (p *PointProj) FromAffine(p1 *Point) *PointProj {
+	p.X.Set(&p1.X)
+	p.Y.Set(&p1.Y)
+	p.Z.SetOne()
+	return p
+}
+
+// Add adds points in projective coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
+func (p *PointProj) Add(p1, p2 *PointProj) *PointProj {
+
+	var res PointProj
+
+	ecurve := GetEdwardsCurve()
+
+	var A, B, C, D, E, F, G, H, I fr.Element
+	A.Mul(&p1.Z, &p2.Z)
+	B.Square(&A)
+	C.Mul(&p1.X, &p2.X)
+	D.Mul(&p1.Y, &p2.Y)
+	E.Mul(&ecurve.D, &C).Mul(&E, &D)
+	F.Sub(&B, &E)
+	G.Add(&B, &E)
+	H.Add(&p1.X, &p1.Y)
+	I.Add(&p2.X, &p2.Y)
+	res.X.Mul(&H, &I).
+		Sub(&res.X, &C).
+		Sub(&res.X, &D).
+		Mul(&res.X, &A).
+		Mul(&res.X, &F)
+	H.Mul(&ecurve.A, &C)
+	res.Y.Sub(&D, &H).
+		Mul(&res.Y, &A).
+		Mul(&res.Y, &G)
+	res.Z.Mul(&F, &G)
+
+	p.Set(&res)
+	return p
+}
+
+// Double adds points in projective coordinates
+// cf https://hyperelliptic.org/EFD/g1p/auto-twisted-projective.html
+func (p *PointProj) Double(p1 *PointProj) *PointProj {
+
+	var res PointProj
+
+	ecurve := GetEdwardsCurve()
+
+	var B, C, D, E, F, H, J, tmp fr.Element
+
+	B.Add(&p1.X, &p1.Y).Square(&B)
+	C.Square(&p1.X)
+	D.Square(&p1.Y)
+	E.Mul(&ecurve.A, &C)
+	F.Add(&E, &D)
+	H.Square(&p1.Z)
+	tmp.Double(&H)
+	J.Sub(&F, &tmp)
+	res.X.Sub(&B, &C).
+		Sub(&res.X, &D).
+		Mul(&res.X, &J)
+	res.Y.Sub(&E, &D).Mul(&res.Y, &F)
+	res.Z.Mul(&F, &J)
+
+	p.Set(&res)
+	return p
+}
+
+// ScalarMul scalar multiplication of a point
+// p1 points on the twisted Edwards curve
+// c parameters of the twisted Edwards curve
+// scal scalar NOT in Montgomery form
+// modifies p
+func (p *Point) ScalarMul(p1 *Point, scalar fr.Element) *Point {
+
+	var resProj, p1Proj PointProj
+	resProj.X.SetZero()
+	resProj.Y.SetOne()
+	resProj.Z.SetOne()
+
+	p1Proj.FromAffine(p1)
+
+	const wordSize = bits.UintSize
+
+	for i := fr.Limbs - 1; i >= 0; i-- {
+		for j := 0; j < wordSize; j++ {
+			resProj.Double(&resProj)
+			b := (scalar[i] & (uint64(1) << uint64(wordSize-1-j))) >> uint64(wordSize-1-j)
+			if b == 1 {
+				resProj.Add(&resProj, &p1Proj)
+			}
+		}
+	}
+
+	p.FromProj(&resProj)
+
+	return p
+}
+`
+
+const twistededwardsTestTemplate = `
+
+import (
+	"testing"
+
+	"github.com/consensys/gurvy/{{toLower .Curve}}/fr"
+)
+
+{{- if eq .Curve "BLS377"}}
+
+const (
+	p1X = "7856069822550644602350415898705583102804054322676686701686868681180390683191"
+	p1Y = "3241536273212573093538616792816792314537069469501817695485117627942313287470"
+	p2X = "1160920934689122329475619380767212560050567059137293550313010894909728651003"
+	p2Y = "3892573300586196792685369299061752912514312915939418488489340503592993828204"
+
+	addX = "4654218820172810607855477229168076443131830467736982226473160665846420978334"
+	addY = "1685684523699465804894518076871568285204370367240217093290252302527037366869"
+
+	doubleX = "4244114947831893688650603669469076757097414724081120745988665149338439960446"
+	doubleY = "2622481459811798934035477973330769014415710846983519971648718589957417122030"
+
+	scalar  = "1900757810411063447304518370550676354431272003645559775361966729820849848306"
+	scalarX = "256402522044674287296925997123180232350124143993571966033106152254086043453"
+	scalarY = "2299001894953031416218504821581903773425513723708762903170988981064684096760"
+)
+{{- else if eq .Curve "BW761"}}
+
+const (
+	p1X = "174894771747265760533559929001103078948556378341799203622435472480762223789296305368635601128274579989577504667236"
+	p1Y = "122235850285066269240635163786673819200166739357326499490761364783034742670277369333572698629176721514863682340107"
+	p2X = "237409702498946303851939108108878742514744659804169117956961564205290510060050345535658324730720543856236822036299"
+	p2Y = "256282350724670549492987030899972477287304009991681612503810920440636612649006391287272608950880771455664871587830"
+
+	addX = "86522994889238099092677855495080518754283363372354307041165097200468432733757142019899710542128111346523189831367"
+	addY = "8232220140294806353569493298258156913983347607937288853027139110305001363514449599207049243057509879670301568279"
+
+	doubleX = "84533169507331278517210241141368434204424886877564028140382678471336050196161843582506450902894035429488566552065"
+	doubleY = "130027588617512686076896030366282462631730757657975630103806600124469426950153427811083443167809595824208766543993"
+
+	scalar  = "8234104122482341265491137074636836252947884782870784360943022469005013929455"
+	scalarX = "136580728194438533256004129472306948598132622518411259655501970780055429527600581998046142314143828577497526017386"
+	scalarY = "2644741263580453773100925178727766287568208893774418099377728873063780901327397978080822135476827475857960194580"
+)
+{{- end}}
+
+func TestBase(t *testing.T) {
+
+	ecurve := GetEdwardsCurve()
+
+	if !ecurve.Base.IsOnCurve() {
+		t.Fatal("base point should be on the curve")
+	}
+
+	// order*Base should be the neutral element (0, 1)
+	var order fr.Element
+	var p Point
+	order.SetBigInt(&ecurve.Order)
+	p.ScalarMul(&ecurve.Base, order.ToRegular())
+
+	var one fr.Element
+	one.SetOne()
+	if !p.X.IsZero() || !p.Y.Equal(&one) {
+		t.Fatal("base point should be of order ecurve.Order")
+	}
+}
+
+func TestAdd(t *testing.T) {
+
+	var p1, p2 Point
+
+	p1.X.SetString(p1X)
+	p1.Y.SetString(p1Y)
+
+	p2.X.SetString(p2X)
+	p2.Y.SetString(p2Y)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString(addX)
+	expectedY.SetString(addY)
+
+	p1.Add(&p1, &p2)
+
+	if !p1.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestAddProj(t *testing.T) {
+
+	var p1, p2 Point
+	var p1proj, p2proj PointProj
+
+	p1.X.SetString(p1X)
+	p1.Y.SetString(p1Y)
+
+	p2.X.SetString(p2X)
+	p2.Y.SetString(p2Y)
+
+	p1proj.FromAffine(&p1)
+	p2proj.FromAffine(&p2)
+
+	// (X:Y:Z) ~ (2X:2Y:2Z), the addition shouldn't assume Z = 1
+	p2proj.X.Double(&p2proj.X)
+	p2proj.Y.Double(&p2proj.Y)
+	p2proj.Z.Double(&p2proj.Z)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString(addX)
+	expectedY.SetString(addY)
+
+	p1proj.Add(&p1proj, &p2proj)
+	p1.FromProj(&p1proj)
+
+	if !p1.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p1.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+
+}
+
+func TestDouble(t *testing.T) {
+
+	var p Point
+
+	p.X.SetString(p1X)
+	p.Y.SetString(p1Y)
+
+	p.Double(&p)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString(doubleX)
+	expectedY.SetString(doubleY)
+
+	if !p.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+}
+
+func TestDoubleProj(t *testing.T) {
+
+	var p Point
+	var pproj PointProj
+
+	p.X.SetString(p1X)
+	p.Y.SetString(p1Y)
+
+	pproj.FromAffine(&p).Double(&pproj)
+
+	p.FromProj(&pproj)
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString(doubleX)
+	expectedY.SetString(doubleY)
+
+	if !p.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+}
+
+func TestScalarMul(t *testing.T) {
+
+	var p Point
+	var s fr.Element
+
+	p.X.SetString(p1X)
+	p.Y.SetString(p1Y)
+
+	s.SetString(scalar)
+
+	p.ScalarMul(&p, s.ToRegular())
+
+	var expectedX, expectedY fr.Element
+
+	expectedX.SetString(scalarX)
+	expectedY.SetString(scalarY)
+
+	if !p.X.Equal(&expectedX) {
+		t.Fatal("wrong x coordinate")
+	}
+	if !p.Y.Equal(&expectedY) {
+		t.Fatal("wrong y coordinate")
+	}
+}
+`
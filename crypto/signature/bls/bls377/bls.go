@@ -0,0 +1,247 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bls implements BLS signatures on BLS377, with signatures in G1 and public keys in G2.
+//
+// Messages are elements of BLS377's Fp (that is BW761's Fr), and are hashed to G1 with a
+// try and increment method which can be checked in a BW761 circuit (cf std/signature/bls).
+package bls
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/crypto/hash/mimc/bw761"
+	"github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fp"
+	"github.com/consensys/gurvy/bls377/fr"
+	"golang.org/x/crypto/blake2b"
+)
+
+// NbTries maximum number of x coordinates tried when hashing a message to G1
+const NbTries = 64
+
+// HashSeed seed of the mimc hash (on BW761's Fr) used to hash the messages to G1
+const HashSeed = "bls"
+
+// NonResidue smallest quadratic non residue of BLS377's Fp, used to prove that
+// the x coordinates skipped by the hash to G1 are not on the curve
+const NonResidue = 5
+
+var (
+	errHashToG1         = errors.New("no point found when hashing the message to G1")
+	errNotInSubGroup    = errors.New("point not in the subgroup of order r")
+	errNoPublicKeys     = errors.New("at least one public key is needed")
+	errNoSignatures     = errors.New("at least one signature is needed")
+	errInvalidPublicKey = errors.New("invalid public key")
+)
+
+// PublicKey BLS public key, sk*G2 where G2 is the generator of bls377.G2
+type PublicKey struct {
+	A bls377.G2Affine
+}
+
+// PrivateKey BLS private key
+type PrivateKey struct {
+	scalar big.Int // in [1, r)
+}
+
+// Signature BLS signature, sk*H(msg) in bls377.G1
+type Signature struct {
+	S bls377.G1Affine
+}
+
+// HashHint witness of the hash to G1 of a message
+//
+// For the i-th try, x_i = mimc(msg) + i, and Roots[i]^2 = x_i^3 + 1 if Flags[i] is true (x_i is the
+// abscissa of a point of the curve), Roots[i]^2 = NonResidue*(x_i^3 + 1) otherwise. The hash of the
+// message is derived from the first x_i on the curve, and its root in [0, (p-1)/2].
+type HashHint struct {
+	Flags [NbTries]bool
+	Roots [NbTries]fp.Element
+}
+
+// New derives a key pair from seed
+func New(seed [32]byte) (PublicKey, PrivateKey) {
+
+	var pub PublicKey
+	var priv PrivateKey
+
+	// 512 bits reduced mod r, so that the bias is negligible
+	h := blake2b.Sum512(seed[:])
+	priv.scalar.SetBytes(h[:]).Mod(&priv.scalar, fr.Modulus())
+	if priv.scalar.Sign() == 0 {
+		priv.scalar.SetUint64(1)
+	}
+
+	_, _, _, g2 := bls377.Generators()
+	pub.A.ScalarMultiplication(&g2, &priv.scalar)
+
+	return pub, priv
+}
+
+// Sign signs msg, that is it returns sk*H(msg)
+func Sign(msg fp.Element, priv PrivateKey) (Signature, error) {
+	var res Signature
+	hm, err := HashToG1(msg)
+	if err != nil {
+		return res, err
+	}
+	res.S.ScalarMultiplication(&hm, &priv.scalar)
+	return res, nil
+}
+
+// Verify verifies a signature, that is it checks that e(S, G2) = e(H(msg), A)
+func Verify(sig Signature, msg fp.Element, pub PublicKey) (bool, error) {
+	return VerifyAggregate(sig, msg, []PublicKey{pub})
+}
+
+// Aggregate aggregates signatures (of the same message or not), by adding them
+func Aggregate(sigs ...Signature) (Signature, error) {
+	var res Signature
+	if len(sigs) == 0 {
+		return res, errNoSignatures
+	}
+	var acc, tmp bls377.G1Jac
+	acc.FromAffine(&sigs[0].S)
+	for i := 1; i < len(sigs); i++ {
+		tmp.FromAffine(&sigs[i].S)
+		acc.AddAssign(&tmp)
+	}
+	res.S.FromJacobian(&acc)
+	return res, nil
+}
+
+// AggregatePublicKeys returns the sum of the public keys, which verifies the aggregate
+// of the signatures of a same message
+func AggregatePublicKeys(pubs ...PublicKey) (PublicKey, error) {
+	var res PublicKey
+	if len(pubs) == 0 {
+		return res, errNoPublicKeys
+	}
+	var acc, tmp bls377.G2Jac
+	acc.FromAffine(&pubs[0].A)
+	for i := 1; i < len(pubs); i++ {
+		tmp.FromAffine(&pubs[i].A)
+		acc.AddAssign(&tmp)
+	}
+	res.A.FromJacobian(&acc)
+	return res, nil
+}
+
+// VerifyAggregate verifies the aggregate signature of msg by the owners of pubs
+//
+// The public keys must come with a proof of possession of the private key, otherwise
+// the aggregate signature is subject to rogue key attacks.
+func VerifyAggregate(sig Signature, msg fp.Element, pubs []PublicKey) (bool, error) {
+
+	apk, err := AggregatePublicKeys(pubs...)
+	if err != nil {
+		return false, err
+	}
+	for i := 0; i < len(pubs); i++ {
+		if !pubs[i].A.IsInSubGroup() || pubs[i].A.IsInfinity() {
+			return false, errInvalidPublicKey
+		}
+	}
+	if !sig.S.IsInSubGroup() {
+		return false, errNotInSubGroup
+	}
+
+	hm, err := HashToG1(msg)
+	if err != nil {
+		return false, err
+	}
+
+	// e(S, -G2) * e(H(msg), A) == 1
+	_, _, _, g2 := bls377.Generators()
+	g2.Neg(&g2)
+	e := bls377.FinalExponentiation(bls377.MillerLoop(sig.S, g2), bls377.MillerLoop(hm, apk.A))
+
+	var one bls377.GT
+	one.SetOne()
+	return e.Equal(&one), nil
+}
+
+// HashToG1 hashes msg to bls377.G1 (cf HashHint)
+func HashToG1(msg fp.Element) (bls377.G1Affine, error) {
+	var res bls377.G1Affine
+	hint, err := NewHashHint(msg)
+	if err != nil {
+		return res, err
+	}
+	for i := 0; i < NbTries; i++ {
+		if hint.Flags[i] {
+			res.X = tryX(msg, i)
+			res.Y = hint.Roots[i]
+			res.ClearCofactor(&res)
+			return res, nil
+		}
+	}
+	return res, errHashToG1
+}
+
+// NewHashHint computes the witness of the hash to G1 of msg
+func NewHashHint(msg fp.Element) (HashHint, error) {
+
+	var res HashHint
+	var nonResidue fp.Element
+	nonResidue.SetUint64(NonResidue)
+
+	found := false
+	for i := 0; i < NbTries; i++ {
+		x := tryX(msg, i)
+
+		// x^3 + 1
+		var y2 fp.Element
+		y2.Square(&x).Mul(&y2, &x).Add(&y2, new(fp.Element).SetOne())
+
+		if y2.Legendre() != -1 {
+			res.Flags[i] = true
+			res.Roots[i].Sqrt(&y2)
+			if !found && !isCanonical(&res.Roots[i]) {
+				res.Roots[i].Neg(&res.Roots[i])
+			}
+			found = true
+		} else {
+			y2.Mul(&y2, &nonResidue)
+			res.Roots[i].Sqrt(&y2)
+		}
+	}
+	if !found {
+		return res, errHashToG1
+	}
+	return res, nil
+}
+
+// tryX returns x_i = mimc(msg) + i
+func tryX(msg fp.Element, i int) fp.Element {
+	h := bw761.NewMiMC(HashSeed)
+	h.Write(msg.Bytes())
+	var res, tmp fp.Element
+	res.SetBytes(h.Sum(nil))
+	tmp.SetUint64(uint64(i))
+	res.Add(&res, &tmp)
+	return res
+}
+
+// isCanonical returns true if y <= (p-1)/2
+func isCanonical(y *fp.Element) bool {
+	var a, b big.Int
+	y.ToBigIntRegular(&a)
+	b.Rsh(fp.Modulus(), 1)
+	return a.Cmp(&b) <= 0
+}
@@ -0,0 +1,112 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls
+
+import (
+	"testing"
+
+	"github.com/consensys/gurvy/bls377/fp"
+)
+
+func TestHashToG1(t *testing.T) {
+
+	for i := uint64(0); i < 16; i++ {
+		var msg fp.Element
+		msg.SetUint64(i)
+		hm, err := HashToG1(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hm.IsOnCurve() || !hm.IsInSubGroup() || hm.IsInfinity() {
+			t.Fatal("the hash of a message should be in G1")
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+
+	pub, priv := New([32]byte{42})
+
+	var msg fp.Element
+	msg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	sig, err := Sign(msg, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Verify(sig, msg, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("Verify correct signature should return true")
+	}
+
+	msg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035979")
+	res, err = Verify(sig, msg, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("Verify wrong signature should return false")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+
+	const n = 4
+
+	var msg fp.Element
+	msg.SetUint64(42)
+
+	pubs := make([]PublicKey, n)
+	sigs := make([]Signature, n)
+	for i := 0; i < n; i++ {
+		var priv PrivateKey
+		pubs[i], priv = New([32]byte{byte(i)})
+		var err error
+		sigs[i], err = Sign(msg, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sig, err := Aggregate(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := VerifyAggregate(sig, msg, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res {
+		t.Fatal("VerifyAggregate correct signature should return true")
+	}
+
+	// a missing signer should invalidate the aggregate signature
+	sig, err = Aggregate(sigs[1:]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = VerifyAggregate(sig, msg, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res {
+		t.Fatal("VerifyAggregate wrong signature should return false")
+	}
+}
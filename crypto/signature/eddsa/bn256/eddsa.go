@@ -103,7 +103,7 @@ func Sign(message fr.Element, pub PublicKey, priv PrivateKey) (Signature, error)
 	var randScalar fr.Element
 
 	// randSrc = privKey.randSrc || msg (-> message = MSB message .. LSB message)
-	randSrc := make([]byte, 64)
+	randSrc := make([]byte, 32+fr.Limbs*8)
 	for i, v := range priv.randSrc {
 		randSrc[i] = v
 	}
@@ -113,7 +113,7 @@ func Sign(message fr.Element, pub PublicKey, priv PrivateKey) (Signature, error)
 		return res, err
 	}
 	bufb := buf.Bytes()
-	for i := 0; i < 32; i++ {
+	for i := 0; i < fr.Limbs*8; i++ {
 		randSrc[32+i] = bufb[i]
 	}
 
@@ -28,7 +28,13 @@ type Circuit interface {
 // from the declarative code
 //
 // 3. finally, it converts that to a R1CS
-func Compile(curveID gurvy.ID, circuit Circuit) (r1cs.R1CS, error) {
+//
+// options can enable checks on the resulting R1CS (see WithLint), or a constraint profile (see WithProfile)
+func Compile(curveID gurvy.ID, circuit Circuit, opts ...CompileOption) (r1cs.R1CS, error) {
+	var cfg compileConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 
 	// instantiate our constraint system
 	cs := newConstraintSystem()
@@ -62,6 +68,10 @@ func Compile(curveID gurvy.ID, circuit Circuit) (r1cs.R1CS, error) {
 		return nil, err
 	}
 
+	if cfg.profile != nil {
+		cs.profile = newProfile()
+	}
+
 	// call Define() to fill in the Constraints
 	if err := circuit.Define(curveID, &cs); err != nil {
 		return nil, err
@@ -72,6 +82,20 @@ func Compile(curveID gurvy.ID, circuit Circuit) (r1cs.R1CS, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cs.profile != nil {
+		if err := cs.profile.writeTo(cfg.profile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.lint {
+		issues, err := r1cs.Lint(res)
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) != 0 {
+			return nil, &r1cs.LintError{Issues: issues}
+		}
+	}
 	return res, nil
 }
 
@@ -55,8 +55,9 @@ type ConstraintSystem struct {
 	}
 
 	// Constraints
-	constraints []r1c.R1C // list of R1C that yield an output (for example v3 == v1 * v2, return v3)
-	assertions  []r1c.R1C // list of R1C that yield no output (for example ensuring v1 == v2)
+	constraints []r1c.R1C  // list of R1C that yield an output (for example v3 == v1 * v2, return v3)
+	assertions  []r1c.R1C  // list of R1C that yield no output (for example ensuring v1 == v2)
+	hints       []r1c.Hint // list of hints computing wires outside of the constraints (cf NewHint)
 	oneTerm     r1c.Term
 
 	// Coefficients in the constraints
@@ -68,6 +69,14 @@ type ConstraintSystem struct {
 	debugInfo      []logEntry // list of logs storing information about assertions. If an assertion fails, it prints it in a friendly format
 	unsetVariables []logEntry // unset variables. If a variable is unset, the error is caught when compiling the circuit
 
+	// scopes of the constraints and wires (cf Scope)
+	scopes scopes
+
+	// engine computing the wires as the constraints are added, when the circuit is executed (cf Execute)
+	engine *engine
+
+	// call stacks of the constraints, when the circuit is compiled with a profile (cf WithProfile)
+	profile *profile
 }
 
 // this has quite some impact on frontend performance, especially on large circuits size
@@ -98,6 +107,9 @@ func newConstraintSystem() ConstraintSystem {
 	cs.public.variables[0] = Variable{backend.Public, 0, nil}
 	cs.oneTerm = cs.Term(cs.public.variables[0], bOne)
 
+	cs.scopes.names = []string{""}
+	cs.scopes.ids = map[string]int{"": 0}
+
 	return cs
 }
 
@@ -151,7 +163,36 @@ func (cs *ConstraintSystem) LinearExpression(terms ...r1c.Term) r1c.LinearExpres
 
 func (cs *ConstraintSystem) addAssertion(constraint r1c.R1C, debugInfo logEntry) {
 	cs.assertions = append(cs.assertions, constraint)
+	if cs.scopes.assertions != nil {
+		cs.scopes.assertions = append(cs.scopes.assertions, cs.scopes.current)
+	}
+	if cs.profile != nil {
+		cs.profile.record(cs.scope())
+	}
 	cs.debugInfo = append(cs.debugInfo, debugInfo)
+	if cs.engine != nil {
+		cs.engine.assert(cs, constraint, debugInfo)
+	}
+}
+
+func (cs *ConstraintSystem) addConstraint(constraint r1c.R1C) {
+	cs.constraints = append(cs.constraints, constraint)
+	if cs.scopes.constraints != nil {
+		cs.scopes.constraints = append(cs.scopes.constraints, cs.scopes.current)
+	}
+	if cs.profile != nil {
+		cs.profile.record(cs.scope())
+	}
+	if cs.engine != nil {
+		cs.engine.solve(cs, constraint)
+	}
+}
+
+func (cs *ConstraintSystem) addHint(hint r1c.Hint) {
+	cs.hints = append(cs.hints, hint)
+	if cs.engine != nil {
+		cs.engine.runHint(cs, hint)
+	}
 }
 
 // toR1CS constructs a rank-1 constraint sytem
@@ -170,6 +211,7 @@ func (cs *ConstraintSystem) toR1CS(curveID gurvy.ID) (r1cs.R1CS, error) {
 		SecretWires:     cs.secret.names,
 		PublicWires:     cs.public.names,
 		Coefficients:    cs.coeffs,
+		Hints:           make([]r1c.Hint, len(cs.hints)),
 		Logs:            make([]backend.LogEntry, len(cs.logs)),
 		DebugInfo:       make([]backend.LogEntry, len(cs.debugInfo)),
 	}
@@ -210,6 +252,24 @@ func (cs *ConstraintSystem) toR1CS(curveID gurvy.ID) (r1cs.R1CS, error) {
 		}
 	}
 
+	// same for the inputs and outputs of the hints
+	for i := 0; i < len(cs.hints); i++ {
+		res.Hints[i] = cs.hints[i]
+		res.Hints[i].Inputs = make([]r1c.LinearExpression, len(cs.hints[i].Inputs))
+		for j := 0; j < len(cs.hints[i].Inputs); j++ {
+			res.Hints[i].Inputs[j] = make(r1c.LinearExpression, len(cs.hints[i].Inputs[j]))
+			copy(res.Hints[i].Inputs[j], cs.hints[i].Inputs[j])
+			if err := offsetIDs(res.Hints[i].Inputs[j]); err != nil {
+				return &res, err
+			}
+		}
+		res.Hints[i].Outputs = make([]r1c.Term, len(cs.hints[i].Outputs))
+		copy(res.Hints[i].Outputs, cs.hints[i].Outputs)
+		if err := offsetIDs(res.Hints[i].Outputs); err != nil {
+			return &res, err
+		}
+	}
+
 	// we need to offset the ids in logs too
 	for i := 0; i < len(cs.logs); i++ {
 		entry := backend.LogEntry{
@@ -252,6 +312,16 @@ func (cs *ConstraintSystem) toR1CS(curveID gurvy.ID) (r1cs.R1CS, error) {
 		res.DebugInfo[i] = entry
 	}
 
+	// scopes of the constraints and wires, the inputs being in the root scope
+	if cs.scopes.constraints != nil {
+		res.Scopes = cs.scopes.names
+		res.ConstraintScopes = make([]int, 0, res.NbConstraints)
+		res.ConstraintScopes = append(res.ConstraintScopes, cs.scopes.constraints...)
+		res.ConstraintScopes = append(res.ConstraintScopes, cs.scopes.assertions...)
+		res.WireScopes = make([]int, res.NbWires)
+		copy(res.WireScopes, cs.scopes.internal)
+	}
+
 	if curveID == gurvy.UNKNOWN {
 		return &res, nil
 	}
@@ -280,7 +350,8 @@ func (cs *ConstraintSystem) coeffID(b *big.Int) int {
 
 // Println enables circuit debugging and behaves almost like fmt.Println()
 //
-// the print will be done once the R1CS.Solve() method is executed
+// the print will be done once the R1CS.Solve() method is executed, or when the Println is reached
+// if the circuit is executed (cf Execute), to stdout or to the writer set by backend.WithLogWriter
 //
 // if one of the input is a Variable, its value will be resolved avec R1CS.Solve() method is called
 func (cs *ConstraintSystem) Println(a ...interface{}) {
@@ -329,6 +400,9 @@ func (cs *ConstraintSystem) Println(a ...interface{}) {
 	entry.format = sbb.String()
 
 	cs.logs = append(cs.logs, entry)
+	if cs.engine != nil {
+		fmt.Fprint(cs.engine.logs, cs.engine.resolve(entry))
+	}
 }
 
 // newInternalVariable creates a new wire, appends it on the list of wires of the circuit, sets
@@ -339,6 +413,9 @@ func (cs *ConstraintSystem) newInternalVariable() Variable {
 		visibility: backend.Internal,
 	}
 	cs.internal.variables = append(cs.internal.variables, res)
+	if cs.scopes.internal != nil {
+		cs.scopes.internal = append(cs.scopes.internal, cs.scopes.current)
+	}
 	return res
 }
 
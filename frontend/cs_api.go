@@ -56,7 +56,7 @@ func (cs *ConstraintSystem) Add(i1, i2 interface{}, in ...interface{}) Variable
 	}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -96,7 +96,7 @@ func (cs *ConstraintSystem) Sub(i1, i2 interface{}) Variable {
 	}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -143,7 +143,7 @@ func (cs *ConstraintSystem) Mul(i1, i2 interface{}, in ...interface{}) Variable
 			cs.Term(_res, bOne),
 		}
 		constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-		cs.constraints = append(cs.constraints, constraint)
+		cs.addConstraint(constraint)
 		return _res
 	}
 
@@ -164,7 +164,7 @@ func (cs *ConstraintSystem) Inverse(v Variable) Variable {
 	R := r1c.LinearExpression{cs.Term(v, bOne)}
 	O := r1c.LinearExpression{cs.oneTerm}
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -203,7 +203,7 @@ func (cs *ConstraintSystem) Div(i1, i2 interface{}) Variable {
 	R := r1c.LinearExpression{cs.Term(res, bOne)}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -228,7 +228,7 @@ func (cs *ConstraintSystem) Xor(a, b Variable) Variable {
 	}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -266,7 +266,7 @@ func (cs *ConstraintSystem) ToBinary(a Variable, nbBits int) []Variable {
 	}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.BinaryDec}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 
@@ -300,7 +300,7 @@ func (cs *ConstraintSystem) FromBinary(b ...Variable) Variable {
 	}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -347,7 +347,7 @@ func (cs *ConstraintSystem) Select(b Variable, i1, i2 interface{}) Variable {
 	O = append(O, toAppend...)
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 }
@@ -377,12 +377,54 @@ func (cs *ConstraintSystem) Constant(input interface{}) Variable {
 	O := r1c.LinearExpression{cs.Term(res, bOne)}
 
 	constraint := r1c.R1C{L: L, R: R, O: O, Solver: r1c.SingleOutput}
-	cs.constraints = append(cs.constraints, constraint)
+	cs.addConstraint(constraint)
 
 	return res
 
 }
 
+// NewHint returns nbOutputs variables computed by the hint function registered under id
+// (cf backend.RegisterHint), from the values of inputs
+//
+// inputs can be Variables, LinearExpressions or constants (see backend.FromInterface)
+//
+// The outputs are not constrained: the caller must constrain them (for example, an inverse
+// computed by a hint must be checked with a multiplication), otherwise the prover can set them freely.
+func (cs *ConstraintSystem) NewHint(id string, nbOutputs int, inputs ...interface{}) []Variable {
+	if _, ok := backend.GetHint(id); !ok {
+		panic("hint " + id + " is not registered")
+	}
+
+	hint := r1c.Hint{
+		ID:       id,
+		Inputs:   make([]r1c.LinearExpression, len(inputs)),
+		Outputs:  make([]r1c.Term, nbOutputs),
+		Position: len(cs.constraints),
+	}
+
+	for i, input := range inputs {
+		switch t := input.(type) {
+		case r1c.LinearExpression:
+			hint.Inputs[i] = make(r1c.LinearExpression, len(t))
+			copy(hint.Inputs[i], t)
+		case Variable:
+			hint.Inputs[i] = r1c.LinearExpression{cs.Term(t, bOne)}
+		default:
+			n := backend.FromInterface(t)
+			hint.Inputs[i] = r1c.LinearExpression{cs.Term(cs.oneVariable(), &n)}
+		}
+	}
+
+	res := make([]Variable, nbOutputs)
+	for i := 0; i < nbOutputs; i++ {
+		res[i] = cs.newInternalVariable()
+		hint.Outputs[i] = cs.Term(res[i], bOne)
+	}
+	cs.addHint(hint)
+
+	return res
+}
+
 // AssertIsEqual adds an assertion in the constraint system (i1 == i2)
 func (cs *ConstraintSystem) AssertIsEqual(i1, i2 interface{}) {
 	// encoded as L * R == O
@@ -583,12 +625,17 @@ func (cs *ConstraintSystem) mustBeLessOrEqCst(v Variable, bound big.Int) {
 		debugInfo.format += "\n" + stack[i]
 	}
 
-	const nbBits = 256
-	const nbWords = 4
 	const wordSize = 64
 
-	vBits := cs.ToBinary(v, nbBits)
+	// 256 bits, or more if the bound doesn't fit (snark field of bw761)
 	boundBits := bound.Bits()
+	nbWords := 4
+	if len(boundBits) > nbWords {
+		nbWords = len(boundBits)
+	}
+	nbBits := nbWords * wordSize
+
+	vBits := cs.ToBinary(v, nbBits)
 	l := len(boundBits)
 	if len(boundBits) < nbWords {
 		for i := 0; i < nbWords-l; i++ {
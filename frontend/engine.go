@@ -0,0 +1,424 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+// moduli of the snark fields of the supported curves
+var moduli = map[gurvy.ID]func() *big.Int{
+	gurvy.BN256:  fr_bn256.Modulus,
+	gurvy.BLS381: fr_bls381.Modulus,
+	gurvy.BLS377: fr_bls377.Modulus,
+	gurvy.BW761:  fr_bw761.Modulus,
+}
+
+// engine evaluates the wires of a ConstraintSystem as the constraints are added (cf Execute)
+type engine struct {
+	modulus  *big.Int
+	public   []big.Int
+	secret   []big.Int
+	internal []big.Int
+	solved   []bool // solved[i] is true if internal[i] is computed
+	logs     io.Writer
+	scope    string // current scope (cf Scope)
+
+	// assertions on wires which are not computed yet (for example, the booleans of ToBinary are
+	// constrained before their decomposition), checked as soon as their wires are computed
+	pending []pendingAssertion
+}
+
+// pendingAssertion assertion postponed until its wires are computed, with the call stack which added it
+type pendingAssertion struct {
+	constraint r1c.R1C
+	debugInfo  logEntry
+	pc         []uintptr
+	scope      string
+}
+
+// engineFailure is the panic raised by the engine to stop the execution of Define
+type engineFailure struct {
+	err error
+}
+
+// Execute runs circuit.Define on a ConstraintSystem which computes the values of the wires from witness,
+// modulo the snark field of curveID, as the constraints are added. No R1CS is built: it returns at the first
+// constraint which is not satisfied, with the Go call stack of the instruction which added it.
+// The logs (cf Println) are printed when they are reached, to stdout or to the writer set by backend.WithLogWriter.
+//
+// witness must be map[string]interface{} or must implement frontend.Circuit (cf ParseWitness). It can be
+// the same object as circuit. The variables of circuit are allocated for the execution, and reset afterwards.
+//
+// Package test wraps Execute in go test helpers.
+//
+// A constraint which is not satisfied is reported as a *backend.UnsatisfiedConstraintError, in which the index of
+// an assertion is -1 (it is known once the R1CS is built).
+func Execute(curveID gurvy.ID, circuit Circuit, witness interface{}, opts ...backend.SolveOption) (err error) {
+	modulus, ok := moduli[curveID]
+	if !ok {
+		return fmt.Errorf("unknown curve id %s", curveID.String())
+	}
+	values, err := ParseWitness(witness)
+	if err != nil {
+		return err
+	}
+
+	cs := newConstraintSystem()
+	e := &engine{modulus: modulus(), logs: backend.NewSolveConfig(opts...).LogWriter}
+	cs.engine = e
+	e.public = []big.Int{*big.NewInt(1)}
+
+	// allocates the inputs, as Compile does, with their values. The variables of the circuit are restored
+	// at the end of the execution, so that the circuit can still be compiled.
+	type allocation struct {
+		field reflect.Value
+		old   Variable
+	}
+	var allocations []allocation
+	defer func() {
+		for _, a := range allocations {
+			a.field.Set(reflect.ValueOf(a.old))
+		}
+	}()
+	var handler leafHandler = func(visibility backend.Visibility, name string, tInput reflect.Value) error {
+		if !tInput.CanSet() {
+			return errors.New("can't set val " + name)
+		}
+		value, ok := values[name]
+		if !ok {
+			return fmt.Errorf("%q: %w", name, backend.ErrInputNotSet)
+		}
+		n := backend.FromInterface(value)
+		n.Mod(&n, e.modulus)
+
+		allocations = append(allocations, allocation{field: tInput, old: tInput.Interface().(Variable)})
+		switch visibility {
+		case backend.Unset, backend.Secret:
+			tInput.Set(reflect.ValueOf(cs.newSecretVariable(name)))
+			e.secret = append(e.secret, n)
+		case backend.Public:
+			tInput.Set(reflect.ValueOf(cs.newPublicVariable(name)))
+			e.public = append(e.public, n)
+		}
+		return nil
+	}
+	if err := parseType(circuit, "", backend.Unset, handler); err != nil {
+		return err
+	}
+
+	// the engine stops Define by panicking with an engineFailure at the first failure
+	defer func() {
+		if r := recover(); r != nil {
+			failure, ok := r.(engineFailure)
+			if !ok {
+				panic(r)
+			}
+			err = failure.err
+		}
+	}()
+	if err := circuit.Define(curveID, &cs); err != nil {
+		return err
+	}
+	if len(e.pending) != 0 {
+		e.failAt(errors.New("an assertion uses wires which are never computed"), e.pending[0].pc, e.pending[0].scope)
+	}
+	return nil
+}
+
+// fail stops the execution, with err followed by the call stack
+func (e *engine) fail(err error) {
+	e.failAt(err, callers(), e.scope)
+}
+
+// failAt stops the execution, with err in scope followed by the call stack of pc
+func (e *engine) failAt(err error, pc []uintptr, scope string) {
+	if uerr, ok := err.(*backend.UnsatisfiedConstraintError); ok {
+		uerr.Scope = scope
+		uerr.Stack = engineCallStack(pc)
+		panic(engineFailure{uerr})
+	}
+	if scope != "" {
+		err = fmt.Errorf("in scope %q: %w", scope, err)
+	}
+	stack := engineCallStack(pc)
+	panic(engineFailure{fmt.Errorf("%w\n%s", err, strings.Join(stack, "\n"))})
+}
+
+// wire returns the value of the wire of t, or nil if it is not computed yet
+func (e *engine) wire(t r1c.Term) *big.Int {
+	id := t.ConstraintID()
+	switch t.ConstraintVisibility() {
+	case backend.Public:
+		return &e.public[id]
+	case backend.Secret:
+		return &e.secret[id]
+	case backend.Internal:
+		if id < len(e.solved) && e.solved[id] {
+			return &e.internal[id]
+		}
+		return nil
+	default:
+		e.fail(fmt.Errorf("%w: a variable of the circuit is not allocated", backend.ErrInputNotSet))
+		return nil
+	}
+}
+
+// setWire sets the value of the internal wire of t
+func (e *engine) setWire(t r1c.Term, value *big.Int) {
+	id := t.ConstraintID()
+	for len(e.internal) <= id {
+		e.internal = append(e.internal, big.Int{})
+		e.solved = append(e.solved, false)
+	}
+	e.internal[id].Mod(value, e.modulus)
+	e.solved[id] = true
+}
+
+// isComputed returns true if all the wires of the constraint c are computed
+func (e *engine) isComputed(c r1c.R1C) bool {
+	for _, l := range []r1c.LinearExpression{c.L, c.R, c.O} {
+		for _, t := range l {
+			if e.wire(t) == nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkPending checks the pending assertions whose wires are computed
+func (e *engine) checkPending(cs *ConstraintSystem) {
+	pending := e.pending[:0]
+	var ready []pendingAssertion
+	for _, p := range e.pending {
+		if e.isComputed(p.constraint) {
+			ready = append(ready, p)
+		} else {
+			pending = append(pending, p)
+		}
+	}
+	e.pending = pending
+	for _, p := range ready {
+		if err := e.check(cs, p.constraint, e.resolve(p.debugInfo), -1); err != nil {
+			e.failAt(err, p.pc, p.scope)
+		}
+	}
+}
+
+// evaluate returns the value of the linear expression l, all its wires being computed
+func (e *engine) evaluate(cs *ConstraintSystem, l r1c.LinearExpression) *big.Int {
+	var res, tmp big.Int
+	for _, t := range l {
+		w := e.wire(t)
+		if w == nil {
+			e.fail(fmt.Errorf("the wire %d is used before being computed", t.ConstraintID()))
+		}
+		tmp.Mul(w, &cs.coeffs[t.CoeffID()])
+		res.Add(&res, &tmp)
+	}
+	return res.Mod(&res, e.modulus)
+}
+
+// solve computes the wire of a computational constraint, and checks that the constraint is satisfied
+func (e *engine) solve(cs *ConstraintSystem, c r1c.R1C) {
+	switch c.Solver {
+	case r1c.SingleOutput:
+		// the wire to compute is the only one which isn't computed yet,
+		// the value of its term being isolated from the other ones
+		var toCompute *r1c.Term
+		var loc int
+		var values [3]big.Int
+		for i, l := range []r1c.LinearExpression{c.L, c.R, c.O} {
+			for j := range l {
+				w := e.wire(l[j])
+				if w == nil {
+					if toCompute != nil && toCompute.ConstraintID() != l[j].ConstraintID() {
+						e.fail(fmt.Errorf("more than one wire to compute in a constraint"))
+					}
+					toCompute, loc = &l[j], i
+					continue
+				}
+				var tmp big.Int
+				tmp.Mul(w, &cs.coeffs[l[j].CoeffID()])
+				values[i].Add(&values[i], &tmp)
+			}
+		}
+		if toCompute != nil {
+			// value of the term (wire * coeff), then of the wire
+			var term big.Int
+			switch loc {
+			case 0, 1:
+				other := &values[1-loc]
+				other.Mod(other, e.modulus)
+				if other.Sign() == 0 {
+					e.fail(fmt.Errorf("%w: division by zero", backend.ErrUnsatisfiedConstraint))
+				}
+				term.ModInverse(other, e.modulus).Mul(&term, &values[2]).Sub(&term, &values[loc])
+			case 2:
+				term.Mul(&values[0], &values[1]).Sub(&term, &values[2])
+			}
+			coeff := &cs.coeffs[toCompute.CoeffID()]
+			var inv big.Int
+			if inv.ModInverse(coeff, e.modulus) == nil {
+				e.fail(fmt.Errorf("%w: the wire to compute has a zero coefficient", backend.ErrUnsatisfiedConstraint))
+			}
+			term.Mul(&term, &inv)
+			e.setWire(*toCompute, &term)
+		}
+
+	case r1c.BinaryDec:
+		// the bits of the value of O
+		n := e.evaluate(cs, c.O)
+		if n.BitLen() > len(c.L) {
+			e.fail(fmt.Errorf("%w: %s doesn't fit on %d bits", backend.ErrUnsatisfiedConstraint, n.String(), len(c.L)))
+		}
+		for i, t := range c.L {
+			if e.wire(t) == nil {
+				e.setWire(t, big.NewInt(int64(n.Bit(i))))
+			}
+		}
+
+	default:
+		panic("unimplemented solving method")
+	}
+
+	if err := e.check(cs, c, "", len(cs.constraints)-1); err != nil {
+		e.fail(err)
+	}
+	e.checkPending(cs)
+}
+
+// check returns a *backend.UnsatisfiedConstraintError if c is not satisfied, with the resolved format of debugInfo.
+// constraint is the index of c in the R1CS, or -1 if it is unknown.
+func (e *engine) check(cs *ConstraintSystem, c r1c.R1C, debugInfo string, constraint int) error {
+	l := e.evaluate(cs, c.L)
+	r := e.evaluate(cs, c.R)
+	o := e.evaluate(cs, c.O)
+	var lr big.Int
+	lr.Mul(l, r).Mod(&lr, e.modulus)
+	if lr.Cmp(o) == 0 {
+		return nil
+	}
+	err := &backend.UnsatisfiedConstraintError{Constraint: constraint, DebugInfo: debugInfo}
+	err.L.Set(l)
+	err.R.Set(r)
+	err.O.Set(o)
+	return err
+}
+
+// resolve returns the format of entry, in which the wires are replaced by their values
+func (e *engine) resolve(entry logEntry) string {
+	toResolve := make([]interface{}, len(entry.toResolve))
+	for i, t := range entry.toResolve {
+		w := e.wire(t)
+		if w == nil {
+			toResolve[i] = "<unsolved>"
+		} else {
+			toResolve[i] = w.String()
+		}
+	}
+	return fmt.Sprintf(entry.format, toResolve...)
+}
+
+// assert checks the assertion c, whose debug info is the expression of the assertion followed by its call stack
+func (e *engine) assert(cs *ConstraintSystem, c r1c.R1C, debugInfo logEntry) {
+	// the call stack of the debug info is replaced by the complete one
+	debugInfo.format = strings.SplitN(debugInfo.format, "\n", 2)[0]
+	if !e.isComputed(c) {
+		e.pending = append(e.pending, pendingAssertion{constraint: c, debugInfo: debugInfo, pc: callers(), scope: e.scope})
+		return
+	}
+	if err := e.check(cs, c, e.resolve(debugInfo), -1); err != nil {
+		e.fail(err)
+	}
+}
+
+// runHint computes the outputs of h
+func (e *engine) runHint(cs *ConstraintSystem, h r1c.Hint) {
+	f, _ := backend.GetHint(h.ID)
+	inputs := make([]big.Int, len(h.Inputs))
+	for i := range h.Inputs {
+		inputs[i].Set(e.evaluate(cs, h.Inputs[i]))
+	}
+	outputs := make([]big.Int, len(h.Outputs))
+	if err := f(inputs, outputs); err != nil {
+		e.fail(fmt.Errorf("hint %q: %w", h.ID, err))
+	}
+	for i, t := range h.Outputs {
+		e.setWire(t, &outputs[i])
+	}
+	e.checkPending(cs)
+}
+
+// callers returns the program counters of the call stack
+func callers() []uintptr {
+	pc := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pc)
+		if n < len(pc) {
+			return pc[:n]
+		}
+		pc = make([]uintptr, 2*len(pc))
+	}
+}
+
+// engineCallStack returns the call stack of pc, from the API of the ConstraintSystem to Define.
+// The internal functions of the engine are skipped.
+func engineCallStack(pc []uintptr) []string {
+	frames := runtime.CallersFrames(pc)
+	var res []string
+	for {
+		frame, more := frames.Next()
+		fe := strings.Split(frame.Function, "/")
+		function := fe[len(fe)-1]
+		if res != nil || !isEngineInternal(function) {
+			res = append(res, fmt.Sprintf("%s\n\t%s:%d", function, frame.File, frame.Line))
+		}
+		if !more || strings.HasSuffix(function, "Define") {
+			break
+		}
+	}
+	return res
+}
+
+// isEngineInternal returns true if function is a method of the engine, or an unexported method of the ConstraintSystem
+func isEngineInternal(function string) bool {
+	const csPrefix = "frontend.(*ConstraintSystem)."
+	if strings.HasPrefix(function, "frontend.(*engine).") {
+		return true
+	}
+	if strings.HasPrefix(function, csPrefix) {
+		name := function[len(csPrefix):]
+		return name != "" && strings.ToLower(name[:1]) == name[:1]
+	}
+	return false
+}
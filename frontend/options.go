@@ -0,0 +1,44 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import "io"
+
+// CompileOption configures Compile
+type CompileOption func(*compileConfig)
+
+type compileConfig struct {
+	lint    bool
+	profile io.Writer
+}
+
+// WithLint makes Compile fail with a *r1cs.LintError if r1cs.Lint reports an issue
+// (unused secret input or internal wire, constant constraint)
+func WithLint() CompileOption {
+	return func(cfg *compileConfig) {
+		cfg.lint = true
+	}
+}
+
+// WithProfile makes Compile record the call stack of every constraint, and write to w a pprof
+// profile in which the sample value is the number of constraints. The functions creating the
+// constraints are then listed by "go tool pprof -top circuit.pprof".
+func WithProfile(w io.Writer) CompileOption {
+	return func(cfg *compileConfig) {
+		cfg.profile = w
+	}
+}
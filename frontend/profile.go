@@ -0,0 +1,208 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"runtime"
+	"strings"
+)
+
+// profile records the call stack of each constraint (cf WithProfile)
+type profile struct {
+	stacks  [][]uintptr
+	scopes  []string
+	samples map[string]int // scope and stack key -> index in stacks, scopes and counts
+	counts  []int64
+}
+
+func newProfile() *profile {
+	return &profile{samples: make(map[string]int)}
+}
+
+// record adds a constraint created in scope by the calling function
+func (p *profile) record(scope string) {
+	pc := callers()
+	key := make([]byte, 8*len(pc), 8*len(pc)+len(scope))
+	for i := 0; i < len(pc); i++ {
+		binary.LittleEndian.PutUint64(key[8*i:], uint64(pc[i]))
+	}
+	key = append(key, scope...)
+	if i, ok := p.samples[string(key)]; ok {
+		p.counts[i]++
+		return
+	}
+	p.samples[string(key)] = len(p.stacks)
+	p.stacks = append(p.stacks, pc)
+	p.scopes = append(p.scopes, scope)
+	p.counts = append(p.counts, 1)
+}
+
+// isFrontendInternal returns true if function belongs to the frontend package, and is not a Define method
+func isFrontendInternal(function string) bool {
+	return strings.HasPrefix(function, "github.com/consensys/gnark/frontend.") && !strings.HasSuffix(function, "Define")
+}
+
+// writeTo writes the profile in the pprof format (gzipped protocol buffer, see
+// https://github.com/google/pprof/blob/master/proto/profile.proto), the sample value being
+// the number of constraints. The frames of the frontend are skipped, such that the leaf of a sample
+// is the function which called the ConstraintSystem API. The samples have a "scope" label (cf Scope).
+func (p *profile) writeTo(w io.Writer) error {
+	var b protoBuffer
+
+	stringIDs := map[string]int64{"": 0}
+	stringTable := []string{""}
+	str := func(s string) int64 {
+		if id, ok := stringIDs[s]; ok {
+			return id
+		}
+		stringIDs[s] = int64(len(stringTable))
+		stringTable = append(stringTable, s)
+		return stringIDs[s]
+	}
+
+	// sample_type
+	b.message(1, func(b *protoBuffer) {
+		b.int64(1, str("constraints"))
+		b.int64(2, str("count"))
+	})
+
+	type location struct {
+		function, file string
+		line           int
+	}
+	locations := make(map[location]uint64)
+	functions := make(map[string]uint64)
+	var locationsBuf, functionsBuf protoBuffer
+
+	for i, pc := range p.stacks {
+		var ids []uint64
+		frames := runtime.CallersFrames(pc)
+		for {
+			frame, more := frames.Next()
+			if len(ids) != 0 || !isFrontendInternal(frame.Function) {
+				l := location{frame.Function, frame.File, frame.Line}
+				id, ok := locations[l]
+				if !ok {
+					fID, ok := functions[l.function]
+					if !ok {
+						fID = uint64(len(functions) + 1)
+						functions[l.function] = fID
+						functionsBuf.message(5, func(b *protoBuffer) {
+							b.uint64(1, fID)
+							b.int64(2, str(l.function))
+							b.int64(3, str(l.function))
+							b.int64(4, str(l.file))
+						})
+					}
+					id = uint64(len(locations) + 1)
+					locations[l] = id
+					locationsBuf.message(4, func(b *protoBuffer) {
+						b.uint64(1, id)
+						b.message(4, func(b *protoBuffer) {
+							b.uint64(1, fID)
+							b.int64(2, int64(l.line))
+						})
+					})
+				}
+				ids = append(ids, id)
+			}
+			if !more {
+				break
+			}
+		}
+
+		// sample
+		b.message(2, func(b *protoBuffer) {
+			b.packedUint64(1, ids)
+			b.packedUint64(2, []uint64{uint64(p.counts[i])})
+			if p.scopes[i] != "" {
+				b.message(3, func(b *protoBuffer) {
+					b.int64(1, str("scope"))
+					b.int64(2, str(p.scopes[i]))
+				})
+			}
+		})
+	}
+
+	b.buf = append(b.buf, locationsBuf.buf...)
+	b.buf = append(b.buf, functionsBuf.buf...)
+	for _, s := range stringTable {
+		b.string(6, s)
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b.buf); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// protoBuffer encodes protocol buffer fields
+type protoBuffer struct {
+	buf []byte
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.buf = append(b.buf, byte(x)|0x80)
+		x >>= 7
+	}
+	b.buf = append(b.buf, byte(x))
+}
+
+func (b *protoBuffer) tag(field, wireType int) {
+	b.varint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (b *protoBuffer) uint64(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.tag(field, 0)
+	b.varint(x)
+}
+
+func (b *protoBuffer) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+func (b *protoBuffer) packedUint64(field int, x []uint64) {
+	var packed protoBuffer
+	for _, v := range x {
+		packed.varint(v)
+	}
+	b.bytes(field, packed.buf)
+}
+
+func (b *protoBuffer) bytes(field int, x []byte) {
+	b.tag(field, 2)
+	b.varint(uint64(len(x)))
+	b.buf = append(b.buf, x...)
+}
+
+func (b *protoBuffer) string(field int, s string) {
+	b.bytes(field, []byte(s))
+}
+
+func (b *protoBuffer) message(field int, f func(b *protoBuffer)) {
+	var m protoBuffer
+	f(&m)
+	b.bytes(field, m.buf)
+}
@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	"github.com/consensys/gurvy"
+)
+
+type profiledCircuit struct {
+	X Variable
+	Y Variable `gnark:",public"`
+}
+
+func (circuit *profiledCircuit) Define(curveID gurvy.ID, cs *ConstraintSystem) error {
+	var x3 Variable
+	cs.Scope("cube", func() {
+		x3 = cs.Mul(circuit.X, circuit.X, circuit.X)
+	})
+	cs.AssertIsEqual(circuit.Y, cs.Add(x3, circuit.X, 5))
+	return nil
+}
+
+func TestProfile(t *testing.T) {
+	var buf bytes.Buffer
+	var circuit profiledCircuit
+	r1cs, err := Compile(gurvy.BN256, &circuit, WithProfile(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(data, []byte("frontend.(*profiledCircuit).Define")) {
+		t.Fatal("the profile should contain the Define method")
+	}
+	if !bytes.Contains(data, []byte("scope")) || !bytes.Contains(data, []byte("cube")) {
+		t.Fatal("the profile should contain the scope labels")
+	}
+	if bytes.Contains(data, []byte("frontend.(*ConstraintSystem).Mul")) {
+		t.Fatal("the frames of the ConstraintSystem should be skipped")
+	}
+
+	// the sum of the sample values is the number of constraints
+	var nbConstraints uint64
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		data = data[n:]
+		if tag&7 != 2 {
+			t.Fatal("unexpected wire type")
+		}
+		size, n := binary.Uvarint(data)
+		field := data[n : n+int(size)]
+		data = data[n+int(size):]
+		if tag>>3 != 2 {
+			continue
+		}
+
+		// sample: find the packed values
+		for len(field) > 0 {
+			tag, n := binary.Uvarint(field)
+			size, m := binary.Uvarint(field[n:])
+			values := field[n+m : n+m+int(size)]
+			field = field[n+m+int(size):]
+			for tag>>3 == 2 && len(values) > 0 {
+				v, n := binary.Uvarint(values)
+				values = values[n:]
+				nbConstraints += v
+			}
+		}
+	}
+	if nbConstraints != uint64(r1cs.GetNbConstraints()) {
+		t.Fatalf("expected %d constraints in the profile, got %d", r1cs.GetNbConstraints(), nbConstraints)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frontend
+
+import "strings"
+
+// scopes tags the constraints and wires with the scope they are created in (cf Scope)
+type scopes struct {
+	names   []string       // scope names, the first one is the root scope ""
+	ids     map[string]int // index of each name in names
+	path    []string       // scopes entered with PushScope
+	current int            // index in names of the current scope
+
+	// scope of each constraint, assertion and internal variable, nil until a scope is entered
+	constraints []int
+	assertions  []int
+	internal    []int
+}
+
+// Scope calls f in the scope name: the constraints and wires created by f are tagged with it. Scopes
+// appear in solver errors, in the constraint profile (cf WithProfile), and in r1cs.Stats and r1cs.Dump.
+//
+// Scopes are nested: a scope "merkle" entered in the scope "transfer[3]" is "transfer[3]/merkle".
+func (cs *ConstraintSystem) Scope(name string, f func()) {
+	cs.PushScope(name)
+	defer cs.PopScope()
+	f()
+}
+
+// PushScope enters the scope name, until the matching PopScope (cf Scope)
+func (cs *ConstraintSystem) PushScope(name string) {
+	if name == "" {
+		panic("scope name can't be empty")
+	}
+	if cs.scopes.constraints == nil {
+		// the constraints and wires created so far are in the root scope
+		cs.scopes.constraints = make([]int, len(cs.constraints))
+		cs.scopes.assertions = make([]int, len(cs.assertions))
+		cs.scopes.internal = make([]int, len(cs.internal.variables))
+	}
+	cs.scopes.path = append(cs.scopes.path, name)
+	cs.setScope()
+}
+
+// PopScope leaves the scope entered by the last PushScope
+func (cs *ConstraintSystem) PopScope() {
+	if len(cs.scopes.path) == 0 {
+		panic("PopScope called without a matching PushScope")
+	}
+	cs.scopes.path = cs.scopes.path[:len(cs.scopes.path)-1]
+	cs.setScope()
+}
+
+// setScope updates the current scope from the scope path
+func (cs *ConstraintSystem) setScope() {
+	name := strings.Join(cs.scopes.path, "/")
+	id, ok := cs.scopes.ids[name]
+	if !ok {
+		id = len(cs.scopes.names)
+		cs.scopes.names = append(cs.scopes.names, name)
+		cs.scopes.ids[name] = id
+	}
+	cs.scopes.current = id
+	if cs.engine != nil {
+		cs.engine.scope = name
+	}
+}
+
+// scope returns the name of the current scope
+func (cs *ConstraintSystem) scope() string {
+	return cs.scopes.names[cs.scopes.current]
+}
@@ -29,6 +29,7 @@ import (
 
 	"runtime"
 
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/internal/utils"
 )
 
@@ -50,7 +51,7 @@ func (proof *Proof) GetCurveID() gurvy.ID {
 }
 
 // Prove creates proof from a circuit
-func Prove(r1cs *bls377backend.R1CS, pk *ProvingKey, solution map[string]interface{}) (*Proof, error) {
+func Prove(r1cs *bls377backend.R1CS, pk *ProvingKey, solution map[string]interface{}, opts ...backend.SolveOption) (*Proof, error) {
 	nbPrivateWires := r1cs.NbWires - r1cs.NbPublicWires
 
 	// solve the R1CS and compute the a, b, c vectors
@@ -58,7 +59,7 @@ func Prove(r1cs *bls377backend.R1CS, pk *ProvingKey, solution map[string]interfa
 	b := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
 	c := make([]fr.Element, r1cs.NbConstraints, pk.Domain.Cardinality)
 	wireValues := make([]fr.Element, r1cs.NbWires)
-	if err := r1cs.Solve(solution, a, b, c, wireValues); err != nil {
+	if err := r1cs.Solve(solution, a, b, c, wireValues, opts...); err != nil {
 		return nil, err
 	}
 
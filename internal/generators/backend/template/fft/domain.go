@@ -150,7 +150,11 @@ func precomputeExpTable( w fr.Element, table []fr.Element) {
 	n := len(table)
 
 	// see if it makes sense to parallelize exp tables pre-computation
-	interval := (n - 1) / (runtime.NumCPU() / 4)
+	nbTasks := runtime.NumCPU() / 4
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
+	interval := (n - 1) / nbTasks
 	// this ratio roughly correspond to the number of multiplication one can do in place of a Exp operation
 	const ratioExpMul = 6000 / 17
 
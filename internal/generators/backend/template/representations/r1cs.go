@@ -7,12 +7,13 @@ const R1CS = `
 import (
 	"errors"
 	"fmt"
-	"strconv"
+	"io"
 	"math/big"
+	"strings"
 
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/r1cs/r1c"
-	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
 
 	{{ template "import_fr" . }}
 )
@@ -33,6 +34,12 @@ type R1CS struct {
 	NbCOConstraints int // number of constraints that need to be solved, the first of the Constraints slice
 	Constraints     []r1c.R1C
 	Coefficients 	[]fr.Element // R1C coefficients indexes point here
+	Hints           []r1c.Hint   // hints run by the solver, ordered by position
+
+	// Scopes (cf frontend.ConstraintSystem.Scope)
+	Scopes           []string // scope names, the first one is the root scope ""
+	ConstraintScopes []int    // index in Scopes of each constraint, nil if no scope was used
+	WireScopes       []int    // index in Scopes of each wire, nil if no scope was used
 }
 
 // GetNbConstraints returns the number of constraints
@@ -57,12 +64,12 @@ func (r1cs *R1CS) GetCurveID() gurvy.ID {
 
 // IsSolved returns nil if given assignment solves the R1CS and error otherwise
 // this method wraps r1cs.Solve() and allocates r1cs.Solve() inputs 
-func (r1cs *R1CS) IsSolved(assignment map[string]interface{}) error {
+func (r1cs *R1CS) IsSolved(assignment map[string]interface{}, opts ...backend.SolveOption) error {
 	a := make([]fr.Element, r1cs.NbConstraints)
 	b := make([]fr.Element, r1cs.NbConstraints)
 	c := make([]fr.Element, r1cs.NbConstraints)
 	wireValues := make([]fr.Element, r1cs.NbWires)
-	return r1cs.Solve(assignment, a,b,c,wireValues)
+	return r1cs.Solve(assignment, a,b,c,wireValues, opts...)
 }
 
 // Solve sets all the wires and returns the a, b, c vectors.
@@ -70,7 +77,10 @@ func (r1cs *R1CS) IsSolved(assignment map[string]interface{}) error {
 // assignment: map[string]value: contains the input variables
 // a, b, c vectors: ab-c = hz
 // wireValues =  [intermediateVariables | privateInputs | publicInputs]
-func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues []fr.Element) error {
+// if an assertion is not satisfied, the error is a *backend.UnsatisfiedConstraintError
+func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues []fr.Element, opts ...backend.SolveOption) error {
+	cfg := backend.NewSolveConfig(opts...)
+
 	// compute the wires and the a, b, c polynomials
 	if (len(a) != r1cs.NbConstraints || len(b) != r1cs.NbConstraints || len(c) != r1cs.NbConstraints||len(wireValues) != r1cs.NbWires){
 			return errors.New("invalid input size: len(a, b, c) == r1cs.NbConstraints and len(wireValues) == r1cs.NbWires")
@@ -119,26 +129,45 @@ func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues [
 
 	// now that we know all inputs are set, defer log printing once all wireValues are computed
 	// (or sooner, if a constraint is not satisfied)
-	defer r1cs.printLogs(wireValues, wireInstantiated)
+	defer r1cs.printLogs(cfg.LogWriter, wireValues, wireInstantiated)
 
 	// check if there is an inconsistant constraint
 	var check fr.Element
 
+	// hints are run before the computational constraint at their position
+	nextHint := 0
+	runHints := func(position int) error {
+		for ; nextHint < len(r1cs.Hints) && r1cs.Hints[nextHint].Position == position; nextHint++ {
+			if err := r1cs.solveHint(&r1cs.Hints[nextHint], wireInstantiated, wireValues); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// Loop through computational constraints (the one wwe need to solve and compute a wire in)
 	for i:=0; i < r1cs.NbCOConstraints; i++ {
+		if err := runHints(i); err != nil {
+			return err
+		}
+
 		// solve the constraint, this will compute the missing wire of the gate
 		r1cs.solveR1C(&r1cs.Constraints[i], wireInstantiated, wireValues)
 
-		// at this stage we are guaranteed that a[i]*b[i]=c[i]
-		// if not, it means there is a bug in the solver
+		// a[i]*b[i]=c[i] holds unless the constraint has no solution for the current inputs
+		// (for example, an inverse of 0, or a binary decomposition of a too large value)
 		a[i], b[i], c[i] = instantiateR1C(&r1cs.Constraints[i], r1cs, wireValues)
 		
 		check.Mul(&a[i], &b[i])
 		if !check.Equal(&c[i]) {
-			panic("r1c that was solved by the solver result in inconsistant equality a*b != c")
+			return r1cs.unsatisfiedConstraintError(i, a[i], b[i], c[i], wireValues, wireInstantiated)
 		}
 	}
 
+	if err := runHints(r1cs.NbCOConstraints); err != nil {
+		return err
+	}
+
 	// Loop through the assertions -- here all wireValues should be instantiated
 	// if a[i] * b[i] != c[i]; it means the constraint is not satisfied
 	for i:=r1cs.NbCOConstraints; i < len(r1cs.Constraints); i++ {
@@ -149,15 +178,56 @@ func (r1cs *R1CS) Solve(assignment map[string]interface{}, a, b, c, wireValues [
 		// check that the constraint is satisfied
 		check.Mul(&a[i], &b[i])
 		if !check.Equal(&c[i]) {
-			debugInfo := r1cs.DebugInfo[i - r1cs.NbCOConstraints] 
-			debugInfoStr := r1cs.logValue(debugInfo, wireValues, wireInstantiated)
-			return fmt.Errorf("%w: %s",  backend.ErrUnsatisfiedConstraint, debugInfoStr)
+			return r1cs.unsatisfiedConstraintError(i, a[i], b[i], c[i], wireValues, wireInstantiated)
 		}
 	}
 
 	return nil
 }
 
+// ConstraintScope returns the scope of the i-th constraint, "" if it is in the root scope
+func (r1cs *R1CS) ConstraintScope(i int) string {
+	if r1cs.ConstraintScopes == nil {
+		return ""
+	}
+	return r1cs.Scopes[r1cs.ConstraintScopes[i]]
+}
+
+// WireScope returns the scope of the i-th wire, "" if it is in the root scope
+func (r1cs *R1CS) WireScope(i int) string {
+	if r1cs.WireScopes == nil {
+		return ""
+	}
+	return r1cs.Scopes[r1cs.WireScopes[i]]
+}
+
+// unsatisfiedConstraintError returns the error of the i-th constraint
+// only the assertions have a debug info
+func (r1cs *R1CS) unsatisfiedConstraintError(i int, a, b, c fr.Element, wireValues []fr.Element, wireInstantiated []bool) error {
+	err := &backend.UnsatisfiedConstraintError{
+		Constraint: i,
+		Scope: r1cs.ConstraintScope(i),
+	}
+	a.ToBigIntRegular(&err.L)
+	b.ToBigIntRegular(&err.R)
+	c.ToBigIntRegular(&err.O)
+	if i < r1cs.NbCOConstraints {
+		return err
+	}
+
+	// the debug info is the assertion, followed by its call stack (function\n\tfile:line frames)
+	lines := strings.Split(r1cs.logValue(r1cs.DebugInfo[i - r1cs.NbCOConstraints], wireValues, wireInstantiated), "\n")
+	err.DebugInfo = lines[0]
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "\t") && len(err.Stack) != 0 {
+			err.Stack[len(err.Stack)-1] += "\n" + line
+		} else {
+			err.Stack = append(err.Stack, line)
+		}
+	}
+	return err
+}
+
 func (r1cs *R1CS) logValue(entry backend.LogEntry, wireValues []fr.Element, wireInstantiated []bool) string {
 	var toResolve []interface{}
 	for j := 0; j < len(entry.ToResolve); j++ {
@@ -170,11 +240,18 @@ func (r1cs *R1CS) logValue(entry backend.LogEntry, wireValues []fr.Element, wire
 	return fmt.Sprintf(entry.Format, toResolve...)
 }
 
-func (r1cs *R1CS) printLogs( wireValues []fr.Element, wireInstantiated []bool) {
+func (r1cs *R1CS) printLogs(w io.Writer, wireValues []fr.Element, wireInstantiated []bool) {
 
-	// for each log, resolve the wire values and print the log to stdout
+	// for each log, resolve the wire values and print the log to w
+	// if the solver stopped on an unsatisfied constraint, the logs of the wires it didn't reach are skipped
 	for i := 0; i < len(r1cs.Logs); i++ {
-		fmt.Print(r1cs.logValue(r1cs.Logs[i], wireValues, wireInstantiated))
+		solved := true
+		for _, wireID := range r1cs.Logs[i].ToResolve {
+			solved = solved && wireInstantiated[wireID]
+		}
+		if solved {
+			fmt.Fprint(w, r1cs.logValue(r1cs.Logs[i], wireValues, wireInstantiated))
+		}
 	}
 }
 
@@ -239,6 +316,35 @@ func instantiateR1C(r *r1c.R1C, r1cs *R1CS, wireValues []fr.Element) (a, b, c fr
 	return
 }
 
+// solveHint computes the outputs of a hint from the values of its inputs
+func (r1cs *R1CS) solveHint(h *r1c.Hint, wireInstantiated []bool, wireValues []fr.Element) error {
+	f, ok := backend.GetHint(h.ID)
+	if !ok {
+		return fmt.Errorf("%q: %w", h.ID, backend.ErrUnknownHint)
+	}
+
+	inputs := make([]big.Int, len(h.Inputs))
+	for i := 0; i < len(h.Inputs); i++ {
+		var v fr.Element
+		for _, t := range h.Inputs[i] {
+			r1cs.AddTerm(&v, t, wireValues[t.ConstraintID()])
+		}
+		v.ToBigIntRegular(&inputs[i])
+	}
+
+	outputs := make([]big.Int, len(h.Outputs))
+	if err := f(inputs, outputs); err != nil {
+		return fmt.Errorf("hint %q: %w", h.ID, err)
+	}
+
+	for i, t := range h.Outputs {
+		cID := t.ConstraintID()
+		wireValues[cID].SetBigInt(&outputs[i])
+		wireInstantiated[cID] = true
+	}
+	return nil
+}
+
 // solveR1c computes a wire by solving a r1cs
 // the function searches for the unset wire (either the unset wire is
 // alone, or it can be computed without ambiguity using the other computed wires
@@ -5,6 +5,8 @@ const R1CSConvertor = `
 
 
 import (
+	"math/big"
+
 	{{ template "import_backend" . }}
 	{{ template "import_fr" . }}
 )
@@ -21,8 +23,12 @@ func (r1cs *UntypedR1CS) to{{toUpper .Curve}}() *{{toLower .Curve}}backend.R1CS
 		NbCOConstraints:	r1cs.NbCOConstraints,
 		Constraints: 		r1cs.Constraints,
 		Coefficients: 		make([]fr.Element, len(r1cs.Coefficients)),
+		Hints: 				r1cs.Hints,
 		Logs:				r1cs.Logs,
 		DebugInfo: 			r1cs.DebugInfo,
+		Scopes: 			r1cs.Scopes,
+		ConstraintScopes: 	r1cs.ConstraintScopes,
+		WireScopes: 		r1cs.WireScopes,
 	}
 
 	for i := 0; i < len(r1cs.Coefficients); i++ {
@@ -32,4 +38,49 @@ func (r1cs *UntypedR1CS) to{{toUpper .Curve}}() *{{toLower .Curve}}backend.R1CS
 	return &toReturn
 }
 
+// from{{toUpper .Curve}} converts the coefficients of a {{toUpper .Curve}} R1CS back to big.Int
+func from{{toUpper .Curve}}(r1cs *{{toLower .Curve}}backend.R1CS) *UntypedR1CS {
+
+	toReturn := UntypedR1CS{
+		NbWires:        	r1cs.NbWires,
+		NbPublicWires:  	r1cs.NbPublicWires,
+		NbSecretWires:  	r1cs.NbSecretWires,
+		SecretWires:    	r1cs.SecretWires,
+		PublicWires:    	r1cs.PublicWires,
+		NbConstraints:  	r1cs.NbConstraints,
+		NbCOConstraints:	r1cs.NbCOConstraints,
+		Constraints: 		r1cs.Constraints,
+		Coefficients: 		make([]big.Int, len(r1cs.Coefficients)),
+		Hints: 				r1cs.Hints,
+		Logs:				r1cs.Logs,
+		DebugInfo: 			r1cs.DebugInfo,
+		Scopes: 			r1cs.Scopes,
+		ConstraintScopes: 	r1cs.ConstraintScopes,
+		WireScopes: 		r1cs.WireScopes,
+	}
+
+	for i := 0; i < len(r1cs.Coefficients); i++ {
+		r1cs.Coefficients[i].ToBigIntRegular(&toReturn.Coefficients[i])
+	}
+
+	return &toReturn
+}
+
+// solve{{toUpper .Curve}} solves the R1CS and returns the wire values in regular form
+func solve{{toUpper .Curve}}(r1cs *{{toLower .Curve}}backend.R1CS, assignment map[string]interface{}) ([]big.Int, error) {
+	a := make([]fr.Element, r1cs.NbConstraints)
+	b := make([]fr.Element, r1cs.NbConstraints)
+	c := make([]fr.Element, r1cs.NbConstraints)
+	wireValues := make([]fr.Element, r1cs.NbWires)
+	if err := r1cs.Solve(assignment, a, b, c, wireValues); err != nil {
+		return nil, err
+	}
+
+	toReturn := make([]big.Int, len(wireValues))
+	for i := 0; i < len(wireValues); i++ {
+		wireValues[i].ToBigIntRegular(&toReturn[i])
+	}
+	return toReturn, nil
+}
+
 `
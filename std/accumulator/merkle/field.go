@@ -0,0 +1,96 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// Domain separation tags of the trees of field elements, which are the ones of
+// crypto/accumulator/merkletree/<curve>: a leaf is hashed as H(LeafPrefix, leaf),
+// and a node as H(NodePrefix, left, right).
+const (
+	LeafPrefix = 0
+	NodePrefix = 1
+)
+
+// FieldTree gadget of the fixed depth Merkle trees whose leaves are field elements
+// (cf crypto/accumulator/merkletree/<curve>.Tree, with the same hash function)
+type FieldTree struct {
+	h hash.Hash
+}
+
+// NewFieldTree returns a FieldTree hashing with h
+func NewFieldTree(h hash.Hash) FieldTree {
+	return FieldTree{h: h}
+}
+
+// LeafSum returns the hash of leaf, H(LeafPrefix, leaf)
+func (t FieldTree) LeafSum(cs *frontend.ConstraintSystem, leaf frontend.Variable) frontend.Variable {
+	return t.h.Hash(cs, cs.Constant(LeafPrefix), leaf)
+}
+
+// NodeSum returns the hash of the node of children left and right, H(NodePrefix, left, right)
+func (t FieldTree) NodeSum(cs *frontend.ConstraintSystem, left, right frontend.Variable) frontend.Variable {
+	return t.h.Hash(cs, cs.Constant(NodePrefix), left, right)
+}
+
+// Root returns the root of the tree of the given depth whose first leaves are leaves, the others being zero
+func (t FieldTree) Root(cs *frontend.ConstraintSystem, leaves []frontend.Variable, depth int) frontend.Variable {
+	if len(leaves) > 1<<uint(depth) {
+		panic("merkle: too many leaves for the depth of the tree")
+	}
+
+	// the subtrees of zero leaves are hashed once per height
+	empty := t.LeafSum(cs, cs.Constant(0))
+	level := make([]frontend.Variable, len(leaves))
+	for i := range leaves {
+		level[i] = t.LeafSum(cs, leaves[i])
+	}
+	for height := 0; height < depth; height++ {
+		if len(level)%2 == 1 {
+			level = append(level, empty)
+		}
+		parents := make([]frontend.Variable, len(level)/2)
+		for i := range parents {
+			parents[i] = t.NodeSum(cs, level[2*i], level[2*i+1])
+		}
+		level = parents
+		if len(level) == 0 || height < depth-1 {
+			empty = t.NodeSum(cs, empty, empty)
+		}
+	}
+	if len(level) == 0 {
+		return empty
+	}
+	return level[0]
+}
+
+// VerifyProof checks that leaf is the leaf at index in the tree of root merkleRoot, path being the
+// siblings of the nodes from the leaf to the root. The index is decomposed in len(path) bits, as in
+// VerifyProofAtIndex.
+func (t FieldTree) VerifyProof(cs *frontend.ConstraintSystem, merkleRoot, leaf frontend.Variable, path []frontend.Variable, index frontend.Variable) {
+	indexBits := cs.ToBinary(index, len(path))
+	node := t.LeafSum(cs, leaf)
+	for i := range path {
+		left := cs.Select(indexBits[i], path[i], node)
+		right := cs.Select(indexBits[i], node, path[i])
+		node = t.NodeSum(cs, left, right)
+	}
+	cs.AssertIsEqual(node, merkleRoot)
+}
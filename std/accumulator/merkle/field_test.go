@@ -0,0 +1,259 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"crypto/rand"
+	"fmt"
+	gohash "hash"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	merkletree_bls377 "github.com/consensys/gnark/crypto/accumulator/merkletree/bls377"
+	merkletree_bls381 "github.com/consensys/gnark/crypto/accumulator/merkletree/bls381"
+	merkletree_bn256 "github.com/consensys/gnark/crypto/accumulator/merkletree/bn256"
+	merkletree_bw761 "github.com/consensys/gnark/crypto/accumulator/merkletree/bw761"
+	mimc_bls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
+	mimc_bls381 "github.com/consensys/gnark/crypto/hash/mimc/bls381"
+	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	mimc_bw761 "github.com/consensys/gnark/crypto/hash/mimc/bw761"
+	poseidon_bls377 "github.com/consensys/gnark/crypto/hash/poseidon/bls377"
+	poseidon_bls381 "github.com/consensys/gnark/crypto/hash/poseidon/bls381"
+	poseidon_bn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	poseidon_bw761 "github.com/consensys/gnark/crypto/hash/poseidon/bw761"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+const (
+	fieldTreeDepth    = 4
+	fieldTreeNbLeaves = 11
+)
+
+const (
+	hashMiMC     = "mimc"
+	hashPoseidon = "poseidon"
+)
+
+type fieldTreeCircuit struct {
+	Root        frontend.Variable `gnark:",public"`
+	Leaves      [fieldTreeNbLeaves]frontend.Variable
+	Leaf, Index frontend.Variable
+	Path        [fieldTreeDepth]frontend.Variable
+	hash        string
+}
+
+func (circuit *fieldTreeCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	var h hash.Hash
+	var err error
+	switch circuit.hash {
+	case hashMiMC:
+		h, err = mimc.NewMiMC("seed", curveID)
+	case hashPoseidon:
+		h, err = poseidon.NewPoseidon("seed", curveID)
+	default:
+		err = fmt.Errorf("unknown hash function %q", circuit.hash)
+	}
+	if err != nil {
+		return err
+	}
+	t := NewFieldTree(h)
+
+	// the root computed from all the leaves, and the proof of one of them
+	cs.AssertIsEqual(t.Root(cs, circuit.Leaves[:], fieldTreeDepth), circuit.Root)
+	t.VerifyProof(cs, circuit.Root, circuit.Leaf, circuit.Path[:], circuit.Index)
+	return nil
+}
+
+// nativeHash returns the go implementation of the hash functions used in the circuit
+var nativeHash = map[gurvy.ID]map[string]func() gohash.Hash{
+	gurvy.BN256: {
+		hashMiMC:     func() gohash.Hash { return mimc_bn256.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bn256.NewPoseidon("seed") },
+	},
+	gurvy.BLS381: {
+		hashMiMC:     func() gohash.Hash { return mimc_bls381.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bls381.NewPoseidon("seed") },
+	},
+	gurvy.BLS377: {
+		hashMiMC:     func() gohash.Hash { return mimc_bls377.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bls377.NewPoseidon("seed") },
+	},
+	gurvy.BW761: {
+		hashMiMC:     func() gohash.Hash { return mimc_bw761.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bw761.NewPoseidon("seed") },
+	},
+}
+
+// nativeProve pushes leaves in a native tree of the curve, and returns its root and the path of the leaf at index
+var nativeProve = map[gurvy.ID]func(t *testing.T, h gohash.Hash, leaves []big.Int, index uint64) (root big.Int, path []big.Int){
+	gurvy.BN256: func(t *testing.T, h gohash.Hash, leaves []big.Int, index uint64) (root big.Int, path []big.Int) {
+		tree, err := merkletree_bn256.New(h, fieldTreeDepth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range leaves {
+			var leaf fr_bn256.Element
+			leaf.SetBigInt(&leaves[i])
+			if err := tree.Push(leaf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r, p, err := tree.Prove(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.ToBigIntRegular(&root)
+		path = make([]big.Int, len(p))
+		for i := range p {
+			p[i].ToBigIntRegular(&path[i])
+		}
+		return
+	},
+	gurvy.BLS381: func(t *testing.T, h gohash.Hash, leaves []big.Int, index uint64) (root big.Int, path []big.Int) {
+		tree, err := merkletree_bls381.New(h, fieldTreeDepth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range leaves {
+			var leaf fr_bls381.Element
+			leaf.SetBigInt(&leaves[i])
+			if err := tree.Push(leaf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r, p, err := tree.Prove(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.ToBigIntRegular(&root)
+		path = make([]big.Int, len(p))
+		for i := range p {
+			p[i].ToBigIntRegular(&path[i])
+		}
+		return
+	},
+	gurvy.BLS377: func(t *testing.T, h gohash.Hash, leaves []big.Int, index uint64) (root big.Int, path []big.Int) {
+		tree, err := merkletree_bls377.New(h, fieldTreeDepth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range leaves {
+			var leaf fr_bls377.Element
+			leaf.SetBigInt(&leaves[i])
+			if err := tree.Push(leaf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r, p, err := tree.Prove(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.ToBigIntRegular(&root)
+		path = make([]big.Int, len(p))
+		for i := range p {
+			p[i].ToBigIntRegular(&path[i])
+		}
+		return
+	},
+	gurvy.BW761: func(t *testing.T, h gohash.Hash, leaves []big.Int, index uint64) (root big.Int, path []big.Int) {
+		tree, err := merkletree_bw761.New(h, fieldTreeDepth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range leaves {
+			var leaf fr_bw761.Element
+			leaf.SetBigInt(&leaves[i])
+			if err := tree.Push(leaf); err != nil {
+				t.Fatal(err)
+			}
+		}
+		r, p, err := tree.Prove(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.ToBigIntRegular(&root)
+		path = make([]big.Int, len(p))
+		for i := range p {
+			p[i].ToBigIntRegular(&path[i])
+		}
+		return
+	},
+}
+
+func TestFieldTree(t *testing.T) {
+
+	const index = 6
+
+	// random leaves on 253 bits, which are elements of the scalar fields of all the curves
+	var bound big.Int
+	bound.Lsh(big.NewInt(1), 253)
+	leaves := make([]big.Int, fieldTreeNbLeaves)
+	for i := range leaves {
+		leaf, err := rand.Int(rand.Reader, &bound)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves[i].Set(leaf)
+	}
+
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		for _, h := range []string{hashMiMC, hashPoseidon} {
+			id, h := id, h
+			t.Run(fmt.Sprintf("%s/%s", id.String(), h), func(t *testing.T) {
+				root, path := nativeProve[id](t, nativeHash[id][h](), leaves, index)
+
+				circuit := fieldTreeCircuit{hash: h}
+				r1cs, err := frontend.Compile(id, &circuit)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assign := func(leafIndex, shift int) *fieldTreeCircuit {
+					var w fieldTreeCircuit
+					w.Root.Assign(root)
+					for i := range w.Leaves {
+						w.Leaves[i].Assign(leaves[(i+shift)%fieldTreeNbLeaves])
+					}
+					w.Leaf.Assign(leaves[leafIndex])
+					w.Index.Assign(index)
+					for i := range w.Path {
+						w.Path[i].Assign(path[i])
+					}
+					return &w
+				}
+
+				assert := groth16.NewAssert(t)
+
+				// the native root is the root computed in the circuit
+				assert.SolvingSucceeded(r1cs, assign(index, 0))
+				assert.SolvingFailed(r1cs, assign(index, 1))
+
+				// the proof of a wrong leaf
+				assert.SolvingFailed(r1cs, assign(index+1, 0))
+			})
+		}
+	}
+}
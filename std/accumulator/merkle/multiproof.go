@@ -0,0 +1,80 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"sort"
+
+	"github.com/consensys/gnark/crypto/accumulator/merkletree"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// VerifyMultiProof checks that leaves[i] is the leaf at indexes[i] in the fixed depth tree of root
+// merkleRoot (cf merkletree.NewFixedDepth), proof being their multiproof (cf merkletree.BuildMultiProof).
+// The indexes are part of the structure of the circuit: the nodes shared by several paths are hashed once,
+// so that proving k leaves costs less than k calls to VerifyProofAtIndex.
+func VerifyMultiProof(cs *frontend.ConstraintSystem, h hash.Hash, merkleRoot frontend.Variable, depth int, indexes []uint64, leaves, proof []frontend.Variable) {
+
+	if len(indexes) != len(leaves) {
+		panic("merkle: there must be as many leaves as indexes")
+	}
+	positions, err := merkletree.MultiProofIndexes(depth, indexes)
+	if err != nil {
+		panic(err)
+	}
+	if len(positions) != len(proof) {
+		panic("merkle: the size of the multiproof doesn't match the indexes")
+	}
+
+	// nodes computed so far at the current height, by index
+	nodes := make(map[uint64]frontend.Variable)
+	for i, index := range indexes {
+		nodes[index] = leafSum(cs, h, leaves[i])
+	}
+	next := 0
+	for ; next < len(positions) && positions[next].Height == 0; next++ {
+		nodes[positions[next].Index] = proof[next]
+	}
+
+	for height := 1; height <= depth; height++ {
+		parents := make(map[uint64]frontend.Variable)
+
+		// iterating over the sorted indexes of the previous level, for the circuit to be deterministic
+		for _, p := range parentIndexes(nodes) {
+			parents[p] = nodeSum(cs, h, nodes[2*p], nodes[2*p+1])
+		}
+		for ; next < len(positions) && positions[next].Height == height; next++ {
+			parents[positions[next].Index] = proof[next]
+		}
+		nodes = parents
+	}
+
+	cs.AssertIsEqual(nodes[0], merkleRoot)
+}
+
+// parentIndexes returns the sorted indexes of the parents of nodes
+func parentIndexes(nodes map[uint64]frontend.Variable) []uint64 {
+	var res []uint64
+	for index := range nodes {
+		if index%2 == 0 {
+			res = append(res, index/2)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
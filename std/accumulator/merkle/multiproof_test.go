@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/crypto/accumulator/merkletree"
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gurvy"
+)
+
+type multiProofCircuit struct {
+	RootHash frontend.Variable `gnark:",public"`
+	Leaves   []frontend.Variable
+	Proof    []frontend.Variable
+	depth    int
+	indexes  []uint64
+}
+
+func (circuit *multiProofCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	hFunc, err := mimc.NewMiMC("seed", curveID)
+	if err != nil {
+		return err
+	}
+	VerifyMultiProof(cs, hFunc, circuit.RootHash, circuit.depth, circuit.indexes, circuit.Leaves, circuit.Proof)
+	return nil
+}
+
+func TestVerifyMultiProof(t *testing.T) {
+
+	const depth = 5
+	leaves := randomLeaves(11)
+
+	// adjacent leaves, leaves sharing a sibling, and padding leaves
+	indexes := []uint64{9, 2, 3, 10, 0, 17}
+	provenLeaves := make([][]byte, len(indexes))
+	for i, index := range indexes {
+		if index < uint64(len(leaves)) {
+			provenLeaves[i] = leaves[index]
+		} else {
+			provenLeaves[i] = make([]byte, 32)
+		}
+	}
+
+	root, proof, err := merkletree.BuildMultiProof(bn256.NewMiMC("seed"), leaves, depth, indexes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proof) >= len(indexes)*depth {
+		t.Fatal("the multiproof should be smaller than the individual proofs")
+	}
+	if !merkletree.VerifyMultiProof(bn256.NewMiMC("seed"), root, depth, indexes, provenLeaves, proof) {
+		t.Fatal("the multiproof in plain go should pass")
+	}
+
+	// the root is the one of the fixed depth tree
+	tree := merkletree.NewFixedDepth(bn256.NewMiMC("seed"), depth)
+	for _, leaf := range leaves {
+		tree.Push(leaf)
+	}
+	if !bytes.Equal(tree.Root(), root) {
+		t.Fatal("the root of the multiproof should be the root of the fixed depth tree")
+	}
+
+	circuit := multiProofCircuit{
+		Leaves:  make([]frontend.Variable, len(indexes)),
+		Proof:   make([]frontend.Variable, len(proof)),
+		depth:   depth,
+		indexes: indexes,
+	}
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assign := func(provenLeaves [][]byte) *multiProofCircuit {
+		w := &multiProofCircuit{
+			Leaves: make([]frontend.Variable, len(indexes)),
+			Proof:  make([]frontend.Variable, len(proof)),
+		}
+		w.RootHash.Assign(root)
+		for i := range provenLeaves {
+			w.Leaves[i].Assign(provenLeaves[i])
+		}
+		for i := range proof {
+			w.Proof[i].Assign(proof[i])
+		}
+		return w
+	}
+
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, assign(provenLeaves))
+
+	// swapping two leaves
+	wrongLeaves := append([][]byte{}, provenLeaves...)
+	wrongLeaves[1], wrongLeaves[2] = wrongLeaves[2], wrongLeaves[1]
+	if merkletree.VerifyMultiProof(bn256.NewMiMC("seed"), root, depth, indexes, wrongLeaves, proof) {
+		t.Fatal("the multiproof of swapped leaves in plain go should fail")
+	}
+	assert.SolvingFailed(r1cs, assign(wrongLeaves))
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// VerifyUpdate checks that newRoot is the root of the tree of root oldRoot, in which the leaf oldLeaf
+// is replaced by newLeaf. The same authentication path proves both roots: path are the siblings of the
+// nodes from the leaf to the root, and indexBits[i] is 1 if the node at height i is a right child
+// (cf merkletree.BuildUpdateProof, which computes the witness).
+func VerifyUpdate(cs *frontend.ConstraintSystem, h hash.Hash, oldRoot, newRoot, oldLeaf, newLeaf frontend.Variable, path, indexBits []frontend.Variable) {
+
+	if len(path) != len(indexBits) {
+		panic("merkle: the path and the index bits must have the same length")
+	}
+
+	cs.AssertIsEqual(computeRoot(cs, h, oldLeaf, path, indexBits), oldRoot)
+	cs.AssertIsEqual(computeRoot(cs, h, newLeaf, path, indexBits), newRoot)
+}
+
+// VerifyUpdates checks consecutive updates of a tree (cf VerifyUpdate): the i-th update replaces
+// oldLeaves[i] by newLeaves[i] in the tree of root roots[i], which yields roots[i+1]. The intermediate
+// roots are typically internal variables of the circuit (cf merkletree.BuildUpdateProofs).
+func VerifyUpdates(cs *frontend.ConstraintSystem, h hash.Hash, roots, oldLeaves, newLeaves []frontend.Variable, paths, indexBits [][]frontend.Variable) {
+
+	if len(roots) != len(oldLeaves)+1 || len(oldLeaves) != len(newLeaves) || len(oldLeaves) != len(paths) || len(paths) != len(indexBits) {
+		panic("merkle: there must be one more root than updates, and as many leaves, paths and index bits as updates")
+	}
+
+	for i := 0; i < len(oldLeaves); i++ {
+		VerifyUpdate(cs, h, roots[i], roots[i+1], oldLeaves[i], newLeaves[i], paths[i], indexBits[i])
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/crypto/accumulator/merkletree"
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+const nbUpdates = 3
+
+type updateCircuit struct {
+	RootBefore, RootAfter frontend.Variable `gnark:",public"`
+	OldLeaves, NewLeaves  [nbUpdates]frontend.Variable
+	IntermediateRoots     [nbUpdates - 1]frontend.Variable
+	Paths, IndexBits      [nbUpdates][]frontend.Variable
+}
+
+func (circuit *updateCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	hFunc, err := mimc.NewMiMC("seed", curveID)
+	if err != nil {
+		return err
+	}
+	roots := append([]frontend.Variable{circuit.RootBefore}, circuit.IntermediateRoots[:]...)
+	roots = append(roots, circuit.RootAfter)
+	VerifyUpdates(cs, hFunc, roots, circuit.OldLeaves[:], circuit.NewLeaves[:], circuit.Paths[:], circuit.IndexBits[:])
+	return nil
+}
+
+func randomLeaves(n int) [][]byte {
+	res := make([][]byte, n)
+	for i := range res {
+		var leaf fr.Element
+		leaf.SetRandom()
+		res[i] = leaf.Bytes()
+	}
+	return res
+}
+
+func TestVerifyUpdates(t *testing.T) {
+
+	// a complete tree, and a tree with orphans (whose index bits aren't the bits of the index)
+	for _, nbLeaves := range []int{8, 11} {
+
+		leaves := randomLeaves(nbLeaves)
+		newLeaves := randomLeaves(nbUpdates)
+
+		// the last leaf, then two updates of the same leaf
+		indexes := []uint64{uint64(nbLeaves - 1), 2, 2}
+		proofs, err := merkletree.BuildUpdateProofs(bn256.NewMiMC("seed"), leaves, indexes, newLeaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, proof := range proofs {
+			if !merkletree.VerifyUpdateProof(bn256.NewMiMC("seed"), proof) {
+				t.Fatal("the update proof in plain go should pass")
+			}
+		}
+
+		var circuit updateCircuit
+		for i := 0; i < nbUpdates; i++ {
+			circuit.Paths[i] = make([]frontend.Variable, len(proofs[i].Path))
+			circuit.IndexBits[i] = make([]frontend.Variable, len(proofs[i].Path))
+		}
+		r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assign := func(w *updateCircuit, rootAfter []byte) {
+			w.RootBefore.Assign(proofs[0].OldRoot)
+			w.RootAfter.Assign(rootAfter)
+			for i := 0; i < nbUpdates; i++ {
+				w.OldLeaves[i].Assign(proofs[i].OldLeaf)
+				w.NewLeaves[i].Assign(proofs[i].NewLeaf)
+				if i > 0 {
+					w.IntermediateRoots[i-1].Assign(proofs[i].OldRoot)
+				}
+				w.Paths[i] = make([]frontend.Variable, len(proofs[i].Path))
+				w.IndexBits[i] = make([]frontend.Variable, len(proofs[i].Path))
+				for j := range proofs[i].Path {
+					w.Paths[i][j].Assign(proofs[i].Path[j])
+					w.IndexBits[i][j].Assign(proofs[i].IndexBits[j])
+				}
+			}
+		}
+
+		assert := groth16.NewAssert(t)
+
+		var witness updateCircuit
+		assign(&witness, proofs[nbUpdates-1].NewRoot)
+		assert.SolvingSucceeded(r1cs, &witness)
+
+		// skipping the last update
+		var wrongWitness updateCircuit
+		assign(&wrongWitness, proofs[nbUpdates-1].OldRoot)
+		assert.SolvingFailed(r1cs, &wrongWitness)
+	}
+}
@@ -46,12 +46,12 @@ package merkle
 
 import (
 	"github.com/consensys/gnark/frontend"
-	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash"
 )
 
 // leafSum returns the hash created from data inserted to form a leaf.
 // Without domain separation.
-func leafSum(cs *frontend.ConstraintSystem, h mimc.MiMC, data frontend.Variable) frontend.Variable {
+func leafSum(cs *frontend.ConstraintSystem, h hash.Hash, data frontend.Variable) frontend.Variable {
 
 	res := h.Hash(cs, data)
 
@@ -60,7 +60,7 @@ func leafSum(cs *frontend.ConstraintSystem, h mimc.MiMC, data frontend.Variable)
 
 // nodeSum returns the hash created from data inserted to form a leaf.
 // Without domain separation.
-func nodeSum(cs *frontend.ConstraintSystem, h mimc.MiMC, a, b frontend.Variable) frontend.Variable {
+func nodeSum(cs *frontend.ConstraintSystem, h hash.Hash, a, b frontend.Variable) frontend.Variable {
 
 	res := h.Hash(cs, a, b)
 
@@ -128,7 +128,7 @@ func GenerateProofHelper(proofSet [][]byte, proofIndex, numLeaves uint64) []int
 // true if the first element of the proof set is a leaf of data in the Merkle
 // root. False is returned if the proof set or Merkle root is nil, and if
 // 'numLeaves' equals 0.
-func VerifyProof(cs *frontend.ConstraintSystem, h mimc.MiMC, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
+func VerifyProof(cs *frontend.ConstraintSystem, h hash.Hash, merkleRoot frontend.Variable, proofSet, helper []frontend.Variable) {
 
 	sum := leafSum(cs, h, proofSet[0])
 
@@ -143,3 +143,25 @@ func VerifyProof(cs *frontend.ConstraintSystem, h mimc.MiMC, merkleRoot frontend
 	cs.AssertIsEqual(sum, merkleRoot)
 
 }
+
+// VerifyProofAtIndex checks that leaf is the leaf at index in the tree of root merkleRoot, path being the
+// siblings of the nodes from the leaf to the root. The index is decomposed in len(path) bits giving the positions
+// of the nodes, so that the proven position is enforced (index < 2**len(path)). The tree must be complete,
+// cf merkletree.NewFixedDepth.
+func VerifyProofAtIndex(cs *frontend.ConstraintSystem, h hash.Hash, merkleRoot, leaf frontend.Variable, path []frontend.Variable, index frontend.Variable) {
+	indexBits := cs.ToBinary(index, len(path))
+	cs.AssertIsEqual(computeRoot(cs, h, leaf, path, indexBits), merkleRoot)
+}
+
+// computeRoot returns the root computed from leaf and its path, indexBits[i] being 1 if the node
+// at height i is a right child (the bits are constrained to be boolean)
+func computeRoot(cs *frontend.ConstraintSystem, h hash.Hash, leaf frontend.Variable, path, indexBits []frontend.Variable) frontend.Variable {
+	sum := leafSum(cs, h, leaf)
+	for i := 0; i < len(path); i++ {
+		cs.AssertIsBoolean(indexBits[i])
+		d1 := cs.Select(indexBits[i], path[i], sum)
+		d2 := cs.Select(indexBits[i], sum, path[i])
+		sum = nodeSum(cs, h, d1, d2)
+	}
+	return sum
+}
@@ -93,3 +93,81 @@ func TestVerify(t *testing.T) {
 	assert := groth16.NewAssert(t)
 	assert.ProverSucceeded(r1cs, assignment)
 }
+
+type merkleAtIndexCircuit struct {
+	RootHash    frontend.Variable `gnark:",public"`
+	Leaf, Index frontend.Variable
+	Path        []frontend.Variable
+}
+
+func (circuit *merkleAtIndexCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	hFunc, err := mimc.NewMiMC("seed", curveID)
+	if err != nil {
+		return err
+	}
+	VerifyProofAtIndex(cs, hFunc, circuit.RootHash, circuit.Leaf, circuit.Path, circuit.Index)
+	return nil
+}
+
+func TestVerifyProofAtIndex(t *testing.T) {
+
+	const depth = 5
+	leaves := randomLeaves(11)
+
+	// the padded tree is the tree of the leaves completed with zeros
+	tree := merkletree.New(bn256.NewMiMC("seed"))
+	for i := 0; i < 1<<depth; i++ {
+		if i < len(leaves) {
+			tree.Push(leaves[i])
+		} else {
+			tree.Push(make([]byte, fr.Limbs*8))
+		}
+	}
+	paddedTree := merkletree.NewFixedDepth(bn256.NewMiMC("seed"), depth)
+	for _, leaf := range leaves {
+		paddedTree.Push(leaf)
+	}
+	if !bytes.Equal(tree.Root(), paddedTree.Root()) {
+		t.Fatal("the root of the fixed depth tree should be the root of the tree completed with padding leaves")
+	}
+
+	var circuit merkleAtIndexCircuit
+	circuit.Path = make([]frontend.Variable, depth)
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	for _, index := range []uint64{3, 10} {
+		root, proofSet, err := merkletree.BuildFixedDepthProof(bn256.NewMiMC("seed"), leaves, depth, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proofSet) != depth+1 {
+			t.Fatal("the proofs of a fixed depth tree should have depth siblings")
+		}
+		if !merkletree.VerifyProof(bn256.NewMiMC("seed"), root, proofSet, index, 1<<depth) {
+			t.Fatal("The merkle proof in plain go should pass")
+		}
+
+		assign := func(w *merkleAtIndexCircuit, index uint64) {
+			w.RootHash.Assign(root)
+			w.Leaf.Assign(proofSet[0])
+			w.Index.Assign(index)
+			w.Path = make([]frontend.Variable, depth)
+			for i := 0; i < depth; i++ {
+				w.Path[i].Assign(proofSet[i+1])
+			}
+		}
+
+		var witness, wrongWitness merkleAtIndexCircuit
+		assign(&witness, index)
+		assert.SolvingSucceeded(r1cs, &witness)
+
+		// the leaf with its path, at another position
+		assign(&wrongWitness, index^1)
+		assert.SolvingFailed(r1cs, &wrongWitness)
+	}
+}
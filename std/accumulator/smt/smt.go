@@ -0,0 +1,128 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package smt verifies membership and non membership proofs of the sparse Merkle trees
+// of crypto/accumulator/smt in a circuit
+package smt
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+// SMT contains the parameters of a sparse Merkle tree: the hash function and the depth
+type SMT struct {
+	h       hash.Hash
+	depth   int
+	modulus *big.Int // modulus of the snark field
+}
+
+var moduli = map[gurvy.ID]func() *big.Int{
+	gurvy.BN256:  fr_bn256.Modulus,
+	gurvy.BLS381: fr_bls381.Modulus,
+	gurvy.BLS377: fr_bls377.Modulus,
+	gurvy.BW761:  fr_bw761.Modulus,
+}
+
+// NewSMT returns the parameters of a sparse Merkle tree with 2**depth leaves, hashed with h
+// (which must match the hash function of the tree, cf crypto/accumulator/smt.New)
+//
+// As crypto/accumulator/smt.New, it accepts depths up to the size in bits of the hash, that is of
+// a serialized field element (fr.Limbs*64 bits).
+func NewSMT(h hash.Hash, depth int, id gurvy.ID) (SMT, error) {
+	modulus, ok := moduli[id]
+	if !ok {
+		return SMT{}, errors.New("unknown curve id")
+	}
+	res := SMT{h: h, depth: depth, modulus: modulus()}
+	if depth <= 0 || depth > 64*((res.modulus.BitLen()+63)/64) {
+		return SMT{}, errors.New("the depth must be positive and smaller than the size of the hash")
+	}
+	return res, nil
+}
+
+// VerifyMembership checks that key is stored with value in the tree of root root,
+// siblings being the proof (cf crypto/accumulator/smt.Proof)
+func (t SMT) VerifyMembership(cs *frontend.ConstraintSystem, root, key, value frontend.Variable, siblings []frontend.Variable) {
+	leaf := t.h.Hash(cs, key, value)
+	t.verifyPath(cs, root, key, leaf, siblings)
+}
+
+// VerifyNonMembership checks that key isn't in the tree of root root, that is that the leaf
+// at its position is empty, siblings being the proof (cf crypto/accumulator/smt.Proof)
+func (t SMT) VerifyNonMembership(cs *frontend.ConstraintSystem, root, key frontend.Variable, siblings []frontend.Variable) {
+	t.verifyPath(cs, root, key, cs.Constant(0), siblings)
+}
+
+// verifyPath checks that the root computed from leaf, at the position of key, and siblings is root
+func (t SMT) verifyPath(cs *frontend.ConstraintSystem, root, key, leaf frontend.Variable, siblings []frontend.Variable) {
+
+	if len(siblings) != t.depth {
+		panic("smt: the number of siblings must be the depth of the tree")
+	}
+
+	bits := t.position(cs, key)
+
+	node := leaf
+	for i := 0; i < t.depth; i++ {
+		if i >= len(bits) {
+			// the bits of H(key) above the size of the field are 0
+			node = t.h.Hash(cs, node, siblings[i])
+			continue
+		}
+		left := cs.Select(bits[i], siblings[i], node)
+		right := cs.Select(bits[i], node, siblings[i])
+		node = t.h.Hash(cs, left, right)
+	}
+
+	cs.AssertIsEqual(node, root)
+}
+
+// position returns the depth lowest bits of H(key) (little endian), or all its bits if the depth
+// is larger than the size of the field
+//
+// The decomposition of H(key) is checked to be the canonical one (smaller than the modulus),
+// otherwise a key could be proven absent at a position which isn't its own.
+func (t SMT) position(cs *frontend.ConstraintSystem, key frontend.Variable) []frontend.Variable {
+
+	bits := cs.ToBinary(t.h.Hash(cs, key), t.modulus.BitLen())
+
+	// bits <= modulus - 1, from the most significant bit: while the bits are equal to the ones
+	// of the bound (equal = 1), a bit must be 0 where the bit of the bound is 0
+	var bound big.Int
+	bound.Sub(t.modulus, big.NewInt(1))
+	equal := cs.Constant(1)
+	for i := len(bits) - 1; i >= 0; i-- {
+		if bound.Bit(i) == 1 {
+			equal = cs.Mul(equal, bits[i])
+		} else {
+			cs.AssertIsEqual(cs.Mul(equal, bits[i]), 0)
+		}
+	}
+
+	if t.depth < len(bits) {
+		return bits[:t.depth]
+	}
+	return bits
+}
@@ -0,0 +1,235 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package smt
+
+import (
+	gohash "hash"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/crypto/accumulator/smt"
+	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	poseidon_bn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+)
+
+const depth = 20
+
+type smtCircuit struct {
+	Root        frontend.Variable `gnark:",public"`
+	Key, Value  frontend.Variable
+	Siblings    [depth]frontend.Variable
+	member      bool // membership or non membership proof
+	usePoseidon bool
+}
+
+func (circuit *smtCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	var h hash.Hash
+	var err error
+	if circuit.usePoseidon {
+		h, err = poseidon.NewPoseidon("seed", curveID)
+	} else {
+		h, err = mimc.NewMiMC("seed", curveID)
+	}
+	if err != nil {
+		return err
+	}
+	t, err := NewSMT(h, depth, curveID)
+	if err != nil {
+		return err
+	}
+	if circuit.member {
+		t.VerifyMembership(cs, circuit.Root, circuit.Key, circuit.Value, circuit.Siblings[:])
+	} else {
+		cs.AssertIsEqual(circuit.Value, 0)
+		t.VerifyNonMembership(cs, circuit.Root, circuit.Key, circuit.Siblings[:])
+	}
+	return nil
+}
+
+// witness returns the witness of the proof of key in tree
+func witness(t *testing.T, tree *smt.Tree, key fr.Element) smtCircuit {
+	proof, err := tree.Prove(key.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w smtCircuit
+	w.Root.Assign(tree.Root())
+	w.Key.Assign(key)
+	if proof.Value != nil {
+		w.Value.Assign(proof.Value)
+	} else {
+		w.Value.Assign(0)
+	}
+	for i := 0; i < depth; i++ {
+		w.Siblings[i].Assign(proof.Siblings[i])
+	}
+	return w
+}
+
+func TestSMT(t *testing.T) {
+
+	for _, usePoseidon := range []bool{false, true} {
+
+		var hFunc gohash.Hash
+		if usePoseidon {
+			hFunc = poseidon_bn256.NewPoseidon("seed")
+		} else {
+			hFunc = mimc_bn256.NewMiMC("seed")
+		}
+
+		// a few keys, one of them being updated and another one deleted
+		tree, err := smt.New(hFunc, depth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := make([]fr.Element, 5)
+		for i := range keys {
+			var value fr.Element
+			keys[i].SetRandom()
+			value.SetRandom()
+			if err := tree.Insert(keys[i].Bytes(), value.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var value fr.Element
+		value.SetUint64(42)
+		if err := tree.Update(keys[1].Bytes(), value.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.Delete(keys[2].Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		var absent fr.Element
+		absent.SetRandom()
+
+		member := smtCircuit{member: true, usePoseidon: usePoseidon}
+		memberR1CS, err := frontend.Compile(gurvy.BN256, &member)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonMember := smtCircuit{usePoseidon: usePoseidon}
+		nonMemberR1CS, err := frontend.Compile(gurvy.BN256, &nonMember)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert := groth16.NewAssert(t)
+
+		for _, key := range []fr.Element{keys[0], keys[1], keys[4]} {
+			w := witness(t, tree, key)
+			assert.SolvingSucceeded(memberR1CS, &w)
+			w = witness(t, tree, key)
+			assert.SolvingFailed(nonMemberR1CS, &w)
+		}
+		for _, key := range []fr.Element{keys[2], absent} {
+			w := witness(t, tree, key)
+			assert.SolvingSucceeded(nonMemberR1CS, &w)
+		}
+
+		// wrong value
+		proof, err := tree.Prove(keys[0].Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var w smtCircuit
+		w.Root.Assign(tree.Root())
+		w.Key.Assign(keys[0])
+		w.Value.Assign(value)
+		for i := 0; i < depth; i++ {
+			w.Siblings[i].Assign(proof.Siblings[i])
+		}
+		assert.SolvingFailed(memberR1CS, &w)
+	}
+}
+
+// maxDepth largest depth of a tree hashed with MiMC on BN256, on both sides
+const maxDepth = 8 * fr.Limbs * 8
+
+type maxDepthCircuit struct {
+	Root       frontend.Variable `gnark:",public"`
+	Key, Value frontend.Variable
+	Siblings   [maxDepth]frontend.Variable
+}
+
+func (circuit *maxDepthCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	h, err := mimc.NewMiMC("seed", curveID)
+	if err != nil {
+		return err
+	}
+	t, err := NewSMT(h, maxDepth, curveID)
+	if err != nil {
+		return err
+	}
+	t.VerifyMembership(cs, circuit.Root, circuit.Key, circuit.Value, circuit.Siblings[:])
+	return nil
+}
+
+func TestSMTMaxDepth(t *testing.T) {
+
+	hFunc := mimc_bn256.NewMiMC("seed")
+	if _, err := smt.New(hFunc, maxDepth+1); err == nil {
+		t.Fatal("the native tree should reject a depth larger than the size of the hash")
+	}
+	h, err := mimc.NewMiMC("seed", gurvy.BN256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSMT(h, maxDepth+1, gurvy.BN256); err == nil {
+		t.Fatal("NewSMT should reject a depth larger than the size of the hash")
+	}
+
+	tree, err := smt.New(hFunc, maxDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var key, value fr.Element
+	key.SetRandom()
+	value.SetRandom()
+	if err := tree.Insert(key.Bytes(), value.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.Prove(key.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var circuit maxDepthCircuit
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var w maxDepthCircuit
+	w.Root.Assign(tree.Root())
+	w.Key.Assign(key)
+	w.Value.Assign(value)
+	for i := 0; i < maxDepth; i++ {
+		w.Siblings[i].Assign(proof.Siblings[i])
+	}
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &w)
+
+	w.Value = frontend.Variable{}
+	w.Value.Assign(42)
+	assert.SolvingFailed(r1cs, &w)
+}
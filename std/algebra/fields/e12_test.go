@@ -135,6 +135,131 @@ func TestMulFp12(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 }
 
+type fp12Square struct {
+	A E12
+	C E12 `gnark:",public"`
+}
+
+func (circuit *fp12Square) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := E12{}
+	ext := GetBLS377ExtensionFp12(cs)
+	expected.Square(cs, &circuit.A, ext)
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestSquareFp12(t *testing.T) {
+
+	var circuit, witness fp12Square
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// witness values
+	var a, c bls377.E12
+	a.SetRandom()
+	c.Square(&a)
+
+	witness.A.Assign(&a)
+	witness.C.Assign(&c)
+
+	// cs values
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
+
+type fp12CyclotomicSquare struct {
+	A E12
+	C E12 `gnark:",public"`
+}
+
+func (circuit *fp12CyclotomicSquare) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := E12{}
+	ext := GetBLS377ExtensionFp12(cs)
+	expected.CyclotomicSquare(cs, &circuit.A, ext)
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestCyclotomicSquareFp12(t *testing.T) {
+
+	var circuit, witness fp12CyclotomicSquare
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// witness values
+	a := randomCyclotomic()
+	var c bls377.E12
+	c.CyclotomicSquare(&a)
+
+	witness.A.Assign(&a)
+	witness.C.Assign(&c)
+
+	// cs values
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
+
+type fp12CyclotomicSquareCompressed struct {
+	A E12
+	C E12 `gnark:",public"`
+}
+
+func (circuit *fp12CyclotomicSquareCompressed) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := circuit.A
+	ext := GetBLS377ExtensionFp12(cs)
+	for i := 0; i < 5; i++ {
+		expected.CyclotomicSquareCompressed(cs, &expected, ext)
+	}
+	expected.Decompress(cs, &expected, ext)
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestCyclotomicSquareCompressedFp12(t *testing.T) {
+
+	var circuit, witness fp12CyclotomicSquareCompressed
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// witness values
+	a := randomCyclotomic()
+	c := a
+	for i := 0; i < 5; i++ {
+		c.CyclotomicSquare(&c)
+	}
+
+	witness.A.Assign(&a)
+	witness.C.Assign(&c)
+
+	// cs values
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &witness)
+
+	// a wrong result should fail
+	var wrongWitness fp12CyclotomicSquareCompressed
+	c.Square(&c)
+	wrongWitness.A.Assign(&a)
+	wrongWitness.C.Assign(&c)
+	assert.SolvingFailed(r1cs, &wrongWitness)
+}
+
+// randomCyclotomic returns a random elmt of the cyclotomic subgroup of Fp12 (easy part of the final exponentiation)
+func randomCyclotomic() bls377.E12 {
+	var a, b bls377.E12
+	a.SetRandom()
+	b.Conjugate(&a)
+	a.Inverse(&a)
+	b.Mul(&b, &a)
+	a.FrobeniusSquare(&b).Mul(&a, &b)
+	return a
+}
+
 type fp12Conjugate struct {
 	A E12
 	C E12 `gnark:",public"`
@@ -366,6 +491,13 @@ func TestInverseFp12(t *testing.T) {
 	// cs values
 	assert := groth16.NewAssert(t)
 	assert.SolvingSucceeded(r1cs, &witness)
+
+	// the inverse computed by the hint is checked
+	var wrongWitness fp12Inverse
+	c.Square(&c)
+	wrongWitness.A.Assign(&a)
+	wrongWitness.C.Assign(&c)
+	assert.SolvingFailed(r1cs, &wrongWitness)
 }
 
 type fp12FixedExpo struct {
@@ -438,3 +570,12 @@ func TestExpFinalExpoFp12(t *testing.T) {
 	assert := groth16.NewAssert(t)
 	assert.SolvingSucceeded(r1cs, &witness)
 }
+
+func TestFinalExpoNbConstraints(t *testing.T) {
+	var circuit fp12FinalExpo
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("FinalExpoBLS: %d constraints", r1cs.GetNbConstraints())
+}
@@ -92,6 +92,40 @@ func (e *E2) Mul(cs *frontend.ConstraintSystem, e1, e2 *E2, ext Extension) *E2 {
 	return e
 }
 
+// Square e2 elmt: 3C
+// ext.uSquare must be odd (it is 5 for bls377)
+func (e *E2) Square(cs *frontend.ConstraintSystem, e1 *E2, ext Extension) *E2 {
+
+	one := big.NewInt(1)
+	two := big.NewInt(2)
+	buSquare := backend.FromInterface(ext.uSquare)
+
+	// (a0+a1)*(a0+uSquare*a1) = a0**2 + uSquare*a1**2 + (1+uSquare)*a0*a1
+	l1 := cs.LinearExpression(
+		cs.Term(e1.A0, one),
+		cs.Term(e1.A1, one),
+	)
+	l2 := cs.LinearExpression(
+		cs.Term(e1.A0, one),
+		cs.Term(e1.A1, &buSquare),
+	)
+	u := cs.Mul(l1, l2)
+
+	// 2*a0*a1
+	e.A1 = cs.Mul(e1.A0, cs.LinearExpression(cs.Term(e1.A1, two)))
+
+	// a0**2 + uSquare*a1**2
+	var c big.Int
+	c.Add(&buSquare, one).Quo(&c, two).Neg(&c) // -(1+uSquare)/2
+	l3 := cs.LinearExpression(
+		cs.Term(u, one),
+		cs.Term(e.A1, &c),
+	)
+	e.A0 = cs.Mul(l3, 1)
+
+	return e
+}
+
 // MulByFp multiplies an fp2 elmt by an fp elmt
 func (e *E2) MulByFp(cs *frontend.ConstraintSystem, e1 *E2, c interface{}) *E2 {
 	e.A0 = cs.Mul(e1.A0, c)
@@ -115,6 +149,16 @@ func (e *E2) Conjugate(cs *frontend.ConstraintSystem, e1 *E2) *E2 {
 	return e
 }
 
+// Norm returns the norm e1.A0**2 - uSquare*e1.A1**2 of e1, which is 0 iff e1 is 0: 3C
+func (e *E2) Norm(cs *frontend.ConstraintSystem, ext Extension) frontend.Variable {
+	minusUSquare := backend.FromInterface(ext.uSquare)
+	minusUSquare.Neg(&minusUSquare)
+	return cs.Mul(cs.LinearExpression(
+		cs.Term(cs.Mul(e.A0, e.A0), big.NewInt(1)),
+		cs.Term(cs.Mul(e.A1, e.A1), &minusUSquare),
+	), 1)
+}
+
 // Inverse inverses an fp2elmt
 func (e *E2) Inverse(cs *frontend.ConstraintSystem, e1 *E2, ext Extension) *E2 {
 
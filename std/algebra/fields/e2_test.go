@@ -134,6 +134,39 @@ func TestMulFp2(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 }
 
+func TestSquareFp2(t *testing.T) {
+	// test circuit
+	circuit := e2TestCircuit{
+		define: func(curveID gurvy.ID, cs *frontend.ConstraintSystem, A, B, C E2) error {
+			ext := Extension{uSquare: 5}
+			expected := E2{}
+			expected.Square(cs, &A, ext)
+			expected.MustBeEqual(cs, C)
+			return nil
+		},
+	}
+
+	// compile it into a R1CS
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// witness values
+	var a, b, c bls377.E2
+	a.SetRandom()
+	b.SetRandom()
+	c.Square(&a)
+
+	var witness e2TestCircuit
+	witness.A.Assign(&a)
+	witness.B.Assign(&b)
+	witness.C.Assign(&c)
+
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
+
 type fp2MulByFp struct {
 	A E2
 	B frontend.Variable
@@ -207,6 +240,37 @@ func TestConjugateFp2(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 }
 
+type fp2Norm struct {
+	A E2
+	C frontend.Variable `gnark:",public"`
+}
+
+func (circuit *fp2Norm) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	ext := Extension{uSquare: 5}
+	cs.AssertIsEqual(circuit.A.Norm(cs, ext), circuit.C)
+	return nil
+}
+
+func TestNormFp2(t *testing.T) {
+
+	var circuit, witness fp2Norm
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// witness values: the norm is a*conjugate(a)
+	var a, c bls377.E2
+	a.SetRandom()
+	c.Conjugate(&a).Mul(&c, &a)
+
+	witness.A.Assign(&a)
+	witness.C.Assign(bls377FpTobw761fr(&c.A0))
+
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
+
 type fp2Inverse struct {
 	A E2
 	C E2 `gnark:",public"`
@@ -108,11 +108,32 @@ func (e *E6) MulByFp2(cs *frontend.ConstraintSystem, e1 *E6, e2 *E2, ext Extensi
 
 // MulByNonResidue multiplies e by the imaginary elmt of Fp6 (noted a+bV+cV where V**3 in F^2)
 func (e *E6) MulByNonResidue(cs *frontend.ConstraintSystem, e1 *E6, ext Extension) *E6 {
-	res := E6{}
-	res.B0.Mul(cs, &e1.B2, ext.vCube, ext)
-	e.B1 = e1.B0
-	e.B2 = e1.B1
-	e.B0 = res.B0
+	b0, b1 := e1.B0, e1.B1
+	e.B0.Mul(cs, &e1.B2, ext.vCube, ext)
+	e.B1 = b0
+	e.B2 = b1
+	return e
+}
+
+// Square e6 elmt (algorithm 16 in https://eprint.iacr.org/2010/354.pdf): 41C
+func (e *E6) Square(cs *frontend.ConstraintSystem, e1 *E6, ext Extension) *E6 {
+
+	var c0, c1, c2, c3, c4, c5 E2
+
+	c4.Mul(cs, &e1.B0, &e1.B1, ext).Add(cs, &c4, &c4) // 7C
+	c5.Square(cs, &e1.B2, ext)                        // 3C
+	c1.MulByIm(cs, &c5, ext).Add(cs, &c1, &c4)        // 3C
+	c2.Sub(cs, &c4, &c5)                              // 2C
+	c3.Square(cs, &e1.B0, ext)                        // 3C
+	c4.Sub(cs, &e1.B0, &e1.B1).Add(cs, &c4, &e1.B2)   // 4C
+	c5.Mul(cs, &e1.B1, &e1.B2, ext).Add(cs, &c5, &c5) // 7C
+	c4.Square(cs, &c4, ext)                           // 3C
+	c0.MulByIm(cs, &c5, ext).Add(cs, &c0, &c3)        // 3C
+
+	e.B2.Add(cs, &c2, &c4).Add(cs, &e.B2, &c5).Sub(cs, &e.B2, &c3) // 6C
+	e.B0 = c0
+	e.B1 = c1
+
 	return e
 }
 
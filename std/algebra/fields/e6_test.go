@@ -141,6 +141,40 @@ func TestMulFp6(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 }
 
+type fp6Square struct {
+	A E6
+	C E6 `gnark:",public"`
+}
+
+func (circuit *fp6Square) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := E6{}
+	ext := getBLS377ExtensionFp6(cs)
+	expected.Square(cs, &circuit.A, ext)
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestSquareFp6(t *testing.T) {
+
+	var circuit, witness fp6Square
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// witness values
+	var a, c bls377.E6
+	a.SetRandom()
+	c.Square(&a)
+
+	witness.A.Assign(&a)
+	witness.C.Assign(&c)
+
+	// cs values
+	assert := groth16.NewAssert(t)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
+
 type fp6MulByNonResidue struct {
 	A E6
 	C E6 `gnark:",public"`
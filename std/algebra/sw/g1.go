@@ -19,6 +19,7 @@ package sw
 import (
 	"math/big"
 
+	"github.com/consensys/gnark/backend/r1cs/r1c"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy/bls377"
 	"github.com/consensys/gurvy/bls377/fp"
@@ -35,6 +36,11 @@ type G1Affine struct {
 	X, Y frontend.Variable
 }
 
+// G1Proj point in projective coords (X:Y:Z), the point at infinity being (0:1:0)
+type G1Proj struct {
+	X, Y, Z frontend.Variable
+}
+
 // Neg outputs -p
 func (p *G1Jac) Neg(cs *frontend.ConstraintSystem, p1 *G1Jac) *G1Jac {
 	p.X = p1.X
@@ -251,36 +257,337 @@ func (p *G1Affine) Double(cs *frontend.ConstraintSystem, p1 *G1Affine) *G1Affine
 }
 
 // ScalarMul computes scalar*p1, affect the result to p, and returns it.
-// n is the number of bits used for the scalar mul.
-// TODO it doesn't work if the scalar if 1, because it ends up doing P-P at the end, involving division by 0
-// TODO add a panic if scalar == 1
+// n is the number of bits used for the scalar mul (the scalar must be smaller than 2**n).
+//
+// The formulas are complete (cf G1Proj), so any scalar and any point of G1 work, except when the
+// result is the point at infinity (scalar = 0 mod r), which can't be represented in affine coords.
 func (p *G1Affine) ScalarMul(cs *frontend.ConstraintSystem, p1 *G1Affine, s interface{}, n int) *G1Affine {
+	return p.MultiScalarMul(cs, []G1Affine{*p1}, []frontend.Variable{cs.Constant(s)}, n)
+}
 
-	scalar := cs.Constant(s)
+// MultiScalarMul computes sum(scalars[i]*points[i]), affect the result to p, and returns it
+// (cf G1Proj.MultiScalarMul). The result must not be the point at infinity.
+func (p *G1Affine) MultiScalarMul(cs *frontend.ConstraintSystem, points []G1Affine, scalars []frontend.Variable, n int) *G1Affine {
+	var res G1Proj
+	res.MultiScalarMul(cs, points, scalars, n)
+	return p.FromProj(cs, &res)
+}
 
-	var base, res G1Affine
-	base.Double(cs, p1)
-	res.AssignToRefactor(cs, p1)
+// MultiScalarMul computes sum(scalars[i]*points[i]), affect the result to p, and returns it.
+// n is the number of bits of the scalars (they must be smaller than 2**n).
+//
+// The scalars are decomposed with the GLV endomorphism (cf glv.go) and the point is accumulated with a
+// joint (Straus) double and add with windows of 2 bits, so that the doublings are shared by all the points
+// and halved by the decomposition. The additions use complete formulas, the lookup tables include the
+// point at infinity. For 253 bits scalars, it costs ~1150C for the doublings and ~3100C per point
+// (instead of ~6400C per point for a double and add on 377 bits with the affine formulas).
+//
+// The points must be in G1 (their lookup tables are computed with the affine formulas).
+func (p *G1Proj) MultiScalarMul(cs *frontend.ConstraintSystem, points []G1Affine, scalars []frontend.Variable, n int) *G1Proj {
+
+	if len(points) != len(scalars) || len(points) == 0 {
+		panic("sw: the number of points and scalars must be equal and non zero")
+	}
+
+	// tables of 0, P, 2P, 3P (in affine coords, P being in G1) and the bits indexing them,
+	// phi(P) sharing the table of P
+	type table struct {
+		t     [3]G1Affine
+		omega *big.Int
+		bits  []frontend.Variable
+	}
+	var tables []table
+	one := big.NewInt(1)
+	minusOne := big.NewInt(-1)
+	for i := 0; i < len(points); i++ {
+		var t [3]G1Affine
+		t[0] = points[i]
+		t[1].Double(cs, &t[0])
+		t[2] = t[1]
+		t[2].AddAssign(cs, &t[0])
+		s1, s2 := scalarBits(cs, scalars[i], n)
+		tables = append(tables, table{t, one, s1})
+		if s2 != nil {
+			tables = append(tables, table{t, &thirdRootOneG1, s2})
+		}
+	}
 
-	b := cs.ToBinary(scalar, n)
+	p.SetInfinity(cs)
+	for i := len(tables[0].bits) - 2; i >= 0; i -= 2 {
+		if i != len(tables[0].bits)-2 {
+			p.Double(cs, p).Double(cs, p)
+		}
+		for _, t := range tables {
+			b0, b1 := t.bits[i], t.bits[i+1]
+			b0b1 := cs.Mul(b0, b1)
+			x := lookup(cs, b0, b1, b0b1, 0, t.t[0].X, t.t[1].X, t.t[2].X, t.omega)
+			y := lookup(cs, b0, b1, b0b1, 1, t.t[0].Y, t.t[1].Y, t.t[2].Y, one)
+			z := cs.LinearExpression(cs.Term(b0, one), cs.Term(b1, one), cs.Term(b0b1, minusOne)) // b0 or b1
+			p.addAssign(cs, x, y, z)
+		}
+	}
 
-	var tmp G1Affine
+	return p
+}
 
-	// start from 1 and use right-to-left scalar multiplication to avoid bugs due to incomplete addition law
-	// (I don't see how to avoid that)
-	for i := 1; i <= n-1; i++ {
-		tmp.AssignToRefactor(cs, &res).AddAssign(cs, &base)
-		res.Select(cs, b[i], &tmp, &res)
-		base.Double(cs, &base)
+// MultiScalarMulFixedBase computes sum(scalars[i]*points[i]), where the points are known at compile time,
+// affect the result to p, and returns it. n is the number of bits of the scalars (they must be smaller than 2**n).
+//
+// The scalars are split in windows of 3 bits, and the multiples j*2**(3w)*points[i] (j < 8) are precomputed
+// outside of the circuit, so that there is no doubling: each window costs a lookup (4C) and a complete
+// addition (15C). For 253 bits scalars, it costs ~1900C per point.
+//
+// The points must be in G1, the points at infinity are skipped.
+func (p *G1Proj) MultiScalarMulFixedBase(cs *frontend.ConstraintSystem, points []bls377.G1Affine, scalars []frontend.Variable, n int) *G1Proj {
+
+	if len(points) != len(scalars) || len(points) == 0 {
+		panic("sw: the number of points and scalars must be equal and non zero")
 	}
 
-	// now check the lsb, if it's one, leave the result as is, otherwise substract P
-	tmp.Neg(cs, p1).AddAssign(cs, &res)
+	const windowSize = 3
+
+	first := true
+	for i := 0; i < len(points); i++ {
+		if points[i].IsInfinity() {
+			continue
+		}
+		bits := cs.ToBinary(scalars[i], n)
+
+		// base = 2**(3w)*points[i]
+		var base bls377.G1Jac
+		base.FromAffine(&points[i])
+		for w := 0; w < len(bits); w += windowSize {
+			end := w + windowSize
+			if end > len(bits) {
+				end = len(bits)
+			}
+
+			// table[j] = j*base
+			table := make([]bls377.G1Affine, 1<<(end-w))
+			acc := base
+			table[1].FromJacobian(&acc)
+			for j := 2; j < len(table); j++ {
+				acc.AddAssign(&base)
+				table[j].FromJacobian(&acc)
+			}
+
+			x, y, z := lookupFixed(cs, bits[w:end], table)
+			if first {
+				p.X = cs.Mul(x, 1)
+				p.Y = cs.Mul(y, 1)
+				p.Z = cs.Mul(z, 1)
+				first = false
+			} else {
+				p.addAssign(cs, x, y, z)
+			}
+
+			for j := 0; j < windowSize; j++ {
+				base.DoubleAssign()
+			}
+		}
+	}
 
-	p.Select(cs, b[0], &res, &tmp)
+	if first {
+		p.SetInfinity(cs)
+	}
 
 	return p
+}
+
+// lookupFixed returns table[b] in projective coords as linear expressions, where b is given by its bits
+// (little endian) and table[0] is the point at infinity. The coordinates are multilinear polynomials
+// in the bits, whose coefficients are computed outside of the circuit: 2**len(bits)-len(bits)-1 C
+func lookupFixed(cs *frontend.ConstraintSystem, bits []frontend.Variable, table []bls377.G1Affine) (x, y, z r1c.LinearExpression) {
+
+	// monomials[mask] = product of the bits in mask
+	monomials := make([]frontend.Variable, len(table))
+	monomials[0] = cs.Constant(1)
+	for mask := 1; mask < len(table); mask++ {
+		low := mask & -mask
+		if mask == low {
+			monomials[mask] = bits[bitIndex(low)]
+		} else {
+			monomials[mask] = cs.Mul(monomials[mask^low], monomials[low])
+		}
+	}
+
+	// coefficients of the monomials (Möbius transform of the table)
+	modulus := fp.Modulus()
+	coeffs := make([][3]big.Int, len(table))
+	for j := 1; j < len(table); j++ {
+		table[j].X.ToBigIntRegular(&coeffs[j][0])
+		table[j].Y.ToBigIntRegular(&coeffs[j][1])
+		coeffs[j][2].SetInt64(1)
+	}
+	coeffs[0][1].SetInt64(1) // (0:1:0)
+	for k := 1; k < len(table); k <<= 1 {
+		for mask := 0; mask < len(table); mask++ {
+			if mask&k != 0 {
+				for c := 0; c < 3; c++ {
+					coeffs[mask][c].Sub(&coeffs[mask][c], &coeffs[mask^k][c]).Mod(&coeffs[mask][c], modulus)
+				}
+			}
+		}
+	}
 
+	var res [3]r1c.LinearExpression
+	for mask := 0; mask < len(table); mask++ {
+		for c := 0; c < 3; c++ {
+			if coeffs[mask][c].Sign() != 0 {
+				res[c] = append(res[c], cs.Term(monomials[mask], &coeffs[mask][c]))
+			}
+		}
+	}
+
+	return res[0], res[1], res[2]
+}
+
+// bitIndex returns i such that b = 2**i
+func bitIndex(b int) int {
+	i := 0
+	for b > 1 {
+		b >>= 1
+		i++
+	}
+	return i
+}
+
+// SetInfinity sets p to the point at infinity (0:1:0) and returns it
+func (p *G1Proj) SetInfinity(cs *frontend.ConstraintSystem) *G1Proj {
+	p.X = cs.Constant(0)
+	p.Y = cs.Constant(1)
+	p.Z = cs.Constant(0)
+	return p
+}
+
+// FromAffine sets p to p1 in projective coords and returns it
+func (p *G1Proj) FromAffine(cs *frontend.ConstraintSystem, p1 *G1Affine) *G1Proj {
+	p.X = p1.X
+	p.Y = p1.Y
+	p.Z = cs.Constant(1)
+	return p
+}
+
+// Neg outputs -p
+func (p *G1Proj) Neg(cs *frontend.ConstraintSystem, p1 *G1Proj) *G1Proj {
+	p.X = p1.X
+	p.Y = cs.Sub(0, p1.Y)
+	p.Z = p1.Z
+	return p
+}
+
+// AddAssign adds p1 to p with the complete formulas of https://eprint.iacr.org/2015/1060.pdf
+// (algorithm 7, a=0, b=1), and returns p: 15C. p and p1 can be equal, opposite or at infinity.
+func (p *G1Proj) AddAssign(cs *frontend.ConstraintSystem, p1 *G1Proj) *G1Proj {
+	one := big.NewInt(1)
+	return p.addAssign(cs,
+		cs.LinearExpression(cs.Term(p1.X, one)),
+		cs.LinearExpression(cs.Term(p1.Y, one)),
+		cs.LinearExpression(cs.Term(p1.Z, one)),
+	)
+}
+
+// addAssign adds (x:y:z) to p, the coordinates being linear expressions (cf AddAssign)
+func (p *G1Proj) addAssign(cs *frontend.ConstraintSystem, x, y, z r1c.LinearExpression) *G1Proj {
+
+	one := big.NewInt(1)
+	minusOne := big.NewInt(-1)
+	three := big.NewInt(3)
+	minusThree := big.NewInt(-3)
+
+	sum := func(a, b r1c.LinearExpression) r1c.LinearExpression {
+		res := make(r1c.LinearExpression, 0, len(a)+len(b))
+		res = append(res, a...)
+		return append(res, b...)
+	}
+
+	t0 := cs.Mul(p.X, x)
+	t1 := cs.Mul(p.Y, y)
+	t2 := cs.Mul(p.Z, z)
+
+	// t3 = X1*Y2 + X2*Y1, t4 = Y1*Z2 + Y2*Z1, t5 = X1*Z2 + X2*Z1
+	u3 := cs.Mul(cs.LinearExpression(cs.Term(p.X, one), cs.Term(p.Y, one)), sum(x, y))
+	u4 := cs.Mul(cs.LinearExpression(cs.Term(p.Y, one), cs.Term(p.Z, one)), sum(y, z))
+	u5 := cs.Mul(cs.LinearExpression(cs.Term(p.X, one), cs.Term(p.Z, one)), sum(x, z))
+	t3 := cs.LinearExpression(cs.Term(u3, one), cs.Term(t0, minusOne), cs.Term(t1, minusOne))
+	t4 := cs.LinearExpression(cs.Term(u4, one), cs.Term(t1, minusOne), cs.Term(t2, minusOne))
+	b3t5 := cs.LinearExpression(cs.Term(u5, three), cs.Term(t0, minusThree), cs.Term(t2, minusThree))
+
+	// 3*t0, t1 + 3*t2, t1 - 3*t2
+	t0Times3 := cs.LinearExpression(cs.Term(t0, three))
+	t1PlusB3t2 := cs.LinearExpression(cs.Term(t1, one), cs.Term(t2, three))
+	t1MinusB3t2 := cs.LinearExpression(cs.Term(t1, one), cs.Term(t2, minusThree))
+
+	// X3 = t3*(t1 - 3*t2) - t4*3*t5
+	x1 := cs.Mul(t3, t1MinusB3t2)
+	x2 := cs.Mul(t4, b3t5)
+	X := cs.Mul(cs.LinearExpression(cs.Term(x1, one), cs.Term(x2, minusOne)), 1)
+
+	// Y3 = (t1 - 3*t2)*(t1 + 3*t2) + 3*t5*3*t0
+	y1 := cs.Mul(t1MinusB3t2, t1PlusB3t2)
+	y2 := cs.Mul(b3t5, t0Times3)
+	Y := cs.Mul(cs.LinearExpression(cs.Term(y1, one), cs.Term(y2, one)), 1)
+
+	// Z3 = (t1 + 3*t2)*t4 + 3*t0*t3
+	z1 := cs.Mul(t1PlusB3t2, t4)
+	z2 := cs.Mul(t0Times3, t3)
+	Z := cs.Mul(cs.LinearExpression(cs.Term(z1, one), cs.Term(z2, one)), 1)
+
+	p.X, p.Y, p.Z = X, Y, Z
+
+	return p
+}
+
+// Double doubles p1 with the complete formulas of https://eprint.iacr.org/2015/1060.pdf
+// (algorithm 9, a=0, b=1), affect the result to p, and returns it: 9C
+func (p *G1Proj) Double(cs *frontend.ConstraintSystem, p1 *G1Proj) *G1Proj {
+
+	one := big.NewInt(1)
+	two := big.NewInt(2)
+	eight := big.NewInt(8)
+	minusNine := big.NewInt(-9)
+	three := big.NewInt(3)
+
+	// t0 = Y**2, t1 = Y*Z, t2 = 3*Z**2
+	t0 := cs.Mul(p1.Y, p1.Y)
+	t1 := cs.Mul(p1.Y, p1.Z)
+	t2 := cs.Mul(p1.Z, p1.Z)
+	xy := cs.Mul(p1.X, p1.Y)
+
+	// t0 - 3*t2 = Y**2 - 9*Z**2
+	t0MinusB3t2 := cs.LinearExpression(cs.Term(t0, one), cs.Term(t2, minusNine))
+
+	// X3 = 2*(t0 - 3*t2)*X*Y
+	X := cs.Mul(t0MinusB3t2, cs.LinearExpression(cs.Term(xy, two)))
+
+	// Y3 = 3*t2*8*t0 + (t0 - 3*t2)*(t0 + 3*t2)
+	y1 := cs.Mul(cs.LinearExpression(cs.Term(t2, three)), cs.LinearExpression(cs.Term(t0, eight)))
+	y2 := cs.Mul(t0MinusB3t2, cs.LinearExpression(cs.Term(t0, one), cs.Term(t2, three)))
+	Y := cs.Mul(cs.LinearExpression(cs.Term(y1, one), cs.Term(y2, one)), 1)
+
+	// Z3 = 8*t0*t1
+	Z := cs.Mul(cs.LinearExpression(cs.Term(t0, eight)), t1)
+
+	p.X, p.Y, p.Z = X, Y, Z
+
+	return p
+}
+
+// Select sets p1 if b=1, p2 if b=0, and returns it. b must be boolean constrained
+func (p *G1Proj) Select(cs *frontend.ConstraintSystem, b frontend.Variable, p1, p2 *G1Proj) *G1Proj {
+	p.X = cs.Select(b, p1.X, p2.X)
+	p.Y = cs.Select(b, p1.Y, p2.Y)
+	p.Z = cs.Select(b, p1.Z, p2.Z)
+	return p
+}
+
+// FromProj sets p to p1 in affine coords and returns it: 3C
+// p1 must not be the point at infinity, the circuit is not satisfiable otherwise.
+func (p *G1Affine) FromProj(cs *frontend.ConstraintSystem, p1 *G1Proj) *G1Affine {
+	zInv := cs.Inverse(p1.Z)
+	p.X = cs.Mul(p1.X, zInv)
+	p.Y = cs.Mul(p1.Y, zInv)
+	return p
 }
 
 func bls377FpTobw761fr(a *fp.Element) (r fr.Element) {
@@ -290,6 +597,16 @@ func bls377FpTobw761fr(a *fp.Element) (r fr.Element) {
 	return
 }
 
+// MustBeOnCurve checks that p is on BLS377's curve y**2 = x**3 + 1: 4C
+func (p *G1Affine) MustBeOnCurve(cs *frontend.ConstraintSystem) {
+	one := big.NewInt(1)
+	x3 := cs.Mul(p.X, p.X, p.X)
+	cs.AssertIsEqual(cs.Mul(p.Y, p.Y), cs.LinearExpression(
+		cs.Term(x3, one),
+		cs.Term(cs.Constant(1), one),
+	))
+}
+
 // Assign a value to self (witness assignment)
 func (p *G1Jac) Assign(p1 *bls377.G1Jac) {
 	p.X.Assign(bls377FpTobw761fr(&p1.X))
@@ -23,6 +23,7 @@ import (
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls377/fp"
 	"github.com/consensys/gurvy/bls377/fr"
 
 	"github.com/consensys/gurvy/bls377"
@@ -283,6 +284,224 @@ func TestScalarMulG1(t *testing.T) {
 
 }
 
+type g1ScalarMulVariable struct {
+	A G1Affine
+	S frontend.Variable
+	C G1Affine `gnark:",public"`
+}
+
+func (circuit *g1ScalarMulVariable) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := G1Affine{}
+	expected.ScalarMul(cs, &circuit.A, circuit.S, fr.Modulus().BitLen())
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestScalarMulVariableG1(t *testing.T) {
+
+	var circuit g1ScalarMulVariable
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_a := randomPointG1()
+	var a bls377.G1Affine
+	a.FromJacobian(&_a)
+
+	var rMinusOne, random big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	var r fr.Element
+	r.SetRandom()
+	r.ToBigIntRegular(&random)
+
+	assert := groth16.NewAssert(t)
+
+	// the incomplete formulas fail on small scalars and on -P
+	for _, s := range []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), &rMinusOne, &random} {
+		var c bls377.G1Jac
+		var _c bls377.G1Affine
+		c.ScalarMultiplication(&_a, s)
+		_c.FromJacobian(&c)
+
+		var witness g1ScalarMulVariable
+		witness.A.Assign(&a)
+		witness.S.Assign(*s)
+		witness.C.Assign(&_c)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+
+	// wrong result
+	var c bls377.G1Jac
+	var _c bls377.G1Affine
+	c.ScalarMultiplication(&_a, big.NewInt(6))
+	_c.FromJacobian(&c)
+
+	var witness g1ScalarMulVariable
+	witness.A.Assign(&a)
+	witness.S.Assign(5)
+	witness.C.Assign(&_c)
+	assert.SolvingFailed(r1cs, &witness)
+}
+
+type g1MultiScalarMul struct {
+	A [3]G1Affine
+	S [3]frontend.Variable
+	C G1Affine `gnark:",public"`
+}
+
+func (circuit *g1MultiScalarMul) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := G1Affine{}
+	expected.MultiScalarMul(cs, circuit.A[:], circuit.S[:], fr.Modulus().BitLen())
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestMultiScalarMulG1(t *testing.T) {
+
+	var circuit g1MultiScalarMul
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	// random points, then a point and its opposite with the same scalar
+	p := randomPointG1()
+	var pNeg bls377.G1Jac
+	pNeg.Neg(&p)
+	for _, points := range [][3]bls377.G1Jac{
+		{randomPointG1(), randomPointG1(), randomPointG1()},
+		{p, randomPointG1(), pNeg},
+	} {
+		var witness g1MultiScalarMul
+		var res, tmp bls377.G1Jac
+		var s fr.Element
+		for i := 0; i < 3; i++ {
+			if i != 2 || !points[2].Equal(&pNeg) {
+				s.SetRandom()
+			}
+			var bs big.Int
+			s.ToBigIntRegular(&bs)
+			tmp.ScalarMultiplication(&points[i], &bs)
+			res.AddAssign(&tmp)
+
+			var a bls377.G1Affine
+			a.FromJacobian(&points[i])
+			witness.A[i].Assign(&a)
+			witness.S[i].Assign(bs)
+		}
+		var c bls377.G1Affine
+		c.FromJacobian(&res)
+		witness.C.Assign(&c)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+}
+
+type g1MultiScalarMulFixedBase struct {
+	points [3]bls377.G1Affine // known at compile time
+	S      [3]frontend.Variable
+	C      G1Affine `gnark:",public"`
+}
+
+func (circuit *g1MultiScalarMulFixedBase) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	var res G1Proj
+	res.MultiScalarMulFixedBase(cs, circuit.points[:], circuit.S[:], fr.Modulus().BitLen())
+	expected := G1Affine{}
+	expected.FromProj(cs, &res)
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestMultiScalarMulFixedBaseG1(t *testing.T) {
+
+	// a point and its opposite, and the point at infinity
+	var circuit g1MultiScalarMulFixedBase
+	p := randomPointG1()
+	circuit.points[0].FromJacobian(&p)
+	circuit.points[1].Neg(&circuit.points[0])
+	p = randomPointG1()
+	circuit.points[2].FromJacobian(&p)
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	var witness, wrongWitness g1MultiScalarMulFixedBase
+	var res, tmp bls377.G1Jac
+	for i := 0; i < 3; i++ {
+		var s fr.Element
+		s.SetRandom()
+		var bs big.Int
+		s.ToBigIntRegular(&bs)
+		tmp.FromAffine(&circuit.points[i])
+		tmp.ScalarMultiplication(&tmp, &bs)
+		res.AddAssign(&tmp)
+		witness.S[i].Assign(bs)
+		wrongWitness.S[i].Assign(bs)
+	}
+	var c bls377.G1Affine
+	c.FromJacobian(&res)
+	witness.C.Assign(&c)
+	assert.SolvingSucceeded(r1cs, &witness)
+
+	res.AddAssign(&tmp)
+	c.FromJacobian(&res)
+	wrongWitness.C.Assign(&c)
+	assert.SolvingFailed(r1cs, &wrongWitness)
+}
+
+type g1AddAssignProj struct {
+	A, B G1Affine
+	C    G1Affine `gnark:",public"`
+}
+
+func (circuit *g1AddAssignProj) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	var a, b, res G1Proj
+	a.FromAffine(cs, &circuit.A)
+	b.FromAffine(cs, &circuit.B)
+	res.SetInfinity(cs).AddAssign(cs, &a).AddAssign(cs, &b).AddAssign(cs, &a)
+	res.Double(cs, &res)
+	expected := G1Affine{}
+	expected.FromProj(cs, &res)
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestAddAssignProjG1(t *testing.T) {
+
+	var circuit g1AddAssignProj
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	// 2*(2A+B), with B random, B = A and B = -A
+	_a := randomPointG1()
+	var aNeg bls377.G1Jac
+	aNeg.Neg(&_a)
+	for _, _b := range []bls377.G1Jac{randomPointG1(), _a, aNeg} {
+		var res bls377.G1Jac
+		res.Set(&_a).AddAssign(&_b).AddAssign(&_a).DoubleAssign()
+
+		var a, b, c bls377.G1Affine
+		a.FromJacobian(&_a)
+		b.FromJacobian(&_b)
+		c.FromJacobian(&res)
+
+		var witness g1AddAssignProj
+		witness.A.Assign(&a)
+		witness.B.Assign(&b)
+		witness.C.Assign(&c)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+}
+
 func randomPointG1() bls377.G1Jac {
 
 	p1, _, _, _ := bls377.Generators()
@@ -294,3 +513,99 @@ func randomPointG1() bls377.G1Jac {
 
 	return p1
 }
+
+// -------------------------------------------------------------------------------------------------
+// point validation
+
+type g1MustBeInSubgroup struct {
+	A G1Affine
+}
+
+func (circuit *g1MustBeInSubgroup) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	circuit.A.MustBeOnCurve(cs)
+	circuit.A.MustBeInSubgroup(cs)
+	return nil
+}
+
+func TestMustBeInSubgroupG1(t *testing.T) {
+
+	var circuit g1MustBeInSubgroup
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	g1, _, _, _ := bls377.Generators()
+	_a := randomPointG1()
+	for _, p := range []bls377.G1Jac{g1, _a} {
+		var a bls377.G1Affine
+		a.FromJacobian(&p)
+		var witness g1MustBeInSubgroup
+		witness.A.Assign(&a)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+
+	// (0, 1) of order 3 and (-1, 0) of order 2 are on the curve, but not in G1
+	var minusOne big.Int
+	minusOne.Sub(fp.Modulus(), big.NewInt(1))
+	for _, p := range [][2]big.Int{{*big.NewInt(0), *big.NewInt(1)}, {minusOne, *big.NewInt(0)}} {
+		var witness g1MustBeInSubgroup
+		witness.A.X.Assign(p[0])
+		witness.A.Y.Assign(p[1])
+		assert.SolvingFailed(r1cs, &witness)
+	}
+
+	// a point mapped to the curve, before its cofactor is cleared: its order is a multiple of r
+	// larger than r, so that it is on the curve but not in G1
+	b, err := bls377.EncodeToCurveG1Svdw([]byte("gnark"), []byte("subgroup"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _b bls377.G1Jac
+	_b.FromAffine(&b)
+	var rh big.Int
+	rh.Mul(fr.Modulus(), &bls377Cofactor1)
+	rb, hb, rhb := g1MulGeneric(&_b, fr.Modulus()), g1MulGeneric(&_b, &bls377Cofactor1), g1MulGeneric(&_b, &rh)
+	if !b.IsOnCurve() || b.IsInSubGroup() || rb.Z.IsZero() || hb.Z.IsZero() || !rhb.Z.IsZero() {
+		t.Fatal("the test point should be on the curve, of order a multiple of r larger than r")
+	}
+	var largeOrderWitness g1MustBeInSubgroup
+	largeOrderWitness.A.Assign(&b)
+	assert.SolvingFailed(r1cs, &largeOrderWitness)
+
+	// not on the curve
+	var a bls377.G1Affine
+	a.FromJacobian(&_a)
+	a.Y.Double(&a.Y)
+	var witness g1MustBeInSubgroup
+	witness.A.Assign(&a)
+	assert.SolvingFailed(r1cs, &witness)
+}
+
+// bls377Seed seed x0 of bls377, from which the cofactors of G1 and G2 are computed
+var bls377Seed, _ = new(big.Int).SetString("9586122913090633729", 10)
+
+// bls377Cofactor1 cofactor (x0-1)**2/3 of G1
+var bls377Cofactor1 = func() big.Int {
+	var h big.Int
+	h.Sub(bls377Seed, big.NewInt(1))
+	h.Mul(&h, &h).Div(&h, big.NewInt(3))
+	return h
+}()
+
+// g1MulGeneric computes s*p with a double and add, which unlike the GLV scalar multiplication
+// of gurvy also holds for a point which is not in G1
+func g1MulGeneric(p *bls377.G1Jac, s *big.Int) bls377.G1Jac {
+	var res bls377.G1Jac
+	res.X.SetOne()
+	res.Y.SetOne()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res.DoubleAssign()
+		if s.Bit(i) == 1 {
+			res.AddAssign(p)
+		}
+	}
+	return res
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package sw
 
 import (
+	"math/big"
+
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/fields"
 	"github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fp"
 )
 
 // G2Jac point in Jacobian coords
@@ -32,6 +35,11 @@ type G2Affine struct {
 	X, Y fields.E2
 }
 
+// G2Proj point in projective coords (X:Y:Z), the point at infinity being (0:1:0)
+type G2Proj struct {
+	X, Y, Z fields.E2
+}
+
 // ToProj sets p to p1 in projective coords and return it
 func (p *G2Jac) ToProj(cs *frontend.ConstraintSystem, p1 *G2Jac, ext fields.Extension) *G2Jac {
 	p.X.Mul(cs, &p1.X, &p1.Z, ext)
@@ -152,10 +160,10 @@ func (p *G2Affine) Double(cs *frontend.ConstraintSystem, p1 *G2Affine, ext field
 		Sub(cs, &xr, &p1.X).
 		Sub(cs, &xr, &p1.X)
 
-	// yr = lambda*(p.x-xr)-p.y
-	yr.Sub(cs, &p.X, &xr).
+	// yr = lambda*(p1.x-xr)-p1.y
+	yr.Sub(cs, &p1.X, &xr).
 		Mul(cs, &l, &yr, ext).
-		Sub(cs, &yr, &p.Y)
+		Sub(cs, &yr, &p1.Y)
 
 	p.X = xr
 	p.Y = yr
@@ -194,6 +202,227 @@ func (p *G2Jac) Double(cs *frontend.ConstraintSystem, p1 *G2Jac, ext fields.Exte
 	return p
 }
 
+// ScalarMul computes scalar*p1, affect the result to p, and returns it.
+// n is the number of bits used for the scalar mul (the scalar must be smaller than 2**n).
+//
+// The formulas are complete (cf G2Proj), so any scalar and any point of G2 work, except when the
+// result is the point at infinity (scalar = 0 mod r), which can't be represented in affine coords.
+func (p *G2Affine) ScalarMul(cs *frontend.ConstraintSystem, p1 *G2Affine, s interface{}, n int, ext fields.Extension) *G2Affine {
+	return p.MultiScalarMul(cs, []G2Affine{*p1}, []frontend.Variable{cs.Constant(s)}, n, ext)
+}
+
+// MultiScalarMul computes sum(scalars[i]*points[i]), affect the result to p, and returns it
+// (cf G2Proj.MultiScalarMul). The result must not be the point at infinity.
+func (p *G2Affine) MultiScalarMul(cs *frontend.ConstraintSystem, points []G2Affine, scalars []frontend.Variable, n int, ext fields.Extension) *G2Affine {
+	var res G2Proj
+	res.MultiScalarMul(cs, points, scalars, n, ext)
+	return p.FromProj(cs, &res, ext)
+}
+
+// MultiScalarMul computes sum(scalars[i]*points[i]), affect the result to p, and returns it.
+// n is the number of bits of the scalars (they must be smaller than 2**n).
+//
+// Same algorithm as G1Proj.MultiScalarMul: GLV decomposition, joint double and add with windows of 2 bits
+// and complete formulas. For 253 bits scalars, it costs ~6300C for the doublings and ~16400C per point.
+func (p *G2Proj) MultiScalarMul(cs *frontend.ConstraintSystem, points []G2Affine, scalars []frontend.Variable, n int, ext fields.Extension) *G2Proj {
+
+	if len(points) != len(scalars) || len(points) == 0 {
+		panic("sw: the number of points and scalars must be equal and non zero")
+	}
+
+	// tables of 0, P, 2P, 3P and the bits indexing them, phi(P) sharing the table of P
+	type table struct {
+		t     [3]G2Proj
+		omega *big.Int
+		bits  []frontend.Variable
+	}
+	var tables []table
+	one := big.NewInt(1)
+	for i := 0; i < len(points); i++ {
+		var t [3]G2Proj
+		t[0].FromAffine(cs, &points[i])
+		t[1].Double(cs, &t[0], ext)
+		t[2].FromAffine(cs, &points[i]).AddAssign(cs, &t[1], ext)
+		s1, s2 := scalarBits(cs, scalars[i], n)
+		tables = append(tables, table{t, one, s1})
+		if s2 != nil {
+			tables = append(tables, table{t, &thirdRootOneG2, s2})
+		}
+	}
+
+	lookupE2 := func(b0, b1, b0b1 frontend.Variable, t0 int64, t1, t2, t3 *fields.E2, coeff *big.Int) fields.E2 {
+		return fields.E2{
+			A0: cs.Mul(lookup(cs, b0, b1, b0b1, t0, t1.A0, t2.A0, t3.A0, coeff), 1),
+			A1: cs.Mul(lookup(cs, b0, b1, b0b1, 0, t1.A1, t2.A1, t3.A1, coeff), 1),
+		}
+	}
+
+	p.SetInfinity(cs)
+	for i := len(tables[0].bits) - 2; i >= 0; i -= 2 {
+		if i != len(tables[0].bits)-2 {
+			p.Double(cs, p, ext).Double(cs, p, ext)
+		}
+		for _, t := range tables {
+			b0, b1 := t.bits[i], t.bits[i+1]
+			b0b1 := cs.Mul(b0, b1)
+			q := G2Proj{
+				X: lookupE2(b0, b1, b0b1, 0, &t.t[0].X, &t.t[1].X, &t.t[2].X, t.omega),
+				Y: lookupE2(b0, b1, b0b1, 1, &t.t[0].Y, &t.t[1].Y, &t.t[2].Y, one),
+				Z: lookupE2(b0, b1, b0b1, 0, &t.t[0].Z, &t.t[1].Z, &t.t[2].Z, one),
+			}
+			p.AddAssign(cs, &q, ext)
+		}
+	}
+
+	return p
+}
+
+// SetInfinity sets p to the point at infinity (0:1:0) and returns it
+func (p *G2Proj) SetInfinity(cs *frontend.ConstraintSystem) *G2Proj {
+	p.X = fields.E2{A0: cs.Constant(0), A1: cs.Constant(0)}
+	p.Y = fields.E2{A0: cs.Constant(1), A1: cs.Constant(0)}
+	p.Z = fields.E2{A0: cs.Constant(0), A1: cs.Constant(0)}
+	return p
+}
+
+// FromAffine sets p to p1 in projective coords and returns it
+func (p *G2Proj) FromAffine(cs *frontend.ConstraintSystem, p1 *G2Affine) *G2Proj {
+	p.X = p1.X
+	p.Y = p1.Y
+	p.Z = fields.E2{A0: cs.Constant(1), A1: cs.Constant(0)}
+	return p
+}
+
+// Neg outputs -p
+func (p *G2Proj) Neg(cs *frontend.ConstraintSystem, p1 *G2Proj) *G2Proj {
+	p.X = p1.X
+	p.Y.Neg(cs, &p1.Y)
+	p.Z = p1.Z
+	return p
+}
+
+// mulByB3 multiplies e1 by 3*b, where b = 1/u is the coefficient of the twist: (3*a1, 3/5*a0)
+func mulByB3(cs *frontend.ConstraintSystem, e1 *fields.E2) fields.E2 {
+	var c fp.Element
+	var bc big.Int
+	c.SetUint64(5).Inverse(&c).Mul(&c, new(fp.Element).SetUint64(3)).ToBigIntRegular(&bc)
+	return fields.E2{
+		A0: cs.Mul(e1.A1, 3),
+		A1: cs.Mul(e1.A0, &bc),
+	}
+}
+
+// AddAssign adds p1 to p with the complete formulas of https://eprint.iacr.org/2015/1060.pdf
+// (algorithm 7, a=0), and returns p. p and p1 can be equal, opposite or at infinity.
+func (p *G2Proj) AddAssign(cs *frontend.ConstraintSystem, p1 *G2Proj, ext fields.Extension) *G2Proj {
+
+	var t0, t1, t2, t3, t4, t5, u, v fields.E2
+
+	t0.Mul(cs, &p.X, &p1.X, ext)
+	t1.Mul(cs, &p.Y, &p1.Y, ext)
+	t2.Mul(cs, &p.Z, &p1.Z, ext)
+
+	// t3 = X1*Y2 + X2*Y1
+	u.Add(cs, &p.X, &p.Y)
+	v.Add(cs, &p1.X, &p1.Y)
+	t3.Mul(cs, &u, &v, ext).Sub(cs, &t3, &t0).Sub(cs, &t3, &t1)
+
+	// t4 = Y1*Z2 + Y2*Z1
+	u.Add(cs, &p.Y, &p.Z)
+	v.Add(cs, &p1.Y, &p1.Z)
+	t4.Mul(cs, &u, &v, ext).Sub(cs, &t4, &t1).Sub(cs, &t4, &t2)
+
+	// t5 = 3*b*(X1*Z2 + X2*Z1)
+	u.Add(cs, &p.X, &p.Z)
+	v.Add(cs, &p1.X, &p1.Z)
+	t5.Mul(cs, &u, &v, ext).Sub(cs, &t5, &t0).Sub(cs, &t5, &t2)
+	t5 = mulByB3(cs, &t5)
+
+	// t0 = 3*t0, t2 = 3*b*t2
+	t0.MulByFp(cs, &t0, 3)
+	t2 = mulByB3(cs, &t2)
+
+	// t1 - 3*b*t2, t1 + 3*b*t2
+	u.Sub(cs, &t1, &t2)
+	v.Add(cs, &t1, &t2)
+
+	// X3 = t3*u - t4*t5
+	t1.Mul(cs, &t4, &t5, ext)
+	p.X.Mul(cs, &t3, &u, ext).Sub(cs, &p.X, &t1)
+
+	// Y3 = u*v + t5*t0
+	t1.Mul(cs, &t5, &t0, ext)
+	p.Y.Mul(cs, &u, &v, ext).Add(cs, &p.Y, &t1)
+
+	// Z3 = v*t4 + t0*t3
+	t1.Mul(cs, &t0, &t3, ext)
+	p.Z.Mul(cs, &v, &t4, ext).Add(cs, &p.Z, &t1)
+
+	return p
+}
+
+// Double doubles p1 with the complete formulas of https://eprint.iacr.org/2015/1060.pdf
+// (algorithm 9, a=0), affect the result to p, and returns it
+func (p *G2Proj) Double(cs *frontend.ConstraintSystem, p1 *G2Proj, ext fields.Extension) *G2Proj {
+
+	var t0, t1, t2, xy, u, v, X, Y, Z fields.E2
+
+	// t0 = Y**2, t1 = Y*Z, t2 = 3*b*Z**2
+	t0.Square(cs, &p1.Y, ext)
+	t1.Mul(cs, &p1.Y, &p1.Z, ext)
+	t2.Square(cs, &p1.Z, ext)
+	t2 = mulByB3(cs, &t2)
+	xy.Mul(cs, &p1.X, &p1.Y, ext)
+
+	// Z3 = 8*t0*t1
+	Z.MulByFp(cs, &t0, 8)
+	u = Z
+	Z.Mul(cs, &Z, &t1, ext)
+
+	// Y3 = t2*8*t0 + (t0 - 3*t2)*(t0 + t2)
+	Y.Mul(cs, &t2, &u, ext)
+	u.MulByFp(cs, &t2, 3).Sub(cs, &t0, &u)
+	v.Add(cs, &t0, &t2)
+	v.Mul(cs, &u, &v, ext)
+	Y.Add(cs, &Y, &v)
+
+	// X3 = 2*(t0 - 3*t2)*X*Y
+	X.Mul(cs, &u, &xy, ext).MulByFp(cs, &X, 2)
+
+	p.X, p.Y, p.Z = X, Y, Z
+
+	return p
+}
+
+// FromProj sets p to p1 in affine coords and returns it
+// p1 must not be the point at infinity, the circuit is not satisfiable otherwise.
+func (p *G2Affine) FromProj(cs *frontend.ConstraintSystem, p1 *G2Proj, ext fields.Extension) *G2Affine {
+	var zInv fields.E2
+	zInv.Inverse(cs, &p1.Z, ext)
+	p.X.Mul(cs, &p1.X, &zInv, ext)
+	p.Y.Mul(cs, &p1.Y, &zInv, ext)
+	return p
+}
+
+// MustBeOnCurve checks that p is on the twist y**2 = x**3 + 1/u of BLS377's curve: 13C
+func (p *G2Affine) MustBeOnCurve(cs *frontend.ConstraintSystem, ext fields.Extension) {
+
+	// 1/u = u/5
+	var b fp.Element
+	var bb big.Int
+	b.SetUint64(5).Inverse(&b).ToBigIntRegular(&bb)
+
+	var x3, y2 fields.E2
+	x3.Square(cs, &p.X, ext).Mul(cs, &x3, &p.X, ext)
+	y2.Square(cs, &p.Y, ext)
+
+	cs.AssertIsEqual(y2.A0, x3.A0)
+	cs.AssertIsEqual(y2.A1, cs.LinearExpression(
+		cs.Term(x3.A1, big.NewInt(1)),
+		cs.Term(cs.Constant(1), &bb),
+	))
+}
+
 // Assign a value to self (witness assignment)
 func (p *G2Jac) Assign(p1 *bls377.G2Jac) {
 	p.X.Assign(&p1.X)
@@ -235,6 +235,63 @@ func TestNegG2(t *testing.T) {
 
 }
 
+// -------------------------------------------------------------------------------------------------
+// Scalar multiplication
+
+type g2ScalarMul struct {
+	A G2Affine
+	S frontend.Variable
+	C G2Affine `gnark:",public"`
+}
+
+func (circuit *g2ScalarMul) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	expected := G2Affine{}
+	expected.ScalarMul(cs, &circuit.A, circuit.S, fr.Modulus().BitLen(), fields.GetBLS377ExtensionFp12(cs))
+	expected.MustBeEqual(cs, circuit.C)
+	return nil
+}
+
+func TestScalarMulG2(t *testing.T) {
+
+	var circuit g2ScalarMul
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_a := randomPointG2()
+	var a bls377.G2Affine
+	a.FromJacobian(&_a)
+
+	var rMinusOne, random big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	var r fr.Element
+	r.SetRandom()
+	r.ToBigIntRegular(&random)
+
+	assert := groth16.NewAssert(t)
+
+	for _, s := range []*big.Int{big.NewInt(1), big.NewInt(3), &rMinusOne, &random} {
+		var c bls377.G2Jac
+		var _c bls377.G2Affine
+		c.ScalarMultiplication(&_a, s)
+		_c.FromJacobian(&c)
+
+		var witness g2ScalarMul
+		witness.A.Assign(&a)
+		witness.S.Assign(*s)
+		witness.C.Assign(&_c)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+
+	// wrong result
+	var witness g2ScalarMul
+	witness.A.Assign(&a)
+	witness.S.Assign(2)
+	witness.C.Assign(&a)
+	assert.SolvingFailed(r1cs, &witness)
+}
+
 func randomPointG2() bls377.G2Jac {
 	_, p2, _, _ := bls377.Generators()
 
@@ -244,3 +301,97 @@ func randomPointG2() bls377.G2Jac {
 	p2.ScalarMultiplication(&p2, r1.ToBigIntRegular(&b))
 	return p2
 }
+
+// -------------------------------------------------------------------------------------------------
+// point validation
+
+type g2MustBeInSubgroup struct {
+	A G2Affine
+}
+
+func (circuit *g2MustBeInSubgroup) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	ext := fields.GetBLS377ExtensionFp12(cs)
+	circuit.A.MustBeOnCurve(cs, ext)
+	circuit.A.MustBeInSubgroup(cs, ext)
+	return nil
+}
+
+func TestMustBeInSubgroupG2(t *testing.T) {
+
+	var circuit g2MustBeInSubgroup
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	_, g2, _, _ := bls377.Generators()
+	_a := randomPointG2()
+	for _, p := range []bls377.G2Jac{g2, _a} {
+		var a bls377.G2Affine
+		a.FromJacobian(&p)
+		var witness g2MustBeInSubgroup
+		witness.A.Assign(&a)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+
+	// a point of the twist y**2 = x**3 + 1/u, of order a multiple of r larger than r, so that
+	// it is not in G2
+	var b bls377.E2
+	b.A1.SetUint64(5).Inverse(&b.A1)
+	var p bls377.G2Affine
+	for i := uint64(1); ; i++ {
+		var rhs bls377.E2
+		p.X.A0.SetUint64(i)
+		rhs.Square(&p.X).Mul(&rhs, &p.X).Add(&rhs, &b)
+		if rhs.Legendre() == 1 {
+			p.Y.Sqrt(&rhs)
+			break
+		}
+	}
+	var _p bls377.G2Jac
+	_p.FromAffine(&p)
+	var rh big.Int
+	rh.Mul(fr.Modulus(), &bls377Cofactor2)
+	rp, hp, rhp := g2MulGeneric(&_p, fr.Modulus()), g2MulGeneric(&_p, &bls377Cofactor2), g2MulGeneric(&_p, &rh)
+	if !p.IsOnCurve() || p.IsInSubGroup() || rp.Z.IsZero() || hp.Z.IsZero() || !rhp.Z.IsZero() {
+		t.Fatal("the test point should be on the twist, of order a multiple of r larger than r")
+	}
+	var witness g2MustBeInSubgroup
+	witness.A.Assign(&p)
+	assert.SolvingFailed(r1cs, &witness)
+
+	// not on the twist
+	var a bls377.G2Affine
+	a.FromJacobian(&_a)
+	a.Y.Double(&a.Y)
+	var wrongWitness g2MustBeInSubgroup
+	wrongWitness.A.Assign(&a)
+	assert.SolvingFailed(r1cs, &wrongWitness)
+}
+
+// bls377Cofactor2 cofactor (x0**8-4x0**7+5x0**6-4x0**4+6x0**3-4x0**2-4x0+13)/9 of G2
+var bls377Cofactor2 = func() big.Int {
+	var h big.Int
+	for _, c := range []int64{1, -4, 5, 0, -4, 6, -4, -4, 13} {
+		h.Mul(&h, bls377Seed).Add(&h, big.NewInt(c))
+	}
+	h.Div(&h, big.NewInt(9))
+	return h
+}()
+
+// g2MulGeneric computes s*p with a double and add, which unlike the GLV scalar multiplication
+// of gurvy also holds for a point which is not in G2
+func g2MulGeneric(p *bls377.G2Jac, s *big.Int) bls377.G2Jac {
+	var res bls377.G2Jac
+	res.X.SetOne()
+	res.Y.SetOne()
+	for i := s.BitLen() - 1; i >= 0; i-- {
+		res.DoubleAssign()
+		if s.Bit(i) == 1 {
+			res.AddAssign(p)
+		}
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy/bls377/fp"
+)
+
+// The scalar multiplications use the endomorphism phi: (x, y) -> (omega*x, y) of BLS377's G1 and G2, where
+// omega is a cube root of unity in Fp. phi acts as the multiplication by lambda = x0**2-1 (x0 being the seed
+// of BLS377), so that s*P = s1*P + s2*phi(P) with s = s1 + lambda*s2 (GLV, https://www.iacr.org/archive/crypto2001/21390189.pdf).
+//
+// lambda is on 127 bits, and the decomposition s1 = s mod lambda, s2 = s / lambda is done with a hint. It is checked
+// over the integers (s1 and s2 being range checked, s1 + lambda*s2 doesn't overflow the snark field), so that
+// the scalars don't need to be reduced modulo r.
+var (
+	seed               big.Int // x0, seed of BLS377
+	glvLambda          big.Int
+	thirdRootOneG1     big.Int // phi(P) = lambda*P on G1
+	thirdRootOneG2     big.Int // phi(P) = lambda*P on G2
+	glvMaxScalarBitLen int     // scalars on more bits can't be decomposed without overflowing the snark field
+)
+
+// hintGLVDecomposition id of the hint computing (s mod lambda, s / lambda)
+const hintGLVDecomposition = "sw.bls377.GLVDecomposition"
+
+func init() {
+	seed.SetString("9586122913090633729", 10)
+	glvLambda.Mul(&seed, &seed).Sub(&glvLambda, big.NewInt(1)) // x0**2-1
+
+	var omega fp.Element
+	omega.SetString("80949648264912719408558363140637477264845294720710499478137287262712535938301461879813459410945")
+	omega.ToBigIntRegular(&thirdRootOneG1)
+	omega.Square(&omega).ToBigIntRegular(&thirdRootOneG2)
+
+	// s1, s2 being on nbBits bits (even), s1 + lambda*s2 < 2**(nbBits+lambda.BitLen()) must not overflow BLS377's Fp
+	nbBitsMax := fp.Modulus().BitLen() - 1 - glvLambda.BitLen()
+	nbBitsMax -= nbBitsMax % 2
+	glvMaxScalarBitLen = nbBitsMax + glvLambda.BitLen() - 1
+
+	backend.RegisterHint(hintGLVDecomposition, glvDecomposition)
+}
+
+// glvDecomposition hint computing s1 = s mod lambda, s2 = s / lambda
+func glvDecomposition(inputs []big.Int, outputs []big.Int) error {
+	outputs[1].DivMod(&inputs[0], &glvLambda, &outputs[0])
+	return nil
+}
+
+// scalarBits returns the bits (little endian) of the scalars used by the scalar multiplication of a point
+// by s, where s < 2**n: the bits of s if n is small, the bits of s1, s2 (s = s1 + lambda*s2) otherwise.
+// The number of bits is even, so that they are consumed by windows of 2 bits.
+func scalarBits(cs *frontend.ConstraintSystem, s frontend.Variable, n int) (s1, s2 []frontend.Variable) {
+
+	if n > glvMaxScalarBitLen {
+		panic("sw: the scalars must fit on at most " + big.NewInt(int64(glvMaxScalarBitLen)).String() + " bits")
+	}
+
+	if n <= glvLambda.BitLen() {
+		return cs.ToBinary(s, n+n%2), nil
+	}
+
+	// s2 < 2**n / lambda < 2**(n-lambda.BitLen()+1)
+	nbBits := glvLambda.BitLen()
+	if n-glvLambda.BitLen()+1 > nbBits {
+		nbBits = n - glvLambda.BitLen() + 1
+	}
+	nbBits += nbBits % 2
+
+	d := cs.NewHint(hintGLVDecomposition, 2, s)
+	s1 = cs.ToBinary(d[0], nbBits)
+	s2 = cs.ToBinary(d[1], nbBits)
+
+	// s == s1 + lambda*s2
+	cs.AssertIsEqual(s, cs.LinearExpression(
+		cs.Term(d[0], big.NewInt(1)),
+		cs.Term(d[1], &glvLambda),
+	))
+
+	return s1, s2
+}
+
+// lookup returns coeff*t[b0+2*b1] (b0b1 = b0*b1) as a linear expression, where t[0] is the constant t0: 3C
+func lookup(cs *frontend.ConstraintSystem, b0, b1, b0b1 frontend.Variable, t0 int64, t1, t2, t3 frontend.Variable, coeff *big.Int) r1c.LinearExpression {
+
+	one := cs.Constant(1)
+	bOne := big.NewInt(1)
+	var c, minusC, cT0, minusCT0 big.Int
+	c.Set(coeff)
+	minusC.Neg(coeff)
+	cT0.SetInt64(t0).Mul(&cT0, coeff)
+	minusCT0.Neg(&cT0)
+
+	// t0 + b0*(t1-t0) + b1*(t2-t0) + b0*b1*(t3-t2-t1+t0)
+	m1 := cs.Mul(b0, cs.LinearExpression(cs.Term(t1, &c), cs.Term(one, &minusCT0)))
+	m2 := cs.Mul(b1, cs.LinearExpression(cs.Term(t2, &c), cs.Term(one, &minusCT0)))
+	m3 := cs.Mul(b0b1, cs.LinearExpression(
+		cs.Term(t3, &c),
+		cs.Term(t2, &minusC),
+		cs.Term(t1, &minusC),
+		cs.Term(one, &cT0),
+	))
+
+	return cs.LinearExpression(
+		cs.Term(one, &cT0),
+		cs.Term(m1, bOne),
+		cs.Term(m2, bOne),
+		cs.Term(m3, bOne),
+	)
+}
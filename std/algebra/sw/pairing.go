@@ -209,3 +209,96 @@ func MillerLoopAffine(cs *frontend.ConstraintSystem, P G1Affine, Q G2Affine, res
 
 	return res
 }
+
+// MillerLoopAffineMulti computes the product of the miller loops of the pairs (P[i], Q[i]), with points in affine.
+// The loops are interleaved, so that the squarings of the accumulator are shared by all the pairs.
+// None of the points can be the point at infinity, which has no affine coordinates: with such a
+// point, the result is not the product of the pairings, or the circuit is not satisfiable.
+func MillerLoopAffineMulti(cs *frontend.ConstraintSystem, P []G1Affine, Q []G2Affine, res *fields.E12, pairingInfo PairingContext) *fields.E12 {
+
+	if len(P) != len(Q) || len(P) == 0 {
+		panic("sw: the number of G1 and G2 points must be equal and non zero")
+	}
+
+	var ateLoopNaf [64]int8
+	var ateLoopBigInt big.Int
+	ateLoopBigInt.SetUint64(pairingInfo.AteLoop)
+	utils.NafDecomposition(&ateLoopBigInt, ateLoopNaf[:])
+
+	res.SetOne(cs)
+
+	// the lines go through QCur[k] and QNext[k]
+	QCur := make([]G2Affine, len(Q))
+	QNeg := make([]G2Affine, len(Q))
+	copy(QCur, Q)
+	for k := 0; k < len(Q); k++ {
+		QNeg[k].Neg(cs, &Q[k])
+	}
+
+	var QNext, QNextNeg G2Affine
+	var lEval LineEvalRes
+
+	// Miller loop
+	for i := len(ateLoopNaf) - 2; i >= 0; i-- {
+
+		// res is one at the first iteration
+		if i != len(ateLoopNaf)-2 {
+			res.Mul(cs, res, res, pairingInfo.Extension)
+		}
+
+		for k := 0; k < len(P); k++ {
+			QNext = QCur[k]
+			QNext.Double(cs, &QNext, pairingInfo.Extension)
+			QNextNeg.Neg(cs, &QNext)
+
+			// evaluates line though Qcur,2Qcur at P
+			LineEvalAffineBLS377(cs, QCur[k], QNextNeg, P[k], &lEval, pairingInfo.Extension)
+			lEval.MulAssign(cs, res, pairingInfo.Extension)
+
+			if ateLoopNaf[i] == 1 {
+				// evaluates line through 2Qcur, Q at P
+				LineEvalAffineBLS377(cs, QNext, Q[k], P[k], &lEval, pairingInfo.Extension)
+				lEval.MulAssign(cs, res, pairingInfo.Extension)
+
+				QNext.AddAssign(cs, &Q[k], pairingInfo.Extension)
+
+			} else if ateLoopNaf[i] == -1 {
+				// evaluates line through 2Qcur, -Q at P
+				LineEvalAffineBLS377(cs, QNext, QNeg[k], P[k], &lEval, pairingInfo.Extension)
+				lEval.MulAssign(cs, res, pairingInfo.Extension)
+
+				QNext.AddAssign(cs, &QNeg[k], pairingInfo.Extension)
+			}
+
+			QCur[k] = QNext
+		}
+	}
+
+	return res
+}
+
+// PairingCheck checks that the product of the pairings e(P[i], Q[i]) is one, using
+// a multi miller loop (cf MillerLoopAffineMulti) and a single final exponentiation.
+// As for MillerLoopAffineMulti, none of the points can be the point at infinity.
+//
+// Number of constraints (BLS377 in BW761, cf TestPairingCheckNbConstraints), compared
+// to separate miller loops whose results are multiplied:
+//
+//	pairs  PairingCheck  separate miller loops
+//	1      42766         42981
+//	2      55077         69063
+//	3      67388         95145
+//	4      79699         121227
+//	5      92010         147309
+//	6      104321        173391
+//	7      116632        199473
+//	8      128943        225555
+func PairingCheck(cs *frontend.ConstraintSystem, P []G1Affine, Q []G2Affine, pairingInfo PairingContext) {
+
+	var ml, res, one fields.E12
+	MillerLoopAffineMulti(cs, P, Q, &ml, pairingInfo)
+	res.FinalExpoBLS(cs, &ml, pairingInfo.AteLoop, pairingInfo.Extension)
+
+	one.SetOne(cs)
+	res.MustBeEqual(cs, one)
+}
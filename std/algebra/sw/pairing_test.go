@@ -17,6 +17,7 @@ limitations under the License.
 package sw
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
@@ -222,6 +223,121 @@ func TestPairingBLS377(t *testing.T) {
 
 }
 
+type pairingCheckBLS377 struct {
+	P []G1Affine `gnark:",public"`
+	Q []G2Affine
+}
+
+func (circuit *pairingCheckBLS377) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+
+	ateLoop := uint64(9586122913090633729)
+	ext := fields.GetBLS377ExtensionFp12(cs)
+	pairingInfo := PairingContext{AteLoop: ateLoop, Extension: ext}
+
+	PairingCheck(cs, circuit.P, circuit.Q, pairingInfo)
+
+	return nil
+}
+
+// pairingCheckData returns n pairs such that prod(e(P[i], Q[i])) = 1
+func pairingCheckData(n int) (P []bls377.G1Affine, Q []bls377.G2Affine) {
+	_, _, g1, g2 := bls377.Generators()
+
+	P = make([]bls377.G1Affine, n)
+	Q = make([]bls377.G2Affine, n)
+
+	// P[i] = a_i*g1, Q[i] = b_i*g2, and the last pair is (-sum(a_i*b_i)*g1, g2)
+	var a, b, sum big.Int
+	for i := 0; i < n-1; i++ {
+		a.SetInt64(int64(3*i + 2))
+		b.SetInt64(int64(5*i + 7))
+		P[i].ScalarMultiplication(&g1, &a)
+		Q[i].ScalarMultiplication(&g2, &b)
+		sum.Add(&sum, a.Mul(&a, &b))
+	}
+	P[n-1].ScalarMultiplication(&g1, &sum).Neg(&P[n-1])
+	Q[n-1] = g2
+	if n == 1 {
+		// e(P, Q) = 1 iff P or Q is the point at infinity, which is not supported
+		P[0] = g1
+	}
+
+	return
+}
+
+func TestPairingCheckBLS377(t *testing.T) {
+
+	assert := groth16.NewAssert(t)
+
+	for _, n := range []int{2, 3} {
+		P, Q := pairingCheckData(n)
+
+		circuit := pairingCheckBLS377{P: make([]G1Affine, n), Q: make([]G2Affine, n)}
+		r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		witness := pairingCheckBLS377{P: make([]G1Affine, n), Q: make([]G2Affine, n)}
+		for i := 0; i < n; i++ {
+			witness.P[i].Assign(&P[i])
+			witness.Q[i].Assign(&Q[i])
+		}
+		assert.SolvingSucceeded(r1cs, &witness)
+
+		// the product is e(P[n-1], Q[n-1]) != 1
+		Q[n-1].ScalarMultiplication(&Q[n-1], big.NewInt(2))
+		witness.Q[n-1] = G2Affine{}
+		witness.Q[n-1].Assign(&Q[n-1])
+		assert.SolvingFailed(r1cs, &witness)
+	}
+}
+
+type pairingCheckSeparateBLS377 struct {
+	P []G1Affine `gnark:",public"`
+	Q []G2Affine
+}
+
+// Define computes the miller loops of the pairs separately, as reference for the number of constraints of PairingCheck
+func (circuit *pairingCheckSeparateBLS377) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+
+	ateLoop := uint64(9586122913090633729)
+	ext := fields.GetBLS377ExtensionFp12(cs)
+	pairingInfo := PairingContext{AteLoop: ateLoop, Extension: ext}
+
+	var ml, tmp, res, one fields.E12
+	MillerLoopAffine(cs, circuit.P[0], circuit.Q[0], &ml, pairingInfo)
+	for i := 1; i < len(circuit.P); i++ {
+		MillerLoopAffine(cs, circuit.P[i], circuit.Q[i], &tmp, pairingInfo)
+		ml.Mul(cs, &ml, &tmp, ext)
+	}
+	res.FinalExpoBLS(cs, &ml, ateLoop, ext)
+	one.SetOne(cs)
+	res.MustBeEqual(cs, one)
+
+	return nil
+}
+
+func TestPairingCheckNbConstraints(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		multi := pairingCheckBLS377{P: make([]G1Affine, n), Q: make([]G2Affine, n)}
+		r1csMulti, err := frontend.Compile(gurvy.BW761, &multi)
+		if err != nil {
+			t.Fatal(err)
+		}
+		separate := pairingCheckSeparateBLS377{P: make([]G1Affine, n), Q: make([]G2Affine, n)}
+		r1csSeparate, err := frontend.Compile(gurvy.BW761, &separate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nbMulti, nbSeparate := r1csMulti.GetNbConstraints(), r1csSeparate.GetNbConstraints()
+		t.Logf("%d pairs: %d constraints (%d with separate miller loops)", n, nbMulti, nbSeparate)
+		if n > 1 && nbMulti >= nbSeparate {
+			t.Fatal("the multi miller loop should be cheaper than separate miller loops")
+		}
+	}
+}
+
 func pairingData() (P bls377.G1Affine, Q bls377.G2Affine, pairingRes bls377.GT) {
 	P.X.SetString("68333130937826953018162399284085925021577172705782285525244777453303237942212457240213897533859360921141590695983")
 	P.Y.SetString("243386584320553125968203959498080829207604143167922579970841210259134422887279629198736754149500839244552761526603")
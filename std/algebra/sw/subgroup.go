@@ -0,0 +1,116 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sw
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields"
+	"github.com/consensys/gurvy/bw761/fr"
+)
+
+// hintInverse id of the hint computing the inverse of a non zero element of BW761's Fr (0 otherwise)
+const hintInverse = "sw.bw761.Inverse"
+
+func init() {
+	backend.RegisterHint(hintInverse, inverse)
+}
+
+// inverse hint computing 1/v, or 0 if v is 0 (the assertion of mustBeNonZero is then not satisfied)
+func inverse(inputs []big.Int, outputs []big.Int) error {
+	if inputs[0].Sign() != 0 {
+		outputs[0].ModInverse(&inputs[0], fr.Modulus())
+	}
+	return nil
+}
+
+// mustBeNonZero checks that v is not zero, with an inverse computed by a hint: 2C
+func mustBeNonZero(cs *frontend.ConstraintSystem, v frontend.Variable) {
+	vInv := cs.NewHint(hintInverse, 1, v)[0]
+	cs.AssertIsEqual(cs.Mul(v, vInv), 1)
+}
+
+// MustBeInSubgroup checks that p, on the curve, is in the subgroup of order r, that is
+// p + x0**2*phi(p) is the point at infinity (cf bls377.G1Jac.IsInSubGroup), phi being the endomorphism
+// (x, y) -> (omega*x, y) of glv.go: ~1330C
+//
+// The multiplications by x0 use the complete formulas, which output (0:0:0) on points of order 2, so
+// the Y coordinate of the result is checked to be non zero as well.
+func (p *G1Affine) MustBeInSubgroup(cs *frontend.ConstraintSystem) {
+
+	var phiP, res G1Proj
+	phiP.X = cs.Mul(p.X, &thirdRootOneG1)
+	phiP.Y = p.Y
+	phiP.Z = cs.Constant(1)
+	phiP.mulBySeed(cs, &phiP).mulBySeed(cs, &phiP)
+
+	res.FromAffine(cs, p).AddAssign(cs, &phiP)
+
+	cs.AssertIsEqual(res.X, 0)
+	cs.AssertIsEqual(res.Z, 0)
+	mustBeNonZero(cs, res.Y)
+}
+
+// mulBySeed sets p to x0*p1, x0 being the seed of BLS377, and returns it: 63 doublings and 6 additions
+func (p *G1Proj) mulBySeed(cs *frontend.ConstraintSystem, p1 *G1Proj) *G1Proj {
+	q := *p1
+	res := *p1
+	for i := seed.BitLen() - 2; i >= 0; i-- {
+		res.Double(cs, &res)
+		if seed.Bit(i) == 1 {
+			res.AddAssign(cs, &q)
+		}
+	}
+	*p = res
+	return p
+}
+
+// MustBeInSubgroup checks that p, on the twist, is in the subgroup of order r, that is
+// p + x0**2*phi(p) is the point at infinity (cf bls377.G2Jac.IsInSubGroup and G1Affine.MustBeInSubgroup): ~7620C
+func (p *G2Affine) MustBeInSubgroup(cs *frontend.ConstraintSystem, ext fields.Extension) {
+
+	var phiP, res G2Proj
+	phiP.FromAffine(cs, p)
+	phiP.X.MulByFp(cs, &p.X, &thirdRootOneG2)
+	phiP.mulBySeed(cs, &phiP, ext).mulBySeed(cs, &phiP, ext)
+
+	res.FromAffine(cs, p).AddAssign(cs, &phiP, ext)
+
+	cs.AssertIsEqual(res.X.A0, 0)
+	cs.AssertIsEqual(res.X.A1, 0)
+	cs.AssertIsEqual(res.Z.A0, 0)
+	cs.AssertIsEqual(res.Z.A1, 0)
+
+	// Y != 0 iff its norm is non zero
+	mustBeNonZero(cs, res.Y.Norm(cs, ext))
+}
+
+// mulBySeed sets p to x0*p1, x0 being the seed of BLS377, and returns it: 63 doublings and 6 additions
+func (p *G2Proj) mulBySeed(cs *frontend.ConstraintSystem, p1 *G2Proj, ext fields.Extension) *G2Proj {
+	q := *p1
+	res := *p1
+	for i := seed.BitLen() - 2; i >= 0; i-- {
+		res.Double(cs, &res, ext)
+		if seed.Bit(i) == 1 {
+			res.AddAssign(cs, &q, ext)
+		}
+	}
+	*p = res
+	return p
+}
@@ -21,10 +21,14 @@ import (
 	"math/big"
 
 	"github.com/consensys/gnark/backend"
+	edbls377 "github.com/consensys/gnark/crypto/twistededwards/bls377"
+	edbw761 "github.com/consensys/gnark/crypto/twistededwards/bw761"
 	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
 	edbls381 "github.com/consensys/gurvy/bls381/twistededwards"
 	"github.com/consensys/gurvy/bn256/fr"
 	edbn256 "github.com/consensys/gurvy/bn256/twistededwards"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
 )
 
 // EdCurve stores the info on the chosen edwards curve
@@ -39,6 +43,13 @@ func init() {
 	newTwistedEdwards = make(map[gurvy.ID]func() EdCurve)
 	newTwistedEdwards[gurvy.BLS381] = newEdBLS381
 	newTwistedEdwards[gurvy.BN256] = newEdBN256
+	newTwistedEdwards[gurvy.BLS377] = newEdBLS377
+	newTwistedEdwards[gurvy.BW761] = newEdBW761
+
+	for _, constructor := range newTwistedEdwards {
+		curve := constructor()
+		backend.RegisterHint(hintCofactorDivision(curve.ID), cofactorDivision(curve))
+	}
 }
 
 // NewEdCurve returns an Edwards curve parameters
@@ -94,3 +105,45 @@ func newEdBLS381() EdCurve {
 	return res
 
 }
+
+func newEdBLS377() EdCurve {
+
+	edcurve := edbls377.GetEdwardsCurve()
+	var cofactorReg big.Int
+	edcurve.Cofactor.ToBigInt(&cofactorReg)
+
+	res := EdCurve{
+		A:        backend.FromInterface(edcurve.A),
+		D:        backend.FromInterface(edcurve.D),
+		Cofactor: backend.FromInterface(cofactorReg),
+		Order:    backend.FromInterface(edcurve.Order),
+		BaseX:    backend.FromInterface(edcurve.Base.X),
+		BaseY:    backend.FromInterface(edcurve.Base.Y),
+		ID:       gurvy.BLS377,
+	}
+	res.Modulus.Set(fr_bls377.Modulus())
+
+	return res
+
+}
+
+func newEdBW761() EdCurve {
+
+	edcurve := edbw761.GetEdwardsCurve()
+	var cofactorReg big.Int
+	edcurve.Cofactor.ToBigInt(&cofactorReg)
+
+	res := EdCurve{
+		A:        backend.FromInterface(edcurve.A),
+		D:        backend.FromInterface(edcurve.D),
+		Cofactor: backend.FromInterface(cofactorReg),
+		Order:    backend.FromInterface(edcurve.Order),
+		BaseX:    backend.FromInterface(edcurve.Base.X),
+		BaseY:    backend.FromInterface(edcurve.Base.Y),
+		ID:       gurvy.BW761,
+	}
+	res.Modulus.Set(fr_bw761.Modulus())
+
+	return res
+
+}
@@ -18,9 +18,12 @@ package twistededwards
 
 import (
 	"math/big"
+	"math/bits"
 
 	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/backend/r1cs/r1c"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
 )
 
 // Point point on a twisted Edwards curve in a Snark cs
@@ -50,6 +53,53 @@ func (p *Point) MustBeOnCurve(cs *frontend.ConstraintSystem, curve EdCurve) {
 
 }
 
+// MustBeInSubgroup checks that p, on the curve, is in the subgroup of prime order curve.Order
+//
+// cofactor*E is this subgroup (the cofactor and the order are coprime), so p must be cofactor*q for a
+// point q on the curve, computed by a hint: about 25 constraints instead of a scalar multiplication by the order.
+func (p *Point) MustBeInSubgroup(cs *frontend.ConstraintSystem, curve EdCurve) {
+
+	q := cs.NewHint(hintCofactorDivision(curve.ID), 2, p.X, p.Y)
+	res := Point{q[0], q[1]}
+	res.MustBeOnCurve(cs, curve)
+
+	for i := curve.Cofactor.BitLen() - 2; i >= 0; i-- {
+		res = doublePoint(cs, res.X, res.Y, curve)
+		if curve.Cofactor.Bit(i) == 1 {
+			res = addPoints(cs, res.X, res.Y, q[0], q[1], curve)
+		}
+	}
+
+	cs.AssertIsEqual(res.X, p.X)
+	cs.AssertIsEqual(res.Y, p.Y)
+}
+
+// hintCofactorDivision id of the hint computing (1/cofactor mod order)*p on the curve id
+func hintCofactorDivision(id gurvy.ID) string {
+	return "twistededwards." + id.String() + ".CofactorDivision"
+}
+
+// cofactorDivision returns the hint computing (1/cofactor mod order)*p, such that cofactor*q = p if p is in the subgroup
+func cofactorDivision(curve EdCurve) backend.HintFunction {
+	var s big.Int
+	s.ModInverse(&curve.Cofactor, &curve.Order)
+	return func(inputs []big.Int, outputs []big.Int) error {
+		var p, res fixedPoint
+		p.x.Set(&inputs[0])
+		p.y.Set(&inputs[1])
+		res.y.SetUint64(1)
+		for i := s.BitLen() - 1; i >= 0; i-- {
+			res.add(&res, &res, curve)
+			if s.Bit(i) == 1 {
+				res.add(&res, &p, curve)
+			}
+		}
+		outputs[0].Set(&res.x)
+		outputs[1].Set(&res.y)
+		return nil
+	}
+}
+
 // AddFixedPoint Adds two points, among which is one fixed point (the base), on a twisted edwards curve (eg jubjub)
 // p1, base, ecurve are respectively: the point to add, a known base point, and the parameters of the twisted edwards curve
 func (p *Point) AddFixedPoint(cs *frontend.ConstraintSystem, p1 *Point, x, y interface{}, curve EdCurve) *Point {
@@ -77,6 +127,205 @@ func (p *Point) AddFixedPoint(cs *frontend.ConstraintSystem, p1 *Point, x, y int
 // AddGeneric Adds two points on a twisted edwards curve (eg jubjub)
 // p1, p2, c are respectively: the point to add, a known base point, and the parameters of the twisted edwards curve
 func (p *Point) AddGeneric(cs *frontend.ConstraintSystem, p1, p2 *Point, curve EdCurve) *Point {
+	*p = addPoints(cs, p1.X, p1.Y, p2.X, p2.Y, curve)
+	return p
+}
+
+// Double doubles a points in SNARK coordinates
+func (p *Point) Double(cs *frontend.ConstraintSystem, p1 *Point, curve EdCurve) *Point {
+	p.AddGeneric(cs, p1, p1, curve)
+	return p
+}
+
+// ClearCofactor multiplies p1 by the cofactor of the curve, whose bits are known at compile time
+func (p *Point) ClearCofactor(cs *frontend.ConstraintSystem, p1 *Point, curve EdCurve) *Point {
+	q := *p1
+	*p = q
+	for i := curve.Cofactor.BitLen() - 2; i >= 0; i-- {
+		p.Double(cs, p, curve)
+		if curve.Cofactor.Bit(i) == 1 {
+			p.AddGeneric(cs, p, &q, curve)
+		}
+	}
+	return p
+}
+
+// ScalarMulNonFixedBase computes the scalar multiplication of a point on a twisted Edwards curve
+// p1: base point (as snark point)
+// curve: parameters of the Edwards curve
+// scal: scalar as a SNARK constraint
+// Standard left to right double and add, on curve.Modulus.BitLen() bits
+// The doublings use the generic addition formula, so p1 does not need to be on the curve
+func (p *Point) ScalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point, scalar frontend.Variable, curve EdCurve) *Point {
+	return p.scalarMulNonFixedBase(cs, p1, scalar, curve.Modulus.BitLen(), curve, doublePointGeneric)
+}
+
+// ScalarMulNonFixedBaseBitLen computes the scalar multiplication of a point on a twisted Edwards curve
+// p1: base point (as snark point), on the curve
+// scalar: scalar as a SNARK constraint, on at most nbBits bits
+// curve: parameters of the Edwards curve
+// Left to right double and add, each bit costs a doubling (5 constraints), the selection of 0 or p1 (2 constraints)
+// and an addition (7 constraints)
+func (p *Point) ScalarMulNonFixedBaseBitLen(cs *frontend.ConstraintSystem, p1 *Point, scalar frontend.Variable, nbBits int, curve EdCurve) *Point {
+	return p.scalarMulNonFixedBase(cs, p1, scalar, nbBits, curve, doublePoint)
+}
+
+// scalarMulNonFixedBase is the left to right double and add shared by ScalarMulNonFixedBase
+// and ScalarMulNonFixedBaseBitLen, double being the doubling formula
+func (p *Point) scalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point, scalar frontend.Variable, nbBits int, curve EdCurve, double func(*frontend.ConstraintSystem, interface{}, interface{}, EdCurve) Point) *Point {
+
+	b := cs.ToBinary(scalar, nbBits)
+
+	one := cs.Constant(1)
+	bOne := big.NewInt(1)
+	bMinusOne := big.NewInt(-1)
+
+	// res is the neutral element (0, 1) until the most significant bit is processed
+	var resX, resY interface{} = 0, 1
+
+	for i := nbBits - 1; i >= 0; i-- {
+
+		// b[i]*p1 = (b[i]*x1, 1+b[i]*(y1-1))
+		x := cs.Mul(b[i], p1.X)
+		y := cs.LinearExpression(
+			cs.Term(one, bOne),
+			cs.Term(cs.Mul(b[i], cs.LinearExpression(cs.Term(p1.Y, bOne), cs.Term(one, bMinusOne))), bOne),
+		)
+
+		if i == nbBits-1 {
+			resX, resY = x, y
+			continue
+		}
+		res := double(cs, resX, resY, curve)
+		res = addPoints(cs, res.X, res.Y, x, y, curve)
+		resX, resY = res.X, res.Y
+	}
+
+	p.X = toVariable(cs, resX)
+	p.Y = toVariable(cs, resY)
+	return p
+}
+
+// ScalarMulFixedBase computes the scalar multiplication of a point on a twisted Edwards curve
+// x, y: coordinates of the base point
+// curve: parameters of the Edwards curve
+// scal: scalar as a SNARK constraint
+// Fixed base windowed method on curve.Modulus.BitLen() bits (cf ScalarMulFixedBaseWindowed)
+func (p *Point) ScalarMulFixedBase(cs *frontend.ConstraintSystem, x, y interface{}, scalar frontend.Variable, curve EdCurve) *Point {
+	return p.ScalarMulFixedBaseWindowed(cs, x, y, scalar, curve.Modulus.BitLen(), curve)
+}
+
+// windowSize number of bits of the scalar consumed by a lookup in ScalarMulFixedBaseWindowed
+const windowSize = 3
+
+// ScalarMulFixedBaseWindowed computes the scalar multiplication of a point known at compile time
+// x, y: coordinates of the base point
+// scalar: scalar as a SNARK constraint, on at most nbBits bits
+// curve: parameters of the Edwards curve
+// The multiples j*2^(3i)*base (0 <= j < 8) are precomputed, so that the i-th window of 3 bits of the scalar
+// costs a lookup in a table of constants (4 constraints) and an addition (7 constraints), without doublings.
+func (p *Point) ScalarMulFixedBaseWindowed(cs *frontend.ConstraintSystem, x, y interface{}, scalar frontend.Variable, nbBits int, curve EdCurve) *Point {
+
+	b := cs.ToBinary(scalar, nbBits)
+
+	var base fixedPoint
+	base.x = backend.FromInterface(x)
+	base.y = backend.FromInterface(y)
+
+	var resX, resY interface{} = 0, 1
+
+	for i := 0; i < nbBits; i += windowSize {
+
+		w := b[i:]
+		if len(w) > windowSize {
+			w = w[:windowSize]
+		}
+
+		// table[j] = j*base
+		table := make([]fixedPoint, 1<<len(w))
+		table[0].y.SetUint64(1)
+		for j := 1; j < len(table); j++ {
+			table[j].add(&table[j-1], &base, curve)
+		}
+
+		tx, ty := lookup(cs, w, table, curve)
+		if i == 0 {
+			resX, resY = tx, ty
+		} else {
+			res := addPoints(cs, resX, resY, tx, ty, curve)
+			resX, resY = res.X, res.Y
+		}
+
+		// base = 2^len(w)*base
+		base.add(&table[len(table)-1], &base, curve)
+	}
+
+	p.X = toVariable(cs, resX)
+	p.Y = toVariable(cs, resY)
+	return p
+}
+
+// DoubleBaseScalarMul computes s1*p1 + s2*p2 on a twisted Edwards curve
+// p1, p2: points (as snark points), on the curve
+// s1, s2: scalars as SNARK constraints, on at most nbBits bits
+// curve: parameters of the Edwards curve
+// The doublings are shared by both multiplications (Straus-Shamir): each bit costs a doubling (5 constraints),
+// the selection of 0, p1, p2 or p1+p2 (6 constraints) and an addition (7 constraints)
+func (p *Point) DoubleBaseScalarMul(cs *frontend.ConstraintSystem, p1, p2 *Point, s1, s2 frontend.Variable, nbBits int, curve EdCurve) *Point {
+
+	b1 := cs.ToBinary(s1, nbBits)
+	b2 := cs.ToBinary(s2, nbBits)
+
+	p12 := addPoints(cs, p1.X, p1.Y, p2.X, p2.Y, curve)
+
+	one := cs.Constant(1)
+	bOne := big.NewInt(1)
+	bMinusOne := big.NewInt(-1)
+
+	var resX, resY interface{} = 0, 1
+
+	for i := nbBits - 1; i >= 0; i-- {
+
+		// u = b1 ? p1+p2 : p2, v = b1 ? p1 : 0, selected point = b2 ? u : v
+		ux := cs.Mul(b1[i], cs.LinearExpression(cs.Term(p12.X, bOne), cs.Term(p2.X, bMinusOne)))
+		uy := cs.Mul(b1[i], cs.LinearExpression(cs.Term(p12.Y, bOne), cs.Term(p2.Y, bMinusOne)))
+		vx := cs.Mul(b1[i], p1.X)
+		vy := cs.Mul(b1[i], cs.LinearExpression(cs.Term(p1.Y, bOne), cs.Term(one, bMinusOne)))
+		x := cs.LinearExpression(
+			cs.Term(vx, bOne),
+			cs.Term(cs.Mul(b2[i], cs.LinearExpression(
+				cs.Term(p2.X, bOne),
+				cs.Term(ux, bOne),
+				cs.Term(vx, bMinusOne),
+			)), bOne),
+		)
+		y := cs.LinearExpression(
+			cs.Term(one, bOne),
+			cs.Term(vy, bOne),
+			cs.Term(cs.Mul(b2[i], cs.LinearExpression(
+				cs.Term(p2.Y, bOne),
+				cs.Term(uy, bOne),
+				cs.Term(one, bMinusOne),
+				cs.Term(vy, bMinusOne),
+			)), bOne),
+		)
+
+		if i == nbBits-1 {
+			resX, resY = x, y
+			continue
+		}
+		res := doublePoint(cs, resX, resY, curve)
+		res = addPoints(cs, res.X, res.Y, x, y, curve)
+		resX, resY = res.X, res.Y
+	}
+
+	p.X = toVariable(cs, resX)
+	p.Y = toVariable(cs, resY)
+	return p
+}
+
+// addPoints returns (x1, y1) + (x2, y2), the coordinates being variables, linear expressions or constants: 7C
+func addPoints(cs *frontend.ConstraintSystem, x1, y1, x2, y2 interface{}, curve EdCurve) Point {
 
 	// https://eprint.iacr.org/2008/013.pdf
 	res := Point{}
@@ -85,10 +334,10 @@ func (p *Point) AddGeneric(cs *frontend.ConstraintSystem, p1, p2 *Point, curve E
 
 	oneWire := cs.Constant(one)
 
-	beta := cs.Mul(p1.X, p2.Y)
-	gamma := cs.Mul(p1.Y, p2.X)
-	delta := cs.Mul(p1.Y, p2.Y)
-	epsilon := cs.Mul(p1.X, p2.X)
+	beta := cs.Mul(x1, y2)
+	gamma := cs.Mul(y1, x2)
+	delta := cs.Mul(y1, y2)
+	epsilon := cs.Mul(x1, x2)
 	tau := cs.Mul(delta, epsilon)
 	num := cs.LinearExpression(
 		cs.Term(beta, one),
@@ -113,69 +362,131 @@ func (p *Point) AddGeneric(cs *frontend.ConstraintSystem, p1, p2 *Point, curve E
 	)
 	res.Y = cs.Div(num, den)
 
-	p.X = res.X
-	p.Y = res.Y
-	return p
+	return res
 }
 
-// Double doubles a points in SNARK coordinates
-func (p *Point) Double(cs *frontend.ConstraintSystem, p1 *Point, curve EdCurve) *Point {
-	p.AddGeneric(cs, p1, p1, curve)
-	return p
+// doublePointGeneric doubles (x, y) with the addition formula, which holds
+// whether or not (x, y) is on the curve
+func doublePointGeneric(cs *frontend.ConstraintSystem, x, y interface{}, curve EdCurve) Point {
+	return addPoints(cs, x, y, x, y, curve)
 }
 
-// ScalarMulNonFixedBase computes the scalar multiplication of a point on a twisted Edwards curve
-// p1: base point (as snark point)
-// curve: parameters of the Edwards curve
-// scal: scalar as a SNARK constraint
-// Standard left to right double and add
-func (p *Point) ScalarMulNonFixedBase(cs *frontend.ConstraintSystem, p1 *Point, scalar frontend.Variable, curve EdCurve) *Point {
+// doublePoint returns 2*(x, y), for a point on the curve: 5C
+//
+// ax^2+y^2 = 1+dx^2y^2 on the curve, so that 2*(x, y) = (2xy / (ax^2+y^2), (y^2-ax^2) / (2-ax^2-y^2))
+func doublePoint(cs *frontend.ConstraintSystem, x, y interface{}, curve EdCurve) Point {
+
+	res := Point{}
 
-	// first unpack the scalar
-	b := cs.ToBinary(scalar, 256)
+	one := big.NewInt(1)
+	two := big.NewInt(2)
+	var minusOne, minusa big.Int
+	minusOne.SetInt64(-1).Mod(&minusOne, &curve.Modulus)
+	minusa.Neg(&curve.A).Mod(&minusa, &curve.Modulus)
 
-	res := Point{
-		cs.Constant(0),
-		cs.Constant(1),
-	}
+	oneWire := cs.Constant(one)
 
-	for i := len(b) - 1; i >= 0; i-- {
-		res.Double(cs, &res, curve)
-		tmp := Point{}
-		tmp.AddGeneric(cs, &res, p1, curve)
-		res.X = cs.Select(b[i], tmp.X, res.X)
-		res.Y = cs.Select(b[i], tmp.Y, res.Y)
-	}
+	xx := cs.Mul(x, x)
+	yy := cs.Mul(y, y)
+	xy := cs.Mul(x, y)
 
-	p.X = res.X
-	p.Y = res.Y
-	return p
+	res.X = cs.Div(
+		cs.LinearExpression(cs.Term(xy, two)),
+		cs.LinearExpression(cs.Term(xx, &curve.A), cs.Term(yy, one)),
+	)
+	res.Y = cs.Div(
+		cs.LinearExpression(cs.Term(yy, one), cs.Term(xx, &minusa)),
+		cs.LinearExpression(cs.Term(oneWire, two), cs.Term(xx, &minusa), cs.Term(yy, &minusOne)),
+	)
+
+	return res
 }
 
-// ScalarMulFixedBase computes the scalar multiplication of a point on a twisted Edwards curve
-// x, y: coordinates of the base point
-// curve: parameters of the Edwards curve
-// scal: scalar as a SNARK constraint
-// Standard left to right double and add
-func (p *Point) ScalarMulFixedBase(cs *frontend.ConstraintSystem, x, y interface{}, scalar frontend.Variable, curve EdCurve) *Point {
+// lookup returns the coordinates of table[b[0] + 2*b[1] + 4*b[2] + ...] as linear expressions, the points
+// of table being known at compile time: 2^len(b)-len(b)-1 constraints
+//
+// Each coordinate is the multilinear polynomial in the bits interpolating the table on {0, 1}^len(b), whose
+// monomials are computed once for both coordinates.
+func lookup(cs *frontend.ConstraintSystem, b []frontend.Variable, table []fixedPoint, curve EdCurve) (x, y r1c.LinearExpression) {
+
+	// monomials[s] = product of the b[i] such that the i-th bit of s is set
+	monomials := make([]frontend.Variable, len(table))
+	monomials[0] = cs.Constant(1)
+	for s := 1; s < len(monomials); s++ {
+		i := bits.Len(uint(s)) - 1
+		if rest := s ^ (1 << i); rest == 0 {
+			monomials[s] = b[i]
+		} else {
+			monomials[s] = cs.Mul(monomials[rest], b[i])
+		}
+	}
 
-	// first unpack the scalar
-	b := cs.ToBinary(scalar, 256)
+	// coefficients of the monomials (Moebius transform of the table)
+	cx := make([]big.Int, len(table))
+	cy := make([]big.Int, len(table))
+	for s := 0; s < len(table); s++ {
+		cx[s].Set(&table[s].x)
+		cy[s].Set(&table[s].y)
+	}
+	for i := 0; i < len(b); i++ {
+		for s := 0; s < len(table); s++ {
+			if s&(1<<i) != 0 {
+				cx[s].Sub(&cx[s], &cx[s^(1<<i)]).Mod(&cx[s], &curve.Modulus)
+				cy[s].Sub(&cy[s], &cy[s^(1<<i)]).Mod(&cy[s], &curve.Modulus)
+			}
+		}
+	}
 
-	res := Point{
-		cs.Constant(0),
-		cs.Constant(1),
+	x = make(r1c.LinearExpression, len(table))
+	y = make(r1c.LinearExpression, len(table))
+	for s := 0; s < len(table); s++ {
+		x[s] = cs.Term(monomials[s], &cx[s])
+		y[s] = cs.Term(monomials[s], &cy[s])
 	}
 
-	for i := len(b) - 1; i >= 0; i-- {
-		res.Double(cs, &res, curve)
-		tmp := Point{}
-		tmp.AddFixedPoint(cs, &res, x, y, curve)
-		res.X = cs.Select(b[i], tmp.X, res.X)
-		res.Y = cs.Select(b[i], tmp.Y, res.Y)
+	return x, y
+}
+
+// toVariable returns v as a variable, v being a variable, a linear expression or a constant
+func toVariable(cs *frontend.ConstraintSystem, v interface{}) frontend.Variable {
+	switch t := v.(type) {
+	case frontend.Variable:
+		return t
+	case r1c.LinearExpression:
+		return cs.Mul(t, 1)
+	default:
+		return cs.Constant(t)
 	}
+}
+
+// fixedPoint affine point of the twisted Edwards curve, known at compile time
+type fixedPoint struct {
+	x, y big.Int
+}
+
+// add sets p = p1 + p2, computed outside the circuit
+func (p *fixedPoint) add(p1, p2 *fixedPoint, curve EdCurve) *fixedPoint {
 
-	p.X = res.X
-	p.Y = res.Y
+	// https://eprint.iacr.org/2008/013.pdf
+	var x1y2, y1x2, y1y2, x1x2, dxy, den, x, y big.Int
+	q := &curve.Modulus
+
+	x1y2.Mul(&p1.x, &p2.y)
+	y1x2.Mul(&p1.y, &p2.x)
+	y1y2.Mul(&p1.y, &p2.y)
+	x1x2.Mul(&p1.x, &p2.x)
+	dxy.Mul(&x1y2, &y1x2).Mul(&dxy, &curve.D).Mod(&dxy, q)
+
+	// x = (x1y2+y1x2) / (1+dx1x2y1y2)
+	den.Add(big.NewInt(1), &dxy).ModInverse(&den, q)
+	x.Add(&x1y2, &y1x2).Mul(&x, &den).Mod(&x, q)
+
+	// y = (y1y2-ax1x2) / (1-dx1x2y1y2)
+	den.Sub(big.NewInt(1), &dxy).Mod(&den, q).ModInverse(&den, q)
+	x1x2.Mul(&x1x2, &curve.A)
+	y.Sub(&y1y2, &x1x2).Mul(&y, &den).Mod(&y, q)
+
+	p.x.Set(&x)
+	p.y.Set(&y)
 	return p
 }
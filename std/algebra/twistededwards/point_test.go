@@ -17,11 +17,14 @@ limitations under the License.
 package twistededwards
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bn256/fr"
+	edbn256 "github.com/consensys/gurvy/bn256/twistededwards"
 )
 
 type mustBeOnCurve struct {
@@ -202,3 +205,190 @@ func TestScalarMul(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 
 }
+
+type scalarMulOffCurve struct {
+	P Point
+}
+
+func (circuit *scalarMulOffCurve) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	params, err := NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	var res, expected Point
+	res.ScalarMulNonFixedBase(cs, &circuit.P, cs.Constant(3), params)
+	expected.Double(cs, &circuit.P, params).AddGeneric(cs, &expected, &circuit.P, params)
+	cs.AssertIsEqual(res.X, expected.X)
+	cs.AssertIsEqual(res.Y, expected.Y)
+	return nil
+}
+
+// ScalarMulNonFixedBase doubles with the generic addition formula, which does not
+// rely on the curve equation, so that it matches AddGeneric for a point off the curve
+func TestScalarMulOffCurve(t *testing.T) {
+	assert := groth16.NewAssert(t)
+	var circuit, witness scalarMulOffCurve
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness.P.X.Assign(2)
+	witness.P.Y.Assign(3)
+	assert.SolvingSucceeded(r1cs, &witness)
+}
+
+type scalarMulBitLen struct {
+	S, T       frontend.Variable
+	P1, P2     Point
+	R1, R2, R3 Point `gnark:",public"` // S*Base, S*P1, S*P1+T*P2
+	nbBits     int
+}
+
+func (circuit *scalarMulBitLen) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	params, err := NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+
+	var r1, r2, r3 Point
+	r1.ScalarMulFixedBaseWindowed(cs, params.BaseX, params.BaseY, circuit.S, circuit.nbBits, params)
+	r2.ScalarMulNonFixedBaseBitLen(cs, &circuit.P1, circuit.S, circuit.nbBits, params)
+	r3.DoubleBaseScalarMul(cs, &circuit.P1, &circuit.P2, circuit.S, circuit.T, circuit.nbBits, params)
+
+	cs.AssertIsEqual(r1.X, circuit.R1.X)
+	cs.AssertIsEqual(r1.Y, circuit.R1.Y)
+	cs.AssertIsEqual(r2.X, circuit.R2.X)
+	cs.AssertIsEqual(r2.Y, circuit.R2.Y)
+	cs.AssertIsEqual(r3.X, circuit.R3.X)
+	cs.AssertIsEqual(r3.Y, circuit.R3.Y)
+
+	return nil
+}
+
+func TestScalarMulBitLen(t *testing.T) {
+
+	edcurve := edbn256.GetEdwardsCurve()
+
+	// scalarMul returns s*p, computed with the go implementation
+	scalarMul := func(p edbn256.Point, s *big.Int) edbn256.Point {
+		var e fr.Element
+		e.SetBigInt(s).FromMont()
+		var res edbn256.Point
+		res.ScalarMul(&p, e)
+		return res
+	}
+	assign := func(p *Point, q edbn256.Point) {
+		p.X.Assign(q.X)
+		p.Y.Assign(q.Y)
+	}
+
+	p1 := scalarMul(edcurve.Base, big.NewInt(3))
+	p2 := scalarMul(edcurve.Base, big.NewInt(7))
+
+	var sRand, tRand big.Int
+	sRand.SetString("2187301943715981463085106843210925728190487210921843910738291047856382910475", 10)
+	sRand.Mod(&sRand, &edcurve.Order)
+	tRand.SetString("1098374019287431092874312987432198743219874321987432198743210987432109874321", 10)
+	tRand.Mod(&tRand, &edcurve.Order)
+
+	testData := []struct {
+		s, t   *big.Int
+		nbBits int
+	}{
+		{big.NewInt(0), big.NewInt(0), edcurve.Order.BitLen()},
+		{big.NewInt(1), big.NewInt(2), edcurve.Order.BitLen()},
+		{&sRand, &tRand, edcurve.Order.BitLen()},
+		{&sRand, big.NewInt(1), edcurve.Order.BitLen()},
+		{big.NewInt(19), big.NewInt(31), 5},
+		{big.NewInt(1), big.NewInt(0), 1},
+	}
+
+	for _, d := range testData {
+
+		assert := groth16.NewAssert(t)
+		circuit := scalarMulBitLen{nbBits: d.nbBits}
+		r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var r3 edbn256.Point
+		sP1 := scalarMul(p1, d.s)
+		tP2 := scalarMul(p2, d.t)
+		r3.Add(&sP1, &tP2)
+
+		var witness scalarMulBitLen
+		witness.S.Assign(d.s)
+		witness.T.Assign(d.t)
+		assign(&witness.P1, p1)
+		assign(&witness.P2, p2)
+		assign(&witness.R1, scalarMul(edcurve.Base, d.s))
+		assign(&witness.R2, sP1)
+		assign(&witness.R3, r3)
+		assert.SolvingSucceeded(r1cs, &witness)
+
+		// wrong result
+		var wrongR3 edbn256.Point
+		wrongR3.Add(&r3, &edcurve.Base)
+		var wrongWitness scalarMulBitLen
+		wrongWitness.S.Assign(d.s)
+		wrongWitness.T.Assign(d.t)
+		assign(&wrongWitness.P1, p1)
+		assign(&wrongWitness.P2, p2)
+		assign(&wrongWitness.R1, scalarMul(edcurve.Base, d.s))
+		assign(&wrongWitness.R2, sP1)
+		assign(&wrongWitness.R3, wrongR3)
+		assert.SolvingFailed(r1cs, &wrongWitness)
+	}
+}
+
+type mustBeInSubgroup struct {
+	P Point
+}
+
+func (circuit *mustBeInSubgroup) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	params, err := NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	circuit.P.MustBeOnCurve(cs, params)
+	circuit.P.MustBeInSubgroup(cs, params)
+	return nil
+}
+
+func TestMustBeInSubgroup(t *testing.T) {
+
+	assert := groth16.NewAssert(t)
+	var circuit mustBeInSubgroup
+	r1cs, err := frontend.Compile(gurvy.BN256, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edcurve := edbn256.GetEdwardsCurve()
+
+	var s fr.Element
+	s.SetString("2187301943715981463085106843210925728190487210921843910738291047856382910475").FromMont()
+	var p edbn256.Point
+	p.ScalarMul(&edcurve.Base, s)
+
+	for _, q := range []edbn256.Point{edcurve.Base, p} {
+		var witness mustBeInSubgroup
+		witness.P.X.Assign(q.X)
+		witness.P.Y.Assign(q.Y)
+		assert.SolvingSucceeded(r1cs, &witness)
+	}
+
+	// (0, -1) is of order 2, and (-x, -y) = (x, y) + (0, -1)
+	var minusOne fr.Element
+	minusOne.SetOne().Neg(&minusOne)
+	var torsion edbn256.Point
+	torsion.X.Neg(&p.X)
+	torsion.Y.Neg(&p.Y)
+	for _, q := range []edbn256.Point{{Y: minusOne}, torsion} {
+		var witness mustBeInSubgroup
+		witness.P.X.Assign(q.X)
+		witness.P.Y.Assign(q.Y)
+		assert.SolvingFailed(r1cs, &witness)
+	}
+}
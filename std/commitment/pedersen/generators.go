@@ -0,0 +1,65 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	pedersenbls381 "github.com/consensys/gnark/crypto/commitment/pedersen/bls381"
+	pedersenbn256 "github.com/consensys/gnark/crypto/commitment/pedersen/bn256"
+	"github.com/consensys/gurvy"
+	edbls381 "github.com/consensys/gurvy/bls381/twistededwards"
+	edbn256 "github.com/consensys/gurvy/bn256/twistededwards"
+)
+
+// newGenerators returns the blinding generator, the n other generators and the number of windows per segment
+var newGenerators map[gurvy.ID]func(string, int) (point, []point, int)
+
+func init() {
+	newGenerators = make(map[gurvy.ID]func(string, int) (point, []point, int))
+	newGenerators[gurvy.BN256] = newGeneratorsBN256
+	newGenerators[gurvy.BLS381] = newGeneratorsBLS381
+}
+
+// -------------------------------------------------------------------------------------------------
+// constructors
+
+func newGeneratorsBN256(seed string, n int) (point, []point, int) {
+	params := pedersenbn256.NewParams(seed, n)
+	toPoint := func(p edbn256.Point) (res point) {
+		p.X.ToBigIntRegular(&res.x)
+		p.Y.ToBigIntRegular(&res.y)
+		return
+	}
+	g := make([]point, n)
+	for i := 0; i < n; i++ {
+		g[i] = toPoint(params.G[i])
+	}
+	return toPoint(params.H), g, pedersenbn256.NbWindowsPerSegment
+}
+
+func newGeneratorsBLS381(seed string, n int) (point, []point, int) {
+	params := pedersenbls381.NewParams(seed, n)
+	toPoint := func(p edbls381.Point) (res point) {
+		p.X.ToBigIntRegular(&res.x)
+		p.Y.ToBigIntRegular(&res.y)
+		return
+	}
+	g := make([]point, n)
+	for i := 0; i < n; i++ {
+		g[i] = toPoint(params.G[i])
+	}
+	return toPoint(params.H), g, pedersenbls381.NbWindowsPerSegment
+}
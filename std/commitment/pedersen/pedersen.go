@@ -0,0 +1,208 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gurvy"
+)
+
+// windowSize number of bits consumed by one window of the Pedersen hash
+const windowSize = 3
+
+// Pedersen contains the generators of a Pedersen commitment (and hash) on the twisted Edwards curve
+// of the snark field. The generators are derived from the seed exactly as in crypto/commitment/pedersen.
+type Pedersen struct {
+	curve               twistededwards.EdCurve
+	h                   point   // blinding generator
+	g                   []point // g[i] generator of the i-th value (commitment) or segment (hash)
+	nbWindowsPerSegment int
+}
+
+// point affine point of the twisted Edwards curve, known at compile time
+type point struct {
+	x, y big.Int
+}
+
+// NewPedersen returns a Pedersen instance with n+1 generators derived from seed, than can be used in a gnark circuit
+func NewPedersen(seed string, n int, id gurvy.ID) (Pedersen, error) {
+	constructor, ok := newGenerators[id]
+	if !ok {
+		return Pedersen{}, errors.New("unknown curve id")
+	}
+	curve, err := twistededwards.NewEdCurve(id)
+	if err != nil {
+		return Pedersen{}, err
+	}
+	res := Pedersen{curve: curve}
+	res.h, res.g, res.nbWindowsPerSegment = constructor(seed, n)
+	return res, nil
+}
+
+// Commit returns (in r1cs form) the hiding commitment randomness*H + sum(values[i]*G[i])
+// (cf crypto/commitment/pedersen Commit)
+func (p Pedersen) Commit(cs *frontend.ConstraintSystem, randomness frontend.Variable, values ...frontend.Variable) twistededwards.Point {
+	if len(values) > len(p.g) {
+		panic("pedersen: more values than generators")
+	}
+
+	var res twistededwards.Point
+	res.ScalarMulFixedBase(cs, p.h.x, p.h.y, randomness, p.curve)
+	for i := 0; i < len(values); i++ {
+		var tmp twistededwards.Point
+		tmp.ScalarMulFixedBase(cs, p.g[i].x, p.g[i].y, values[i], p.curve)
+		res.AddGeneric(cs, &res, &tmp, p.curve)
+	}
+
+	return res
+}
+
+// Hash returns (in r1cs form) the windowed Pedersen hash of a bit string (cf crypto/commitment/pedersen Hash)
+//
+// The bits are constrained to be booleans. Each window of 3 bits costs one lookup in a table of
+// 4 precomputed points (3 constraints), and one addition on the twisted Edwards curve.
+func (p Pedersen) Hash(cs *frontend.ConstraintSystem, bits ...frontend.Variable) twistededwards.Point {
+
+	nbWindows := (len(bits) + windowSize - 1) / windowSize
+	nbSegments := (nbWindows + p.nbWindowsPerSegment - 1) / p.nbWindowsPerSegment
+	if nbSegments > len(p.g) {
+		panic("pedersen: more bits than the generators can hash")
+	}
+
+	if len(bits) == 0 {
+		return twistededwards.Point{X: cs.Constant(0), Y: cs.Constant(1)}
+	}
+
+	for i := 0; i < len(bits); i++ {
+		cs.AssertIsBoolean(bits[i])
+	}
+	padded := make([]frontend.Variable, nbWindows*windowSize)
+	copy(padded, bits)
+	for i := len(bits); i < len(padded); i++ {
+		padded[i] = cs.Constant(0)
+	}
+
+	var res twistededwards.Point
+	for j := 0; j < nbSegments; j++ {
+
+		// base = 2^(4*i) * G[j] for the i-th window of the segment
+		base := p.g[j]
+		for i := 0; i < p.nbWindowsPerSegment; i++ {
+			w := j*p.nbWindowsPerSegment + i
+			if w >= nbWindows {
+				break
+			}
+			if i != 0 {
+				for k := 0; k < 4; k++ {
+					base = p.add(base, base)
+				}
+			}
+
+			tmp := p.lookup(cs, base, padded[w*windowSize:(w+1)*windowSize])
+			if w == 0 {
+				res = tmp
+			} else {
+				res.AddGeneric(cs, &res, &tmp, p.curve)
+			}
+		}
+	}
+
+	return res
+}
+
+// lookup returns enc*base, where enc = (1 + b0 + 2*b1) * (1 - 2*b2)
+func (p Pedersen) lookup(cs *frontend.ConstraintSystem, base point, window []frontend.Variable) twistededwards.Point {
+
+	// table[k] = (k+1)*base
+	var table [4]point
+	table[0] = base
+	table[1] = p.add(base, base)
+	table[2] = p.add(table[1], base)
+	table[3] = p.add(table[2], base)
+
+	one := cs.Constant(1)
+	b01 := cs.Mul(window[0], window[1])
+
+	// coordinate c of the selected point is
+	// c0 + b0*(c1-c0) + b1*(c2-c0) + b0*b1*(c3-c2-c1+c0)
+	coeffs := func(c0, c1, c2, c3 *big.Int) (k0, k1, k2, k3 big.Int) {
+		k0.Set(c0)
+		k1.Sub(c1, c0).Mod(&k1, &p.curve.Modulus)
+		k2.Sub(c2, c0).Mod(&k2, &p.curve.Modulus)
+		k3.Sub(c3, c2).Sub(&k3, c1).Add(&k3, c0).Mod(&k3, &p.curve.Modulus)
+		return
+	}
+
+	kx0, kx1, kx2, kx3 := coeffs(&table[0].x, &table[1].x, &table[2].x, &table[3].x)
+	ky0, ky1, ky2, ky3 := coeffs(&table[0].y, &table[1].y, &table[2].y, &table[3].y)
+
+	x := cs.LinearExpression(
+		cs.Term(one, &kx0),
+		cs.Term(window[0], &kx1),
+		cs.Term(window[1], &kx2),
+		cs.Term(b01, &kx3),
+	)
+	y := cs.LinearExpression(
+		cs.Term(one, &ky0),
+		cs.Term(window[0], &ky1),
+		cs.Term(window[1], &ky2),
+		cs.Term(b01, &ky3),
+	)
+
+	// -(x, y) = (-x, y)
+	var minusTwo big.Int
+	minusTwo.SetInt64(-2).Mod(&minusTwo, &p.curve.Modulus)
+	sign := cs.LinearExpression(
+		cs.Term(one, big.NewInt(1)),
+		cs.Term(window[2], &minusTwo),
+	)
+
+	return twistededwards.Point{
+		X: cs.Mul(x, sign),
+		Y: cs.Mul(y, 1),
+	}
+}
+
+// add returns p1+p2, computed outside the circuit
+func (p Pedersen) add(p1, p2 point) point {
+
+	// https://eprint.iacr.org/2008/013.pdf
+	var res point
+	var x1y2, y1x2, y1y2, x1x2, dxy, den big.Int
+	q := &p.curve.Modulus
+
+	x1y2.Mul(&p1.x, &p2.y)
+	y1x2.Mul(&p1.y, &p2.x)
+	y1y2.Mul(&p1.y, &p2.y)
+	x1x2.Mul(&p1.x, &p2.x)
+	dxy.Mul(&x1y2, &y1x2).Mul(&dxy, &p.curve.D).Mod(&dxy, q)
+
+	// x = (x1y2+y1x2) / (1+dx1x2y1y2)
+	den.Add(big.NewInt(1), &dxy).ModInverse(&den, q)
+	res.x.Add(&x1y2, &y1x2).Mul(&res.x, &den).Mod(&res.x, q)
+
+	// y = (y1y2-ax1x2) / (1-dx1x2y1y2)
+	den.Sub(big.NewInt(1), &dxy).Mod(&den, q).ModInverse(&den, q)
+	x1x2.Mul(&x1x2, &p.curve.A)
+	res.y.Sub(&y1y2, &x1x2).Mul(&res.y, &den).Mod(&res.y, q)
+
+	return res
+}
@@ -0,0 +1,219 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pedersen
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	pedersenbls381 "github.com/consensys/gnark/crypto/commitment/pedersen/bls381"
+	pedersenbn256 "github.com/consensys/gnark/crypto/commitment/pedersen/bn256"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+)
+
+const nbGenerators = 4
+
+type commitCircuit struct {
+	Values         []frontend.Variable
+	Randomness     frontend.Variable
+	ExpectedResult struct {
+		X, Y frontend.Variable
+	} `gnark:",public"`
+}
+
+func (circuit *commitCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	pedersen, err := NewPedersen("seed", nbGenerators, curveID)
+	if err != nil {
+		return err
+	}
+	c := pedersen.Commit(cs, circuit.Randomness, circuit.Values...)
+	cs.AssertIsEqual(c.X, circuit.ExpectedResult.X)
+	cs.AssertIsEqual(c.Y, circuit.ExpectedResult.Y)
+	return nil
+}
+
+type hashCircuit struct {
+	Bits           []frontend.Variable
+	ExpectedResult struct {
+		X, Y frontend.Variable
+	} `gnark:",public"`
+}
+
+func (circuit *hashCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	pedersen, err := NewPedersen("seed", nbGenerators, curveID)
+	if err != nil {
+		return err
+	}
+	h := pedersen.Hash(cs, circuit.Bits...)
+	cs.AssertIsEqual(h.X, circuit.ExpectedResult.X)
+	cs.AssertIsEqual(h.Y, circuit.ExpectedResult.Y)
+	return nil
+}
+
+// nativeCommit and nativeHash compute the commitment and hash with the go implementation
+var nativeCommit = map[gurvy.ID]func(values []big.Int, randomness big.Int) (x, y big.Int){
+	gurvy.BN256: func(values []big.Int, randomness big.Int) (x, y big.Int) {
+		params := pedersenbn256.NewParams("seed", nbGenerators)
+		v := make([]fr_bn256.Element, len(values))
+		for i := 0; i < len(values); i++ {
+			v[i].SetBigInt(&values[i])
+		}
+		var r fr_bn256.Element
+		r.SetBigInt(&randomness)
+		c, err := params.Commit(v, r)
+		if err != nil {
+			panic(err)
+		}
+		c.X.ToBigIntRegular(&x)
+		c.Y.ToBigIntRegular(&y)
+		return
+	},
+	gurvy.BLS381: func(values []big.Int, randomness big.Int) (x, y big.Int) {
+		params := pedersenbls381.NewParams("seed", nbGenerators)
+		v := make([]fr_bls381.Element, len(values))
+		for i := 0; i < len(values); i++ {
+			v[i].SetBigInt(&values[i])
+		}
+		var r fr_bls381.Element
+		r.SetBigInt(&randomness)
+		c, err := params.Commit(v, r)
+		if err != nil {
+			panic(err)
+		}
+		c.X.ToBigIntRegular(&x)
+		c.Y.ToBigIntRegular(&y)
+		return
+	},
+}
+
+var nativeHash = map[gurvy.ID]func(bits []bool) (x, y big.Int){
+	gurvy.BN256: func(bits []bool) (x, y big.Int) {
+		params := pedersenbn256.NewParams("seed", nbGenerators)
+		h, err := params.Hash(bits)
+		if err != nil {
+			panic(err)
+		}
+		h.X.ToBigIntRegular(&x)
+		h.Y.ToBigIntRegular(&y)
+		return
+	},
+	gurvy.BLS381: func(bits []bool) (x, y big.Int) {
+		params := pedersenbls381.NewParams("seed", nbGenerators)
+		h, err := params.Hash(bits)
+		if err != nil {
+			panic(err)
+		}
+		h.X.ToBigIntRegular(&x)
+		h.Y.ToBigIntRegular(&y)
+		return
+	},
+}
+
+func TestCommit(t *testing.T) {
+
+	var randomness big.Int
+	randomness.SetString("7808462342289447506325013279997289618334122576263655295146895675168642919487", 10)
+
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381} {
+		for _, nbValues := range []int{1, 3} {
+			id, nbValues := id, nbValues
+			t.Run(fmt.Sprintf("%s/%d", id.String(), nbValues), func(t *testing.T) {
+				assert := groth16.NewAssert(t)
+
+				values := make([]big.Int, nbValues)
+				for i := 0; i < nbValues; i++ {
+					values[i].SetInt64(int64(42 + i))
+				}
+
+				circuit := commitCircuit{Values: make([]frontend.Variable, nbValues)}
+				r1cs, err := frontend.Compile(id, &circuit)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				x, y := nativeCommit[id](values, randomness)
+
+				witness := commitCircuit{Values: make([]frontend.Variable, nbValues)}
+				for i := 0; i < nbValues; i++ {
+					witness.Values[i].Assign(values[i])
+				}
+				witness.Randomness.Assign(randomness)
+				witness.ExpectedResult.X.Assign(x)
+				witness.ExpectedResult.Y.Assign(y)
+				assert.SolvingSucceeded(r1cs, &witness)
+
+				// another randomness opens another commitment
+				witness.Randomness = frontend.Variable{}
+				witness.Randomness.Assign(42)
+				assert.SolvingFailed(r1cs, &witness)
+			})
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381} {
+		// 7 bits: one segment and an incomplete window, 400 bits: 3 segments
+		for _, nbBits := range []int{7, 400} {
+			id, nbBits := id, nbBits
+			t.Run(fmt.Sprintf("%s/%d", id.String(), nbBits), func(t *testing.T) {
+				assert := groth16.NewAssert(t)
+
+				bits := make([]bool, nbBits)
+				for i := 0; i < nbBits; i++ {
+					bits[i] = (i*i+i/3)%5 < 2
+				}
+
+				circuit := hashCircuit{Bits: make([]frontend.Variable, nbBits)}
+				r1cs, err := frontend.Compile(id, &circuit)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				x, y := nativeHash[id](bits)
+
+				witness := hashCircuit{Bits: make([]frontend.Variable, nbBits)}
+				for i := 0; i < nbBits; i++ {
+					if bits[i] {
+						witness.Bits[i].Assign(1)
+					} else {
+						witness.Bits[i].Assign(0)
+					}
+				}
+				witness.ExpectedResult.X.Assign(x)
+				witness.ExpectedResult.Y.Assign(y)
+				assert.SolvingSucceeded(r1cs, &witness)
+
+				// flipping a bit changes the hash
+				witness.Bits[nbBits-1] = frontend.Variable{}
+				if bits[nbBits-1] {
+					witness.Bits[nbBits-1].Assign(0)
+				} else {
+					witness.Bits[nbBits-1].Assign(1)
+				}
+				assert.SolvingFailed(r1cs, &witness)
+			})
+		}
+	}
+}
@@ -17,9 +17,19 @@ limitations under the License.
 package groth16
 
 import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/frontend"
+	groth16_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16"
 	"github.com/consensys/gnark/std/algebra/fields"
 	"github.com/consensys/gnark/std/algebra/sw"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fp"
+	"github.com/consensys/gurvy/bls377/fr"
 )
 
 // Proof represents a groth16 proof in a r1cs
@@ -41,43 +51,174 @@ type VerifyingKey struct {
 
 	// [Kvk]1 (part of the verifying key yielding psi0, cf https://eprint.iacr.org/2020/278.pdf)
 	G1 []sw.G1Affine // The indexes correspond to the public wires
+
+	// PublicInputs names of the public wires of the inner circuit (cf groth16_bls377.VerifyingKey),
+	// G1[i] corresponding to PublicInputs[i]. If it is empty, G1[0] corresponds to the inner ONE_WIRE.
+	PublicInputs []string `gnark:"-"`
+
+	// constant verifying key (cf NewConstantVerifyingKey)
+	constant *groth16_bls377.VerifyingKey
 }
 
-// Verify implements the verification function of groth16.
-// pubInputNames should what r1cs.PublicInputs() outputs for the inner r1cs.
-// It creates public circuits input, corresponding to the pubInputNames slice.
-// Notations and naming are from https://eprint.iacr.org/2020/278.
-func Verify(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, innerVk VerifyingKey, innerProof Proof, innerPubInputs []frontend.Variable) {
+// seed of the mimc hash deriving the random coefficients of BatchVerify
+const batchSeed = "groth16"
+
+// number of bits of the random coefficients of BatchVerify
+const batchCoeffBitLen = 126
+
+// Assign a value to self (witness assignment)
+func (p *Proof) Assign(innerProof *groth16_bls377.Proof) {
+	p.Ar.Assign(&innerProof.Ar)
+	p.Krs.Assign(&innerProof.Krs)
+	p.Bs.Assign(&innerProof.Bs)
+}
+
+// Allocate sets the number of points of G1 and the public inputs of self from innerVk, so that
+// the verifying key can be part of a circuit to compile
+func (vk *VerifyingKey) Allocate(innerVk *groth16_bls377.VerifyingKey) {
+	vk.G1 = make([]sw.G1Affine, len(innerVk.G1.K))
+	vk.PublicInputs = append([]string{}, innerVk.PublicInputs...)
+}
+
+// Assign a value to self (witness assignment)
+func (vk *VerifyingKey) Assign(innerVk *groth16_bls377.VerifyingKey) {
+	vk.Allocate(innerVk)
+	vk.E.Assign(&innerVk.E)
+	vk.G2.GammaNeg.Assign(&innerVk.G2.GammaNeg)
+	vk.G2.DeltaNeg.Assign(&innerVk.G2.DeltaNeg)
+	for i := 0; i < len(innerVk.G1.K); i++ {
+		vk.G1[i].Assign(&innerVk.G1.K[i])
+	}
+}
+
+// NewConstantVerifyingKey returns innerVk as circuit constants, to be used instead of a witness
+// verifying key when the inner circuit is known at compile time.
+//
+// psi0 is then computed with precomputed tables of multiples of the points of G1 (cf sw.G1Proj.MultiScalarMulFixedBase),
+// and the verifying key is not hashed by BatchVerify.
+func NewConstantVerifyingKey(cs *frontend.ConstraintSystem, innerVk *groth16_bls377.VerifyingKey) VerifyingKey {
 
-	var eπCdelta, eπAπB, epsigamma fields.E12
+	var vk VerifyingKey
+	vk.constant = innerVk
+	vk.PublicInputs = append([]string{}, innerVk.PublicInputs...)
 
-	// e(-πC, -δ)
-	sw.MillerLoopAffine(cs, innerProof.Krs, innerVk.G2.DeltaNeg, &eπCdelta, pairingInfo)
+	e := [12]*fp.Element{
+		&innerVk.E.C0.B0.A0, &innerVk.E.C0.B0.A1, &innerVk.E.C0.B1.A0, &innerVk.E.C0.B1.A1, &innerVk.E.C0.B2.A0, &innerVk.E.C0.B2.A1,
+		&innerVk.E.C1.B0.A0, &innerVk.E.C1.B0.A1, &innerVk.E.C1.B1.A0, &innerVk.E.C1.B1.A1, &innerVk.E.C1.B2.A0, &innerVk.E.C1.B2.A1,
+	}
+	for i, v := range vk.e12Variables() {
+		*v = cs.Constant(*e[i])
+	}
+	vk.G2.GammaNeg = constantG2(cs, &innerVk.G2.GammaNeg)
+	vk.G2.DeltaNeg = constantG2(cs, &innerVk.G2.DeltaNeg)
+
+	vk.G1 = make([]sw.G1Affine, len(innerVk.G1.K))
+	for i := 0; i < len(innerVk.G1.K); i++ {
+		vk.G1[i].X = cs.Constant(innerVk.G1.K[i].X)
+		vk.G1[i].Y = cs.Constant(innerVk.G1.K[i].Y)
+	}
+
+	return vk
+}
+
+// constantG2 returns p as a circuit constant
+func constantG2(cs *frontend.ConstraintSystem, p *bls377.G2Affine) sw.G2Affine {
+	var res sw.G2Affine
+	res.X.A0 = cs.Constant(p.X.A0)
+	res.X.A1 = cs.Constant(p.X.A1)
+	res.Y.A0 = cs.Constant(p.Y.A0)
+	res.Y.A1 = cs.Constant(p.Y.A1)
+	return res
+}
 
-	// e(πA, πB)
-	sw.MillerLoopAffine(cs, innerProof.Ar, innerProof.Bs, &eπAπB, pairingInfo)
+// Verify implements the verification function of groth16.
+// innerPubInputs are the public inputs of the inner circuit, in the order of innerVk.PublicInputs
+// (the inner ONE_WIRE being omitted). Notations and naming are from https://eprint.iacr.org/2020/278.
+//
+// The points of the proof are witnesses, they are checked to be in G1 and G2 (about 10300 constraints).
+func Verify(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, innerVk VerifyingKey, innerProof Proof, innerPubInputs []frontend.Variable) {
+	BatchVerify(cs, pairingInfo, innerVk, []Proof{innerProof}, [][]frontend.Variable{innerPubInputs})
+}
 
-	// compute psi0 using a sequence of multiexponentiations
-	// TODO maybe implement the bucket method with c=1 when there's a large input set
-	var psi0, tmp sw.G1Affine
+// BatchVerify verifies several proofs of the same inner circuit, innerPubInputs[i] being the public
+// inputs of innerProofs[i] (cf Verify).
+//
+// With e_i = e(πA_i, πB_i) * e(-πC_i, -δ) * e(psi0_i, -γ), it checks that prod(e_i**r_i) = e(α, β), where
+// r_0 = 1 - sum(r_i) and the other r_i are random coefficients on 126 bits, derived from a hash of the proofs,
+// the public inputs and the verifying key (when it isn't constant). The r_i are applied to the points of G1,
+// so that there is a single miller loop (with len(innerProofs)+2 pairs) and a single final exponentiation.
+// Without the r_i, the public inputs could be shifted from a proof to another.
+//
+// With one public input, Verify costs ~81900C (~79600C with a constant verifying key), and each additional
+// proof ~62000C instead of ~81900C.
+func BatchVerify(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, innerVk VerifyingKey, innerProofs []Proof, innerPubInputs [][]frontend.Variable) {
 
-	// assign the initial psi0 to the part of the public key corresponding to one_wire
-	// TODO this assumes ONE_WIRE is at position 0
-	psi0.X = innerVk.G1[0].X
-	psi0.Y = innerVk.G1[0].Y
+	if len(innerProofs) == 0 || len(innerProofs) != len(innerPubInputs) {
+		panic("groth16: the number of proofs and public inputs must be equal and non zero")
+	}
 
-	for k, v := range innerPubInputs {
-		tmp.ScalarMul(cs, &innerVk.G1[k+1], v, 377)
-		psi0.AddAssign(cs, &tmp)
+	// the points of the proofs must be in the subgroups of order r
+	for _, proof := range innerProofs {
+		proof.Ar.MustBeOnCurve(cs)
+		proof.Ar.MustBeInSubgroup(cs)
+		proof.Krs.MustBeOnCurve(cs)
+		proof.Krs.MustBeInSubgroup(cs)
+		proof.Bs.MustBeOnCurve(cs, pairingInfo.Extension)
+		proof.Bs.MustBeInSubgroup(cs, pairingInfo.Extension)
 	}
 
-	// e(psi0, -gamma)
-	sw.MillerLoopAffine(cs, psi0, innerVk.G2.GammaNeg, &epsigamma, pairingInfo)
+	psi0 := make([]sw.G1Affine, len(innerProofs))
+	for i := range innerProofs {
+		psi0[i] = innerVk.psi0(cs, innerPubInputs[i])
+	}
 
-	// combine the results before performing the final expo
 	var preFinalExpo fields.E12
-	preFinalExpo.Mul(cs, &eπCdelta, &eπAπB, pairingInfo.Extension).
-		Mul(cs, &preFinalExpo, &epsigamma, pairingInfo.Extension)
+
+	if len(innerProofs) == 1 {
+		// e(-πC, -δ) * e(πA, πB) * e(psi0, -gamma), the miller loops sharing their squarings
+		sw.MillerLoopAffineMulti(cs,
+			[]sw.G1Affine{innerProofs[0].Krs, innerProofs[0].Ar, psi0[0]},
+			[]sw.G2Affine{innerVk.G2.DeltaNeg, innerProofs[0].Bs, innerVk.G2.GammaNeg},
+			&preFinalExpo, pairingInfo)
+	} else {
+		r := innerVk.batchCoefficients(cs, innerProofs, innerPubInputs)
+
+		// r_0 = -c, where c = sum(r_i) - 1 is on (126 + log(len(innerProofs))) bits
+		nbBits := batchCoeffBitLen + bits.Len(uint(len(innerProofs)-1))
+		one := big.NewInt(1)
+		sum := cs.LinearExpression(cs.Term(cs.Constant(1), big.NewInt(-1)))
+		for _, ri := range r {
+			sum = append(sum, cs.Term(ri, one))
+		}
+		c := make([]frontend.Variable, len(r)+1)
+		c[0] = cs.Mul(sum, 1)
+		copy(c[1:], r)
+
+		// sum(r_i*(-πC_i)), sum(r_i*psi0_i), r_i*πA_i
+		krs := make([]sw.G1Affine, len(innerProofs))
+		ar := make([]sw.G1Affine, len(innerProofs))
+		for i := range innerProofs {
+			krs[i] = innerProofs[i].Krs
+			ar[i] = innerProofs[i].Ar
+		}
+		krs[0].Neg(cs, &krs[0])
+		ar[0].Neg(cs, &ar[0])
+		psi0[0].Neg(cs, &psi0[0])
+
+		var sumKrs, sumPsi0 sw.G1Affine
+		sumKrs.MultiScalarMul(cs, krs, c, nbBits)
+		sumPsi0.MultiScalarMul(cs, psi0, c, nbBits)
+
+		P := []sw.G1Affine{sumKrs, sumPsi0}
+		Q := []sw.G2Affine{innerVk.G2.DeltaNeg, innerVk.G2.GammaNeg}
+		for i := range innerProofs {
+			var a sw.G1Affine
+			a.ScalarMul(cs, &ar[i], c[i], nbBits)
+			P = append(P, a)
+			Q = append(Q, innerProofs[i].Bs)
+		}
+		sw.MillerLoopAffineMulti(cs, P, Q, &preFinalExpo, pairingInfo)
+	}
 
 	// performs the final expo
 	var resPairing fields.E12
@@ -87,3 +228,89 @@ func Verify(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, innerV
 	innerVk.E.MustBeEqual(cs, resPairing)
 
 }
+
+// psi0 returns sum(innerPubInputs[k]*G1[k]), the inner ONE_WIRE being 1 (cf Verify)
+//
+// The inner public inputs are elements of BLS377's Fr, the scalar multiplications are done on fr.Modulus().BitLen() bits.
+func (vk *VerifyingKey) psi0(cs *frontend.ConstraintSystem, innerPubInputs []frontend.Variable) sw.G1Affine {
+
+	// position of the inner ONE_WIRE
+	oneWire := 0
+	for i, name := range vk.PublicInputs {
+		if name == backend.OneWire {
+			oneWire = i
+		}
+	}
+	if len(innerPubInputs)+1 != len(vk.G1) {
+		panic("groth16: the number of public inputs doesn't match the verifying key")
+	}
+	if len(innerPubInputs) == 0 {
+		return vk.G1[oneWire]
+	}
+
+	var res, one sw.G1Proj
+	if vk.constant != nil {
+		points := make([]bls377.G1Affine, 0, len(innerPubInputs))
+		points = append(points, vk.constant.G1.K[:oneWire]...)
+		points = append(points, vk.constant.G1.K[oneWire+1:]...)
+		res.MultiScalarMulFixedBase(cs, points, innerPubInputs, fr.Modulus().BitLen())
+	} else {
+		points := make([]sw.G1Affine, 0, len(innerPubInputs))
+		points = append(points, vk.G1[:oneWire]...)
+		points = append(points, vk.G1[oneWire+1:]...)
+		res.MultiScalarMul(cs, points, innerPubInputs, fr.Modulus().BitLen())
+	}
+	res.AddAssign(cs, one.FromAffine(cs, &vk.G1[oneWire]))
+
+	var psi0 sw.G1Affine
+	return *psi0.FromProj(cs, &res)
+}
+
+// batchCoefficients returns the random coefficients r_1, ..., r_(len(innerProofs)-1) of BatchVerify:
+// r_i is made of the low 126 bits of mimc(rho, i), where rho is the hash of the proofs,
+// the public inputs and the verifying key (when it isn't constant)
+func (vk *VerifyingKey) batchCoefficients(cs *frontend.ConstraintSystem, innerProofs []Proof, innerPubInputs [][]frontend.Variable) []frontend.Variable {
+
+	h, err := mimc.NewMiMC(batchSeed, gurvy.BW761)
+	if err != nil {
+		panic(err)
+	}
+
+	var data []frontend.Variable
+	if vk.constant == nil {
+		for _, v := range vk.e12Variables() {
+			data = append(data, *v)
+		}
+		for _, q := range []sw.G2Affine{vk.G2.GammaNeg, vk.G2.DeltaNeg} {
+			data = append(data, q.X.A0, q.X.A1, q.Y.A0, q.Y.A1)
+		}
+		for _, p := range vk.G1 {
+			data = append(data, p.X, p.Y)
+		}
+	}
+	for i, proof := range innerProofs {
+		data = append(data,
+			proof.Ar.X, proof.Ar.Y,
+			proof.Krs.X, proof.Krs.Y,
+			proof.Bs.X.A0, proof.Bs.X.A1, proof.Bs.Y.A0, proof.Bs.Y.A1)
+		data = append(data, innerPubInputs[i]...)
+	}
+	rho := h.Hash(cs, data...)
+
+	res := make([]frontend.Variable, len(innerProofs)-1)
+	for i := range res {
+		b := cs.ToBinary(h.Hash(cs, rho, cs.Constant(i+1)), fp.Modulus().BitLen())
+		res[i] = cs.FromBinary(b[:batchCoeffBitLen]...)
+	}
+
+	return res
+}
+
+// e12Variables returns pointers to the coordinates of E
+func (vk *VerifyingKey) e12Variables() []*frontend.Variable {
+	e := &vk.E
+	return []*frontend.Variable{
+		&e.C0.B0.A0, &e.C0.B0.A1, &e.C0.B1.A0, &e.C0.B1.A1, &e.C0.B2.A0, &e.C0.B2.A1,
+		&e.C1.B0.A0, &e.C1.B0.A1, &e.C1.B1.A0, &e.C1.B1.A1, &e.C1.B2.A0, &e.C1.B2.A1,
+	}
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package groth16
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
+	mimc_bls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
 	"github.com/consensys/gnark/frontend"
 	backend_bls377 "github.com/consensys/gnark/internal/backend/bls377"
 	groth16_bls377 "github.com/consensys/gnark/internal/backend/bls377/groth16"
@@ -28,6 +30,7 @@ import (
 	"github.com/consensys/gnark/std/algebra/sw"
 	"github.com/consensys/gnark/std/hash/mimc"
 	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
 )
 
 //--------------------------------------------------------------------
@@ -87,6 +90,54 @@ func generateBls377InnerProof(t *testing.T, vk *groth16_bls377.VerifyingKey, pro
 	}
 }
 
+// generateBls377InnerProofs returns n proofs of the inner circuit, for distinct preimages,
+// and their public hashes
+func generateBls377InnerProofs(t *testing.T, vk *groth16_bls377.VerifyingKey, n int) ([]groth16_bls377.Proof, []big.Int) {
+
+	var circuit mimcCircuit
+	r1cs, err := frontend.Compile(gurvy.BLS377, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pk groth16_bls377.ProvingKey
+	groth16_bls377.Setup(r1cs.(*backend_bls377.R1CS), &pk, vk)
+
+	proofs := make([]groth16_bls377.Proof, n)
+	hashes := make([]big.Int, n)
+	for i := 0; i < n; i++ {
+		var data, hash fr_bls377.Element
+		data.SetString(preimage)
+		data.Add(&data, new(fr_bls377.Element).SetUint64(uint64(i)))
+		hash.SetBytes(mimc_bls377.Sum("seed", data.Bytes()))
+		hash.ToBigIntRegular(&hashes[i])
+
+		var witness mimcCircuit
+		witness.Data.Assign(data)
+		witness.Hash.Assign(hashes[i])
+		assignment, err := frontend.ParseWitness(&witness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof, err := groth16_bls377.Prove(r1cs.(*backend_bls377.R1CS), &pk, assignment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := groth16_bls377.Verify(proof, vk, assignment); err != nil {
+			t.Fatal(err)
+		}
+		proofs[i] = *proof
+	}
+
+	return proofs, hashes
+}
+
+func getPairingContext(cs *frontend.ConstraintSystem) sw.PairingContext {
+	var pairingInfo sw.PairingContext
+	pairingInfo.Extension = fields.GetBLS377ExtensionFp12(cs)
+	pairingInfo.AteLoop = 9586122913090633729
+	return pairingInfo
+}
+
 type verifierCircuit struct {
 	InnerProof Proof
 	InnerVk    VerifyingKey
@@ -160,6 +211,96 @@ func TestVerifier(t *testing.T) {
 
 }
 
+const nbBatchProofs = 3
+
+type batchVerifierCircuit struct {
+	InnerProofs [nbBatchProofs]Proof
+	InnerVk     VerifyingKey
+	Hashes      [nbBatchProofs]frontend.Variable `gnark:",public"`
+}
+
+func (circuit *batchVerifierCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	pubInputs := make([][]frontend.Variable, nbBatchProofs)
+	for i := range pubInputs {
+		pubInputs[i] = []frontend.Variable{circuit.Hashes[i]}
+	}
+	BatchVerify(cs, getPairingContext(cs), circuit.InnerVk, circuit.InnerProofs[:], pubInputs)
+	return nil
+}
+
+func TestBatchVerifier(t *testing.T) {
+
+	var innerVk groth16_bls377.VerifyingKey
+	innerProofs, hashes := generateBls377InnerProofs(t, &innerVk, nbBatchProofs)
+
+	var circuit batchVerifierCircuit
+	circuit.InnerVk.Allocate(&innerVk)
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	var witness batchVerifierCircuit
+	witness.InnerVk.Assign(&innerVk)
+	for i := 0; i < nbBatchProofs; i++ {
+		witness.InnerProofs[i].Assign(&innerProofs[i])
+		witness.Hashes[i].Assign(hashes[i])
+	}
+	assert.SolvingSucceeded(r1cs.(*backend_bw761.R1CS), &witness)
+
+	// swapping the public inputs of two proofs keeps their sum, the batch must still fail
+	var wrongWitness batchVerifierCircuit
+	wrongWitness.InnerVk.Assign(&innerVk)
+	hashes[0], hashes[1] = hashes[1], hashes[0]
+	for i := 0; i < nbBatchProofs; i++ {
+		wrongWitness.InnerProofs[i].Assign(&innerProofs[i])
+		wrongWitness.Hashes[i].Assign(hashes[i])
+	}
+	assert.SolvingFailed(r1cs.(*backend_bw761.R1CS), &wrongWitness)
+}
+
+type constantVkVerifierCircuit struct {
+	InnerProofs [2]Proof
+	Hashes      [2]frontend.Variable         `gnark:",public"`
+	innerVk     *groth16_bls377.VerifyingKey // known at compile time
+}
+
+func (circuit *constantVkVerifierCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	vk := NewConstantVerifyingKey(cs, circuit.innerVk)
+	pairingInfo := getPairingContext(cs)
+	BatchVerify(cs, pairingInfo, vk, circuit.InnerProofs[:], [][]frontend.Variable{{circuit.Hashes[0]}, {circuit.Hashes[1]}})
+	Verify(cs, pairingInfo, vk, circuit.InnerProofs[0], []frontend.Variable{circuit.Hashes[0]})
+	return nil
+}
+
+func TestVerifierConstantVk(t *testing.T) {
+
+	var innerVk groth16_bls377.VerifyingKey
+	innerProofs, hashes := generateBls377InnerProofs(t, &innerVk, 2)
+
+	circuit := constantVkVerifierCircuit{innerVk: &innerVk}
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert := groth16.NewAssert(t)
+
+	var witness, wrongWitness constantVkVerifierCircuit
+	for i := 0; i < 2; i++ {
+		witness.InnerProofs[i].Assign(&innerProofs[i])
+		witness.Hashes[i].Assign(hashes[i])
+		wrongWitness.InnerProofs[i].Assign(&innerProofs[i])
+	}
+	assert.SolvingSucceeded(r1cs.(*backend_bw761.R1CS), &witness)
+
+	wrongWitness.Hashes[0].Assign(hashes[0])
+	wrongWitness.Hashes[1].Assign(hashes[0])
+	assert.SolvingFailed(r1cs.(*backend_bw761.R1CS), &wrongWitness)
+}
+
 //--------------------------------------------------------------------
 // bench
 
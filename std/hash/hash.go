@@ -0,0 +1,28 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hash defines the interface implemented by the hash functions of std/hash,
+// so that the gadgets using a hash function (accumulators, ...) can be used with any of them.
+package hash
+
+import "github.com/consensys/gnark/frontend"
+
+// Hash hash function in a circuit, for example mimc.MiMC or poseidon.Poseidon
+type Hash interface {
+	// Hash returns the hash (in r1cs form) of data, consistent with the Write and Sum
+	// methods of the hash.Hash implementations of crypto/hash, the data being field elements
+	Hash(cs *frontend.ConstraintSystem, data ...frontend.Variable) frontend.Variable
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/consensys/gnark/crypto/hash/mimc/bls377"
 	"github.com/consensys/gnark/crypto/hash/mimc/bls381"
 	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/crypto/hash/mimc/bw761"
 
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gurvy"
@@ -35,16 +36,31 @@ func init() {
 	encryptFuncs[gurvy.BN256] = encryptBN256
 	encryptFuncs[gurvy.BLS381] = encryptBLS381
 	encryptFuncs[gurvy.BLS377] = encryptBLS377
+	encryptFuncs[gurvy.BW761] = encryptBW761
 
 	newMimc = make(map[gurvy.ID]func(string) MiMC)
 	newMimc[gurvy.BN256] = newMimcBN256
 	newMimc[gurvy.BLS381] = newMimcBLS381
 	newMimc[gurvy.BLS377] = newMimcBLS377
+	newMimc[gurvy.BW761] = newMimcBW761
 }
 
 // -------------------------------------------------------------------------------------------------
 // constructors
 
+func newMimcBW761(seed string) MiMC {
+	res := MiMC{}
+	params := bw761.NewParams(seed)
+	for _, v := range params {
+		var cpy big.Int
+		v.ToBigIntRegular(&cpy)
+		res.params = append(res.params, cpy)
+	}
+	res.id = gurvy.BW761
+	res.seed = seed
+	return res
+}
+
 func newMimcBLS377(seed string) MiMC {
 	res := MiMC{}
 	params := bls377.NewParams(seed)
@@ -54,6 +70,7 @@ func newMimcBLS377(seed string) MiMC {
 		res.params = append(res.params, cpy)
 	}
 	res.id = gurvy.BLS377
+	res.seed = seed
 	return res
 }
 
@@ -66,6 +83,7 @@ func newMimcBLS381(seed string) MiMC {
 		res.params = append(res.params, cpy)
 	}
 	res.id = gurvy.BLS381
+	res.seed = seed
 	return res
 }
 
@@ -78,6 +96,7 @@ func newMimcBN256(seed string) MiMC {
 		res.params = append(res.params, cpy)
 	}
 	res.id = gurvy.BN256
+	res.seed = seed
 	return res
 }
 
@@ -132,3 +151,20 @@ func encryptBLS377(cs *frontend.ConstraintSystem, h MiMC, message frontend.Varia
 	return res
 
 }
+
+// encryptBW761 of a mimc run expressed as r1cs
+func encryptBW761(cs *frontend.ConstraintSystem, h MiMC, message frontend.Variable, key frontend.Variable) frontend.Variable {
+
+	res := message
+
+	for i := 0; i < len(h.params); i++ {
+		tmp := cs.Add(res, key, h.params[i])
+		// res = (res+k+c)^5
+		res = cs.Mul(tmp, tmp) // square
+		res = cs.Mul(res, res) // square
+		res = cs.Mul(res, tmp) // mul
+	}
+	res = cs.Add(res, key)
+	return res
+
+}
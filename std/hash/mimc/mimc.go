@@ -29,6 +29,7 @@ import (
 type MiMC struct {
 	params []big.Int
 	id     gurvy.ID
+	seed   string
 }
 
 // NewMiMC returns a MiMC instance, than can be used in a gnark circuit
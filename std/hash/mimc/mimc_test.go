@@ -17,6 +17,8 @@ limitations under the License.
 package mimc
 
 import (
+	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
@@ -27,10 +29,12 @@ import (
 	mimcbls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
 	mimcbls381 "github.com/consensys/gnark/crypto/hash/mimc/bls381"
 	mimcbn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	mimcbw761 "github.com/consensys/gnark/crypto/hash/mimc/bw761"
 
 	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
 	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
 	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
 )
 
 type mimcCircuit struct {
@@ -124,3 +128,153 @@ func TestMimcBLS377(t *testing.T) {
 	assert.SolvingSucceeded(r1cs, &witness)
 
 }
+
+func TestMimcBW761(t *testing.T) {
+
+	assert := groth16.NewAssert(t)
+
+	// input
+	var data fr_bw761.Element
+	data.SetString("7808462342289447506325013279997289618334122576263655295146895675168642919487")
+
+	// minimal cs res = hash(data)
+	var circuit, witness mimcCircuit
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// running MiMC (Go)
+	b := mimcbw761.Sum("seed", data.Bytes())
+	var tmp fr_bw761.Element
+	tmp.SetBytes(b)
+	witness.Data.Assign(data)
+	witness.ExpectedResult.Assign(tmp)
+
+	assert.SolvingSucceeded(r1cs, &witness)
+
+}
+
+type spongeCircuit struct {
+	Data            []frontend.Variable `gnark:"data,public"`
+	ExpectedOutputs []frontend.Variable
+	rate            int
+}
+
+func (circuit *spongeCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	mimc, err := NewMiMC("seed", curveID)
+	if err != nil {
+		return err
+	}
+	outputs := mimc.Sponge(cs, circuit.rate, len(circuit.ExpectedOutputs), circuit.Data...)
+	for i := 0; i < len(outputs); i++ {
+		cs.AssertIsEqual(outputs[i], circuit.ExpectedOutputs[i])
+	}
+	return nil
+}
+
+// nativeSponge runs the go implementation of the sponge
+var nativeSponge = map[gurvy.ID]func(rate, nbOutputs int, data []big.Int) []big.Int{
+	gurvy.BN256: func(rate, nbOutputs int, data []big.Int) []big.Int {
+		inputs := make([]fr_bn256.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		outputs := mimcbn256.Sponge("seed", rate, nbOutputs, inputs...)
+		res := make([]big.Int, len(outputs))
+		for i := 0; i < len(outputs); i++ {
+			outputs[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	},
+	gurvy.BLS381: func(rate, nbOutputs int, data []big.Int) []big.Int {
+		inputs := make([]fr_bls381.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		outputs := mimcbls381.Sponge("seed", rate, nbOutputs, inputs...)
+		res := make([]big.Int, len(outputs))
+		for i := 0; i < len(outputs); i++ {
+			outputs[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	},
+	gurvy.BLS377: func(rate, nbOutputs int, data []big.Int) []big.Int {
+		inputs := make([]fr_bls377.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		outputs := mimcbls377.Sponge("seed", rate, nbOutputs, inputs...)
+		res := make([]big.Int, len(outputs))
+		for i := 0; i < len(outputs); i++ {
+			outputs[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	},
+	gurvy.BW761: func(rate, nbOutputs int, data []big.Int) []big.Int {
+		inputs := make([]fr_bw761.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		outputs := mimcbw761.Sponge("seed", rate, nbOutputs, inputs...)
+		res := make([]big.Int, len(outputs))
+		for i := 0; i < len(outputs); i++ {
+			outputs[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	},
+}
+
+func TestSponge(t *testing.T) {
+
+	var start big.Int
+	start.SetString("7808462342289447506325013279997289618334122576263655295146895675168642919487", 10)
+
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		for _, rate := range []int{1, 2} {
+			for _, nbOutputs := range []int{1, 3} {
+				id, rate, nbOutputs := id, rate, nbOutputs
+				t.Run(fmt.Sprintf("%s/rate=%d/outputs=%d", id.String(), rate, nbOutputs), func(t *testing.T) {
+					assert := groth16.NewAssert(t)
+
+					const nbInputs = 3
+					data := make([]big.Int, nbInputs)
+					for i := 0; i < nbInputs; i++ {
+						data[i].Add(&start, big.NewInt(int64(i)))
+					}
+
+					circuit := spongeCircuit{
+						Data:            make([]frontend.Variable, nbInputs),
+						ExpectedOutputs: make([]frontend.Variable, nbOutputs),
+						rate:            rate,
+					}
+					r1cs, err := frontend.Compile(id, &circuit)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					// running the sponge (Go)
+					expected := nativeSponge[id](rate, nbOutputs, data)
+
+					witness := spongeCircuit{
+						Data:            make([]frontend.Variable, nbInputs),
+						ExpectedOutputs: make([]frontend.Variable, nbOutputs),
+					}
+					for i := 0; i < nbInputs; i++ {
+						witness.Data[i].Assign(data[i])
+					}
+					for i := 0; i < nbOutputs; i++ {
+						witness.ExpectedOutputs[i].Assign(expected[i])
+					}
+					assert.SolvingSucceeded(r1cs, &witness)
+
+					// a wrong last output must be rejected
+					expected[nbOutputs-1].Add(&expected[nbOutputs-1], big.NewInt(1))
+					witness.ExpectedOutputs[nbOutputs-1] = frontend.Variable{}
+					witness.ExpectedOutputs[nbOutputs-1].Assign(expected[nbOutputs-1])
+					assert.SolvingFailed(r1cs, &witness)
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mimc
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gnark/crypto/hash/mimc/bls377"
+	"github.com/consensys/gnark/crypto/hash/mimc/bls381"
+	"github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	"github.com/consensys/gnark/crypto/hash/mimc/bw761"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+var sBoxFuncs map[gurvy.ID]func(*frontend.ConstraintSystem, r1c.LinearExpression) frontend.Variable
+var newSpongeParams map[gurvy.ID]func(string, int) []big.Int
+
+var bOne = big.NewInt(1)
+
+func init() {
+	sBoxFuncs = make(map[gurvy.ID]func(*frontend.ConstraintSystem, r1c.LinearExpression) frontend.Variable)
+	sBoxFuncs[gurvy.BN256] = sBoxBN256
+	sBoxFuncs[gurvy.BLS381] = sBoxPow5
+	sBoxFuncs[gurvy.BLS377] = sBoxBLS377
+	sBoxFuncs[gurvy.BW761] = sBoxPow5
+
+	newSpongeParams = make(map[gurvy.ID]func(string, int) []big.Int)
+	newSpongeParams[gurvy.BN256] = func(seed string, rate int) []big.Int {
+		params := bn256.NewSpongeParams(seed, rate)
+		res := make([]big.Int, len(params))
+		for i := 0; i < len(params); i++ {
+			params[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	}
+	newSpongeParams[gurvy.BLS381] = func(seed string, rate int) []big.Int {
+		params := bls381.NewSpongeParams(seed, rate)
+		res := make([]big.Int, len(params))
+		for i := 0; i < len(params); i++ {
+			params[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	}
+	newSpongeParams[gurvy.BLS377] = func(seed string, rate int) []big.Int {
+		params := bls377.NewSpongeParams(seed, rate)
+		res := make([]big.Int, len(params))
+		for i := 0; i < len(params); i++ {
+			params[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	}
+	newSpongeParams[gurvy.BW761] = func(seed string, rate int) []big.Int {
+		params := bw761.NewSpongeParams(seed, rate)
+		res := make([]big.Int, len(params))
+		for i := 0; i < len(params); i++ {
+			params[i].ToBigIntRegular(&res[i])
+		}
+		return res
+	}
+}
+
+// Sponge absorbs data in a sponge of the given rate, and squeezes nbOutputs variables from it,
+// expressed as r1cs (cf crypto/hash/mimc/* Sponge).
+//
+// The state has rate+1 elements, the last one being the capacity. The data is padded with a one
+// followed by zeros up to a multiple of the rate. The permutation is a generalized Feistel network
+// (GMiMC, expanding round function) on top of the mimc S-box.
+func (h MiMC) Sponge(cs *frontend.ConstraintSystem, rate, nbOutputs int, data ...frontend.Variable) []frontend.Variable {
+
+	params := newSpongeParams[h.id](h.seed, rate)
+	sBox := sBoxFuncs[h.id]
+	one := cs.Constant(1)
+
+	// the elements of the state are kept as linear expressions, so that adding the output
+	// of the round function to the branches is free
+	state := make([]r1c.LinearExpression, rate+1)
+	for i := 0; i < len(state); i++ {
+		state[i] = cs.LinearExpression(cs.Term(one, big.NewInt(0)))
+	}
+
+	permute := func() {
+		for i := 0; i < len(params); i++ {
+			if len(state[0]) > 1 {
+				state[0] = cs.LinearExpression(cs.Term(cs.Mul(state[0], 1), bOne))
+			}
+			in := make(r1c.LinearExpression, len(state[0]), len(state[0])+1)
+			copy(in, state[0])
+			in = append(in, cs.Term(one, &params[i]))
+
+			f := sBox(cs, in)
+			for j := 1; j < len(state); j++ {
+				state[j] = append(state[j], cs.Term(f, bOne))
+			}
+
+			x0 := state[0]
+			copy(state, state[1:])
+			state[len(state)-1] = x0
+		}
+	}
+
+	// absorb, the padding zeros being omitted
+	padded := make([]frontend.Variable, len(data), len(data)+1)
+	copy(padded, data)
+	padded = append(padded, one)
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate && i+j < len(padded); j++ {
+			state[j] = append(state[j], cs.Term(padded[i+j], bOne))
+		}
+		permute()
+	}
+
+	// squeeze
+	res := make([]frontend.Variable, 0, nbOutputs)
+	for {
+		for j := 0; j < rate && len(res) < nbOutputs; j++ {
+			res = append(res, cs.Mul(state[j], 1))
+		}
+		if len(res) == nbOutputs {
+			return res
+		}
+		permute()
+	}
+}
+
+// -------------------------------------------------------------------------------------------------
+// S-boxes of the mimc encryptions, taking a linear expression as input
+
+// sBoxBN256 x -> x^7
+func sBoxBN256(cs *frontend.ConstraintSystem, x r1c.LinearExpression) frontend.Variable {
+	res := cs.Mul(x, x)
+	res = cs.Mul(res, x)
+	res = cs.Mul(res, res)
+	return cs.Mul(res, x)
+}
+
+// sBoxPow5 x -> x^5
+func sBoxPow5(cs *frontend.ConstraintSystem, x r1c.LinearExpression) frontend.Variable {
+	res := cs.Mul(x, x)
+	res = cs.Mul(res, res)
+	return cs.Mul(res, x)
+}
+
+// sBoxBLS377 x -> x^-1
+func sBoxBLS377(cs *frontend.ConstraintSystem, x r1c.LinearExpression) frontend.Variable {
+	return cs.Div(1, x)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/consensys/gnark/crypto/hash/poseidon/bls377"
+	"github.com/consensys/gnark/crypto/hash/poseidon/bls381"
+	"github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	"github.com/consensys/gnark/crypto/hash/poseidon/bw761"
+
+	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+// spongeWidth size of the state in sponge mode, same for all curves
+const spongeWidth = bn256.SpongeWidth
+
+var bOne = big.NewInt(1)
+
+// newParams returns the parameters of crypto/hash/poseidon for a seed and a width, for each curve
+var newParams = map[gurvy.ID]func(string, int) params{
+	gurvy.BN256: func(seed string, width int) params {
+		p := bn256.NewParams(seed, width)
+		return newParamsFrom(p.Width, p.NbFullRounds, p.NbPartialRounds, bn256.Alpha, fr_bn256.Modulus(), p.RoundKeys, p.MDS)
+	},
+	gurvy.BLS381: func(seed string, width int) params {
+		p := bls381.NewParams(seed, width)
+		return newParamsFrom(p.Width, p.NbFullRounds, p.NbPartialRounds, bls381.Alpha, fr_bls381.Modulus(), p.RoundKeys, p.MDS)
+	},
+	gurvy.BLS377: func(seed string, width int) params {
+		p := bls377.NewParams(seed, width)
+		return newParamsFrom(p.Width, p.NbFullRounds, p.NbPartialRounds, bls377.Alpha, fr_bls377.Modulus(), p.RoundKeys, p.MDS)
+	},
+	gurvy.BW761: func(seed string, width int) params {
+		p := bw761.NewParams(seed, width)
+		return newParamsFrom(p.Width, p.NbFullRounds, p.NbPartialRounds, bw761.Alpha, fr_bw761.Modulus(), p.RoundKeys, p.MDS)
+	},
+}
+
+// newParamsFrom converts the parameters of a native implementation, roundKeys and mds
+// being [][]fr.Element of its curve
+func newParamsFrom(width, nbFullRounds, nbPartialRounds int, alpha uint64, modulus *big.Int, roundKeys, mds interface{}) params {
+	res := params{
+		width:           width,
+		nbFullRounds:    nbFullRounds,
+		nbPartialRounds: nbPartialRounds,
+		alpha:           alpha,
+		roundKeys:       toBigInt(roundKeys),
+		mds:             toBigInt(mds),
+	}
+	res.modulus.Set(modulus)
+	return res
+}
+
+// element field element of gurvy
+type element interface {
+	ToBigIntRegular(res *big.Int) *big.Int
+}
+
+// toBigInt returns the regular form of a [][]fr.Element, whatever the curve
+func toBigInt(matrix interface{}) [][]big.Int {
+	m := reflect.ValueOf(matrix)
+	res := make([][]big.Int, m.Len())
+	for i := 0; i < len(res); i++ {
+		row := m.Index(i)
+		res[i] = make([]big.Int, row.Len())
+		for j := 0; j < len(res[i]); j++ {
+			row.Index(j).Addr().Interface().(element).ToBigIntRegular(&res[i][j])
+		}
+	}
+	return res
+}
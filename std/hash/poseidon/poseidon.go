@@ -0,0 +1,166 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	"github.com/consensys/gnark/frontend"
+
+	"github.com/consensys/gurvy"
+)
+
+// Poseidon contains the seed of the Poseidon hash func and the curve on which it is implemented.
+// The parameters of the permutation are derived from the seed exactly as in crypto/hash/poseidon.
+type Poseidon struct {
+	seed   string
+	sponge params // params used in sponge mode
+	id     gurvy.ID
+}
+
+// params constants of the poseidon permutation on a state of size width
+type params struct {
+	width           int
+	nbFullRounds    int
+	nbPartialRounds int
+	alpha           uint64
+	roundKeys       [][]big.Int
+	mds             [][]big.Int
+	modulus         big.Int
+}
+
+// NewPoseidon returns a Poseidon instance, than can be used in a gnark circuit
+func NewPoseidon(seed string, id gurvy.ID) (Poseidon, error) {
+	if constructor, ok := newParams[id]; ok {
+		return Poseidon{
+			seed:   seed,
+			sponge: constructor(seed, spongeWidth),
+			id:     id,
+		}, nil
+	}
+	return Poseidon{}, errors.New("unknown curve id")
+}
+
+// Hash hash (in r1cs form) an arbitrary number of variables in sponge mode.
+// The data is padded with a one followed by zeros up to a multiple of the rate,
+// the result is the first element of the rate (cf crypto/hash/poseidon HashSponge and Sum)
+func (h Poseidon) Hash(cs *frontend.ConstraintSystem, data ...frontend.Variable) frontend.Variable {
+
+	rate := h.sponge.width - 1
+
+	padded := make([]interface{}, 0, len(data)+rate)
+	for _, v := range data {
+		padded = append(padded, v)
+	}
+	padded = append(padded, 1)
+	for len(padded)%rate != 0 {
+		padded = append(padded, 0)
+	}
+
+	state := make([]frontend.Variable, h.sponge.width)
+	for i := 0; i < h.sponge.width; i++ {
+		state[i] = cs.Constant(0)
+	}
+
+	for i := 0; i < len(padded); i += rate {
+		for j := 0; j < rate; j++ {
+			state[j+1] = cs.Add(state[j+1], padded[i+j])
+		}
+		state = h.sponge.permute(cs, state)
+	}
+
+	return state[1]
+}
+
+// HashFixed hash (in r1cs form) exactly len(data) variables with a single permutation
+// on a state of size len(data)+1, the capacity element being set to len(data)
+// (cf crypto/hash/poseidon HashFixed)
+func (h Poseidon) HashFixed(cs *frontend.ConstraintSystem, data ...frontend.Variable) frontend.Variable {
+
+	p := newParams[h.id](h.seed, len(data)+1)
+
+	state := make([]frontend.Variable, 0, len(data)+1)
+	state = append(state, cs.Constant(len(data)))
+	state = append(state, data...)
+
+	state = p.permute(cs, state)
+
+	return state[1]
+}
+
+// permute returns the poseidon permutation of state, expressed as r1cs.
+//
+// The round keys are folded in the linear expressions fed to the S-boxes, and the mixing layer
+// costs one constraint per element of the state: a full round costs width*(cost of the S-box + 1)
+// constraints, a partial round costs (cost of the S-box) + width constraints.
+func (p params) permute(cs *frontend.ConstraintSystem, state []frontend.Variable) []frontend.Variable {
+
+	one := cs.Constant(1)
+	nbRounds := p.nbFullRounds + p.nbPartialRounds
+
+	sboxed := make([]bool, p.width)
+	for i := 0; i < nbRounds; i++ {
+
+		fullRound := i < p.nbFullRounds/2 || i >= p.nbFullRounds/2+p.nbPartialRounds
+
+		// add round keys and S-box: when the S-box is skipped, state[j]+roundKeys[i][j] is
+		// kept as is and folded in the mixing layer
+		for j := 0; j < p.width; j++ {
+			sboxed[j] = fullRound || j == 0
+			if sboxed[j] {
+				x := cs.LinearExpression(cs.Term(state[j], bOne), cs.Term(one, &p.roundKeys[i][j]))
+				state[j] = p.sBox(cs, x)
+			}
+		}
+
+		// mix
+		res := make([]frontend.Variable, p.width)
+		for j := 0; j < p.width; j++ {
+			var constant, tmp big.Int
+			terms := make([]r1c.Term, 0, p.width+1)
+			for k := 0; k < p.width; k++ {
+				terms = append(terms, cs.Term(state[k], &p.mds[j][k]))
+				if !sboxed[k] {
+					tmp.Mul(&p.mds[j][k], &p.roundKeys[i][k])
+					constant.Add(&constant, &tmp)
+				}
+			}
+			constant.Mod(&constant, &p.modulus)
+			terms = append(terms, cs.Term(one, &constant))
+			res[j] = cs.Mul(cs.LinearExpression(terms...), 1)
+		}
+		state = res
+	}
+
+	return state
+}
+
+// sBox returns x^alpha using square and multiply
+func (p params) sBox(cs *frontend.ConstraintSystem, x r1c.LinearExpression) frontend.Variable {
+	var res interface{}
+	res = x
+	for i := bits.Len64(p.alpha) - 2; i >= 0; i-- {
+		res = cs.Mul(res, res)
+		if (p.alpha>>uint(i))&1 == 1 {
+			res = cs.Mul(res, x)
+		}
+	}
+	return res.(frontend.Variable)
+}
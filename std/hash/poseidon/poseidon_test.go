@@ -0,0 +1,182 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poseidon
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+
+	poseidonbls377 "github.com/consensys/gnark/crypto/hash/poseidon/bls377"
+	poseidonbls381 "github.com/consensys/gnark/crypto/hash/poseidon/bls381"
+	poseidonbn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	poseidonbw761 "github.com/consensys/gnark/crypto/hash/poseidon/bw761"
+
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+type poseidonCircuit struct {
+	Data           []frontend.Variable `gnark:"data,public"`
+	ExpectedResult frontend.Variable
+	fixed          bool
+}
+
+func (circuit *poseidonCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	poseidon, err := NewPoseidon("seed", curveID)
+	if err != nil {
+		return err
+	}
+	var result frontend.Variable
+	if circuit.fixed {
+		result = poseidon.HashFixed(cs, circuit.Data...)
+	} else {
+		result = poseidon.Hash(cs, circuit.Data...)
+	}
+	cs.AssertIsEqual(result, circuit.ExpectedResult)
+	return nil
+}
+
+// native computes the hash of data with the go implementation, in sponge mode
+// through the hash.Hash interface or in fixed arity mode
+var native = map[gurvy.ID]func(fixed bool, data []big.Int) big.Int{
+	gurvy.BN256: func(fixed bool, data []big.Int) (res big.Int) {
+		inputs := make([]fr_bn256.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		var h fr_bn256.Element
+		if fixed {
+			h = poseidonbn256.HashFixed("seed", inputs...)
+		} else {
+			hFunc := poseidonbn256.NewPoseidon("seed")
+			for i := 0; i < len(inputs); i++ {
+				hFunc.Write(inputs[i].Bytes())
+			}
+			h.SetBytes(hFunc.Sum(nil))
+		}
+		h.ToBigIntRegular(&res)
+		return
+	},
+	gurvy.BLS381: func(fixed bool, data []big.Int) (res big.Int) {
+		inputs := make([]fr_bls381.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		var h fr_bls381.Element
+		if fixed {
+			h = poseidonbls381.HashFixed("seed", inputs...)
+		} else {
+			hFunc := poseidonbls381.NewPoseidon("seed")
+			for i := 0; i < len(inputs); i++ {
+				hFunc.Write(inputs[i].Bytes())
+			}
+			h.SetBytes(hFunc.Sum(nil))
+		}
+		h.ToBigIntRegular(&res)
+		return
+	},
+	gurvy.BLS377: func(fixed bool, data []big.Int) (res big.Int) {
+		inputs := make([]fr_bls377.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		var h fr_bls377.Element
+		if fixed {
+			h = poseidonbls377.HashFixed("seed", inputs...)
+		} else {
+			hFunc := poseidonbls377.NewPoseidon("seed")
+			for i := 0; i < len(inputs); i++ {
+				hFunc.Write(inputs[i].Bytes())
+			}
+			h.SetBytes(hFunc.Sum(nil))
+		}
+		h.ToBigIntRegular(&res)
+		return
+	},
+	gurvy.BW761: func(fixed bool, data []big.Int) (res big.Int) {
+		inputs := make([]fr_bw761.Element, len(data))
+		for i := 0; i < len(data); i++ {
+			inputs[i].SetBigInt(&data[i])
+		}
+		var h fr_bw761.Element
+		if fixed {
+			h = poseidonbw761.HashFixed("seed", inputs...)
+		} else {
+			hFunc := poseidonbw761.NewPoseidon("seed")
+			for i := 0; i < len(inputs); i++ {
+				hFunc.Write(inputs[i].Bytes())
+			}
+			h.SetBytes(hFunc.Sum(nil))
+		}
+		h.ToBigIntRegular(&res)
+		return
+	},
+}
+
+func TestPoseidon(t *testing.T) {
+
+	// input
+	var start big.Int
+	start.SetString("7808462342289447506325013279997289618334122576263655295146895675168642919487", 10)
+
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		for _, fixed := range []bool{false, true} {
+			for _, nbInputs := range []int{1, 2, 3, 4} {
+				id, fixed, nbInputs := id, fixed, nbInputs
+				t.Run(fmt.Sprintf("%s/fixed=%t/%d", id.String(), fixed, nbInputs), func(t *testing.T) {
+					assert := groth16.NewAssert(t)
+
+					data := make([]big.Int, nbInputs)
+					for i := 0; i < nbInputs; i++ {
+						data[i].Add(&start, big.NewInt(int64(i)))
+					}
+
+					// minimal cs res = hash(data)
+					circuit := poseidonCircuit{Data: make([]frontend.Variable, nbInputs), fixed: fixed}
+					r1cs, err := frontend.Compile(id, &circuit)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					// running Poseidon (Go)
+					expected := native[id](fixed, data)
+
+					witness := poseidonCircuit{Data: make([]frontend.Variable, nbInputs)}
+					for i := 0; i < nbInputs; i++ {
+						witness.Data[i].Assign(data[i])
+					}
+					witness.ExpectedResult.Assign(expected)
+
+					assert.SolvingSucceeded(r1cs, &witness)
+
+					// a wrong digest must be rejected
+					expected.Add(&expected, big.NewInt(1))
+					witness.ExpectedResult = frontend.Variable{}
+					witness.ExpectedResult.Assign(expected)
+					assert.SolvingFailed(r1cs, &witness)
+				})
+			}
+		}
+	}
+}
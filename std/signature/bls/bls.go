@@ -0,0 +1,220 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bls verifies BLS377 signatures (cf crypto/signature/bls/bls377) in a BW761 circuit
+package bls
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/backend/r1cs/r1c"
+	bls_bls377 "github.com/consensys/gnark/crypto/signature/bls/bls377"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields"
+	"github.com/consensys/gnark/std/algebra/sw"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls377"
+	"github.com/consensys/gurvy/bls377/fp"
+)
+
+// PublicKey stores a BLS public key (to be used in gnark circuit)
+type PublicKey struct {
+	A sw.G2Affine
+}
+
+// Signature stores a BLS signature (to be used in gnark circuit)
+type Signature struct {
+	S sw.G1Affine
+}
+
+// HashHint stores the witness of the hash to G1 of a message (cf crypto/signature/bls/bls377 HashHint)
+type HashHint struct {
+	Flags, Roots [bls_bls377.NbTries]frontend.Variable
+}
+
+// Assign a value to self (witness assignment)
+func (p *PublicKey) Assign(pub *bls_bls377.PublicKey) {
+	p.A.Assign(&pub.A)
+}
+
+// Assign a value to self (witness assignment)
+func (s *Signature) Assign(sig *bls_bls377.Signature) {
+	s.S.Assign(&sig.S)
+}
+
+// Assign a value to self (witness assignment)
+func (h *HashHint) Assign(hint *bls_bls377.HashHint) {
+	for i := 0; i < bls_bls377.NbTries; i++ {
+		if hint.Flags[i] {
+			h.Flags[i].Assign(1)
+		} else {
+			h.Flags[i].Assign(0)
+		}
+		var root big.Int
+		hint.Roots[i].ToBigIntRegular(&root)
+		h.Roots[i].Assign(root)
+	}
+}
+
+// Verify verifies a BLS signature of a message whose hash hm to G1 is computed by the caller,
+// either outside of the circuit (hm is then a public input) or with HashToG1.
+// It checks that e(S, G2) = e(hm, A).
+func Verify(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, pubKey PublicKey, hm sw.G1Affine, sig Signature) {
+	pubKey.mustBeValid(cs, pairingInfo.Extension)
+	pairingCheck(cs, pairingInfo, pubKey.A, hm, sig)
+}
+
+// VerifyAggregate verifies the aggregate signature of a message (whose hash to G1 is hm) by the owners
+// of pubKeys. It checks that e(S, G2) = e(hm, sum(A_i)).
+//
+// The public keys are added with the affine (incomplete) formulas, they must be distinct and come
+// with a proof of possession of the private key (cf crypto/signature/bls/bls377 VerifyAggregate).
+func VerifyAggregate(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, pubKeys []PublicKey, hm sw.G1Affine, sig Signature) {
+	if len(pubKeys) == 0 {
+		panic("bls: at least one public key is needed")
+	}
+	for i := 0; i < len(pubKeys); i++ {
+		pubKeys[i].mustBeValid(cs, pairingInfo.Extension)
+	}
+	apk := pubKeys[0].A
+	for i := 1; i < len(pubKeys); i++ {
+		apk.AddAssign(cs, &pubKeys[i].A, pairingInfo.Extension)
+	}
+	pairingCheck(cs, pairingInfo, apk, hm, sig)
+}
+
+// mustBeValid checks that the public key is in G2 (cf crypto/signature/bls/bls377 VerifyAggregate)
+func (p *PublicKey) mustBeValid(cs *frontend.ConstraintSystem, ext fields.Extension) {
+	p.A.MustBeOnCurve(cs, ext)
+	p.A.MustBeInSubgroup(cs, ext)
+}
+
+// pairingCheck checks that e(S, -G2) * e(hm, A) = 1, S being checked to be in G1
+func pairingCheck(cs *frontend.ConstraintSystem, pairingInfo sw.PairingContext, a sw.G2Affine, hm sw.G1Affine, sig Signature) {
+
+	sig.S.MustBeOnCurve(cs)
+	sig.S.MustBeInSubgroup(cs)
+
+	// -G2, known at compile time
+	_, _, _, g2 := bls377.Generators()
+	g2.Neg(&g2)
+	var g2Neg sw.G2Affine
+	g2Neg.X.A0 = cs.Constant(g2.X.A0)
+	g2Neg.X.A1 = cs.Constant(g2.X.A1)
+	g2Neg.Y.A0 = cs.Constant(g2.Y.A0)
+	g2Neg.Y.A1 = cs.Constant(g2.Y.A1)
+
+	sw.PairingCheck(cs, []sw.G1Affine{sig.S, hm}, []sw.G2Affine{g2Neg, a}, pairingInfo)
+}
+
+// HashToG1 hashes msg to G1 (cf crypto/signature/bls/bls377 HashToG1), the roots of the tries being given by hint
+//
+// For each try, hint proves that x_i^3 + 1 is a square (Flags[i] = 1) or not, so that the hash
+// is derived from the first valid x_i, and its root is checked to be in [0, (p-1)/2].
+func HashToG1(cs *frontend.ConstraintSystem, msg frontend.Variable, hint HashHint) sw.G1Affine {
+
+	h, err := mimc.NewMiMC(bls_bls377.HashSeed, gurvy.BW761)
+	if err != nil {
+		panic(err)
+	}
+	hm := h.Hash(cs, msg)
+
+	one := cs.Constant(1)
+	bMinusOne := big.NewInt(-1)
+	nonResidue := big.NewInt(bls_bls377.NonResidue)
+	var oneMinusNonResidue big.Int
+	oneMinusNonResidue.SetInt64(1 - bls_bls377.NonResidue)
+
+	// the selector of x_i is 1 iff x_i is the first valid try
+	var selectors []frontend.Variable
+	var x, y r1c.LinearExpression
+
+	for i := 0; i < bls_bls377.NbTries; i++ {
+
+		// x_i = hm + i
+		xi := cs.LinearExpression(
+			cs.Term(hm, bOne),
+			cs.Term(one, big.NewInt(int64(i))),
+		)
+
+		// roots[i]^2 = (x_i^3 + 1) * (flags[i] ? 1 : nonResidue)
+		cs.AssertIsBoolean(hint.Flags[i])
+		x3 := cs.Mul(cs.Mul(xi, xi), xi)
+		rhs := cs.LinearExpression(
+			cs.Term(x3, bOne),
+			cs.Term(one, bOne),
+		)
+		factor := cs.LinearExpression(
+			cs.Term(one, nonResidue),
+			cs.Term(hint.Flags[i], &oneMinusNonResidue),
+		)
+		cs.AssertIsEqual(cs.Mul(hint.Roots[i], hint.Roots[i]), cs.Mul(rhs, factor))
+
+		// selector = flags[i] * (1 - sum(previous selectors))
+		notDone := make(r1c.LinearExpression, 0, len(selectors)+1)
+		notDone = append(notDone, cs.Term(one, bOne))
+		for _, s := range selectors {
+			notDone = append(notDone, cs.Term(s, bMinusOne))
+		}
+		selector := cs.Mul(hint.Flags[i], notDone)
+		selectors = append(selectors, selector)
+
+		x = append(x, cs.Term(cs.Mul(selector, xi), bOne))
+		y = append(y, cs.Term(cs.Mul(selector, hint.Roots[i]), bOne))
+	}
+
+	// a valid x_i is found, with a canonical root
+	done := make(r1c.LinearExpression, len(selectors))
+	for i, s := range selectors {
+		done[i] = cs.Term(s, bOne)
+	}
+	cs.AssertIsEqual(done, 1)
+
+	var p sw.G1Affine
+	p.X = cs.Mul(x, 1)
+	p.Y = cs.Mul(y, 1)
+
+	var halfModulus big.Int
+	halfModulus.Rsh(fp.Modulus(), 1)
+	cs.AssertIsLessOrEqual(p.Y, halfModulus)
+
+	return clearCofactor(cs, p)
+}
+
+var bOne = big.NewInt(1)
+
+// clearCofactor returns (1-x0)*p, where x0 is the seed of BLS377 (cf bls377.G1Affine.ClearCofactor)
+//
+// The bits of x0 are known at compile time, the scalar multiplication is a plain double and add.
+func clearCofactor(cs *frontend.ConstraintSystem, p sw.G1Affine) sw.G1Affine {
+
+	var x0 big.Int
+	x0.SetString("9586122913090633729", 10)
+
+	res := p
+	for i := x0.BitLen() - 2; i >= 0; i-- {
+		res.Double(cs, &res)
+		if x0.Bit(i) == 1 {
+			res.AddAssign(cs, &p)
+		}
+	}
+
+	// p - x0*p
+	res.Neg(cs, &res).AddAssign(cs, &p)
+
+	return res
+}
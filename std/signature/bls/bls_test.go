@@ -0,0 +1,175 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	bls_bls377 "github.com/consensys/gnark/crypto/signature/bls/bls377"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/fields"
+	"github.com/consensys/gnark/std/algebra/sw"
+	"github.com/consensys/gurvy"
+	"github.com/consensys/gurvy/bls377/fp"
+)
+
+func pairingContext(cs *frontend.ConstraintSystem) sw.PairingContext {
+	var pairingInfo sw.PairingContext
+	pairingInfo.Extension = fields.GetBLS377ExtensionFp12(cs)
+	pairingInfo.AteLoop = 9586122913090633729
+	return pairingInfo
+}
+
+// signs msg with nbSigners keys, and returns the public keys and the aggregated signature
+func sign(t *testing.T, msg fp.Element, nbSigners int) ([]bls_bls377.PublicKey, bls_bls377.Signature) {
+	pubs := make([]bls_bls377.PublicKey, nbSigners)
+	sigs := make([]bls_bls377.Signature, nbSigners)
+	for i := 0; i < nbSigners; i++ {
+		var priv bls_bls377.PrivateKey
+		pubs[i], priv = bls_bls377.New([32]byte{byte(i)})
+		var err error
+		sigs[i], err = bls_bls377.Sign(msg, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	sig, err := bls_bls377.Aggregate(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := bls_bls377.VerifyAggregate(sig, msg, pubs); err != nil || !ok {
+		t.Fatal("verifying the signature should return true")
+	}
+	return pubs, sig
+}
+
+type precomputedCircuit struct {
+	PublicKeys []PublicKey
+	Hm         sw.G1Affine `gnark:",public"`
+	Signature  Signature
+}
+
+func (circuit *precomputedCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	VerifyAggregate(cs, pairingContext(cs), circuit.PublicKeys, circuit.Hm, circuit.Signature)
+	return nil
+}
+
+type hashToG1Circuit struct {
+	PublicKey PublicKey
+	Message   frontend.Variable `gnark:",public"`
+	Hint      HashHint
+	Signature Signature
+}
+
+func (circuit *hashToG1Circuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	hm := HashToG1(cs, circuit.Message, circuit.Hint)
+	Verify(cs, pairingContext(cs), circuit.PublicKey, hm, circuit.Signature)
+	return nil
+}
+
+func TestVerifyPrecomputed(t *testing.T) {
+
+	assert := groth16.NewAssert(t)
+
+	const nbSigners = 3
+
+	var msg fp.Element
+	msg.SetUint64(42)
+	pubs, sig := sign(t, msg, nbSigners)
+
+	circuit := precomputedCircuit{PublicKeys: make([]PublicKey, nbSigners)}
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hm, err := bls_bls377.HashToG1(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	witness := precomputedCircuit{PublicKeys: make([]PublicKey, nbSigners)}
+	for i := 0; i < nbSigners; i++ {
+		witness.PublicKeys[i].Assign(&pubs[i])
+	}
+	witness.Hm.Assign(&hm)
+	witness.Signature.Assign(&sig)
+	assert.SolvingSucceeded(r1cs, &witness)
+
+	// the signature doesn't match the hash of another message
+	msg.SetUint64(43)
+	hm, err = bls_bls377.HashToG1(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness.Hm = sw.G1Affine{}
+	witness.Hm.Assign(&hm)
+	assert.SolvingFailed(r1cs, &witness)
+}
+
+func TestVerifyHashToG1(t *testing.T) {
+
+	assert := groth16.NewAssert(t)
+
+	var msg fp.Element
+	msg.SetString("44717650746155748460101257525078853138837311576962212923649547644148297035978")
+	pubs, sig := sign(t, msg, 1)
+
+	var circuit hashToG1Circuit
+	r1cs, err := frontend.Compile(gurvy.BW761, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hint, err := bls_bls377.NewHashHint(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var witness hashToG1Circuit
+	witness.PublicKey.Assign(&pubs[0])
+	witness.Message.Assign(msg.String())
+	witness.Hint.Assign(&hint)
+	witness.Signature.Assign(&sig)
+	assert.SolvingSucceeded(r1cs, &witness)
+
+	// the first valid try can't be skipped
+	first := 0
+	for !hint.Flags[first] {
+		first++
+	}
+	hint.Flags[first] = false
+	witness.Hint = HashHint{}
+	witness.Hint.Assign(&hint)
+	assert.SolvingFailed(r1cs, &witness)
+	hint.Flags[first] = true
+
+	// the root must be canonical
+	hint.Roots[first].Neg(&hint.Roots[first])
+	witness.Hint = HashHint{}
+	witness.Hint.Assign(&hint)
+	assert.SolvingFailed(r1cs, &witness)
+	hint.Roots[first].Neg(&hint.Roots[first])
+
+	// wrong message
+	witness.Hint = HashHint{}
+	witness.Hint.Assign(&hint)
+	witness.Message = frontend.Variable{}
+	witness.Message.Assign(43)
+	assert.SolvingFailed(r1cs, &witness)
+}
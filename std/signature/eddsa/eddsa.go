@@ -36,8 +36,20 @@ type Signature struct {
 
 // Verify verifies an eddsa signature
 // cf https://en.wikipedia.org/wiki/EdDSA
+//
+// S must be reduced modulo the order of the subgroup (as produced by crypto/signature/eddsa), it is
+// decomposed on curve.Order.BitLen() bits.
+//
+// Number of constraints: 7378 on BN256, 6942 on BLS381, 5974 on BLS377, 10782 on BW761 (the double and add
+// scalar multiplications with the cofactor as a variable took 19082, 18693, 17651, 28185, without checking R and A).
 func Verify(cs *frontend.ConstraintSystem, sig Signature, msg frontend.Variable, pubKey PublicKey) error {
 
+	// R and A must be in the subgroup of prime order
+	sig.R.A.MustBeOnCurve(cs, pubKey.Curve)
+	sig.R.A.MustBeInSubgroup(cs, pubKey.Curve)
+	pubKey.A.MustBeOnCurve(cs, pubKey.Curve)
+	pubKey.A.MustBeInSubgroup(cs, pubKey.Curve)
+
 	// compute H(R, A, M), all parameters in data are in Montgomery form
 	data := []frontend.Variable{
 		sig.R.A.X,
@@ -53,19 +65,17 @@ func Verify(cs *frontend.ConstraintSystem, sig Signature, msg frontend.Variable,
 	}
 	hramConstantd := hash.Hash(cs, data...)
 
-	// lhs = cofactor*SB
-	cofactorConstantd := cs.Constant(pubKey.Curve.Cofactor)
+	// lhs = cofactor*SB, S < Order
 	lhs := twistededwards.Point{}
-
-	lhs.ScalarMulFixedBase(cs, pubKey.Curve.BaseX, pubKey.Curve.BaseY, sig.S, pubKey.Curve).
-		ScalarMulNonFixedBase(cs, &lhs, cofactorConstantd, pubKey.Curve)
+	lhs.ScalarMulFixedBaseWindowed(cs, pubKey.Curve.BaseX, pubKey.Curve.BaseY, sig.S, pubKey.Curve.Order.BitLen(), pubKey.Curve)
+	lhs.ClearCofactor(cs, &lhs, pubKey.Curve)
 	lhs.MustBeOnCurve(cs, pubKey.Curve)
 
 	// rhs = cofactor*(R+H(R,A,M)*A)
 	rhs := twistededwards.Point{}
 	rhs.ScalarMulNonFixedBase(cs, &pubKey.A, hramConstantd, pubKey.Curve).
-		AddGeneric(cs, &rhs, &sig.R.A, pubKey.Curve).
-		ScalarMulNonFixedBase(cs, &rhs, cofactorConstantd, pubKey.Curve)
+		AddGeneric(cs, &rhs, &sig.R.A, pubKey.Curve)
+	rhs.ClearCofactor(cs, &rhs, pubKey.Curve)
 	rhs.MustBeOnCurve(cs, pubKey.Curve)
 
 	cs.AssertIsEqual(lhs.X, rhs.X)
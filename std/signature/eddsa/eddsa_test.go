@@ -17,15 +17,22 @@ limitations under the License.
 package eddsa
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/consensys/gnark/backend/groth16"
+	mimc_bls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
 	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	mimc_bw761 "github.com/consensys/gnark/crypto/hash/mimc/bw761"
+	eddsa_bls377 "github.com/consensys/gnark/crypto/signature/eddsa/bls377"
 	eddsa_bn256 "github.com/consensys/gnark/crypto/signature/eddsa/bn256"
+	eddsa_bw761 "github.com/consensys/gnark/crypto/signature/eddsa/bw761"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/std/algebra/twistededwards"
 	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
 	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
 )
 
 type eddsaCircuit struct {
@@ -35,7 +42,7 @@ type eddsaCircuit struct {
 }
 
 func (circuit *eddsaCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
-	params, err := twistededwards.NewEdCurve(gurvy.BN256)
+	params, err := twistededwards.NewEdCurve(curveID)
 	if err != nil {
 		return err
 	}
@@ -113,3 +120,78 @@ func TestEddsa(t *testing.T) {
 		assert.SolvingFailed(r1cs, &witness)
 	}
 }
+
+// signedMessage public key, message and signature produced by the go implementation
+type signedMessage struct {
+	AX, AY, RX, RY, S, Msg big.Int
+}
+
+// checkEddsa checks that the circuit compiled on curve id accepts a signature produced by the go
+// implementation, and rejects it for another message
+func checkEddsa(t *testing.T, id gurvy.ID, signed signedMessage) {
+	assert := groth16.NewAssert(t)
+
+	var circuit eddsaCircuit
+	r1cs, err := frontend.Compile(id, &circuit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var witness eddsaCircuit
+	witness.Message.Assign(signed.Msg)
+	witness.PublicKey.A.X.Assign(signed.AX)
+	witness.PublicKey.A.Y.Assign(signed.AY)
+	witness.Signature.R.A.X.Assign(signed.RX)
+	witness.Signature.R.A.Y.Assign(signed.RY)
+	witness.Signature.S.Assign(signed.S)
+	assert.SolvingSucceeded(r1cs, &witness)
+
+	var msg big.Int
+	msg.Add(&signed.Msg, big.NewInt(1))
+	witness.Message = frontend.Variable{}
+	witness.Message.Assign(msg)
+	assert.SolvingFailed(r1cs, &witness)
+}
+
+func TestEddsaOtherCurves(t *testing.T) {
+
+	var seed [32]byte
+	copy(seed[:], "eddsa")
+	const msg = "44717650746155748460101257525078853138837311576962212923649547644148297035978"
+
+	t.Run("BLS377", func(t *testing.T) {
+		pubKey, privKey := eddsa_bls377.New(seed, mimc_bls377.NewMiMC("seed"))
+		var m fr_bls377.Element
+		m.SetString(msg)
+		signature, err := eddsa_bls377.Sign(m, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var signed signedMessage
+		pubKey.A.X.ToBigIntRegular(&signed.AX)
+		pubKey.A.Y.ToBigIntRegular(&signed.AY)
+		signature.R.X.ToBigIntRegular(&signed.RX)
+		signature.R.Y.ToBigIntRegular(&signed.RY)
+		signature.S.ToBigIntRegular(&signed.S)
+		m.ToBigIntRegular(&signed.Msg)
+		checkEddsa(t, gurvy.BLS377, signed)
+	})
+
+	t.Run("BW761", func(t *testing.T) {
+		pubKey, privKey := eddsa_bw761.New(seed, mimc_bw761.NewMiMC("seed"))
+		var m fr_bw761.Element
+		m.SetString(msg)
+		signature, err := eddsa_bw761.Sign(m, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var signed signedMessage
+		pubKey.A.X.ToBigIntRegular(&signed.AX)
+		pubKey.A.Y.ToBigIntRegular(&signed.AY)
+		signature.R.X.ToBigIntRegular(&signed.RX)
+		signature.R.Y.ToBigIntRegular(&signed.RY)
+		signature.S.ToBigIntRegular(&signed.S)
+		m.ToBigIntRegular(&signed.Msg)
+		checkEddsa(t, gurvy.BW761, signed)
+	})
+}
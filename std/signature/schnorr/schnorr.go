@@ -0,0 +1,75 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+)
+
+// PublicKey stores a Schnorr public key (to be used in gnark circuit)
+type PublicKey struct {
+	A     twistededwards.Point
+	Curve twistededwards.EdCurve
+}
+
+// Signature stores a Schnorr signature (to be used in gnark circuit)
+type Signature struct {
+	R twistededwards.Point
+	S frontend.Variable
+}
+
+// Verify verifies a Schnorr signature (cf crypto/signature/schnorr), that is it checks that
+// S < Order and cofactor*S*Base = cofactor*(R + H(R,A,M)*A)
+//
+// hasher computes the challenge H(R, A, M), it must match the hash.Hash used to sign with
+// crypto/signature/schnorr
+func Verify(cs *frontend.ConstraintSystem, hasher hash.Hash, sig Signature, msg frontend.Variable, pubKey PublicKey) {
+
+	curve := pubKey.Curve
+
+	// R and A must be in the subgroup of prime order
+	sig.R.MustBeOnCurve(cs, curve)
+	sig.R.MustBeInSubgroup(cs, curve)
+	pubKey.A.MustBeOnCurve(cs, curve)
+	pubKey.A.MustBeInSubgroup(cs, curve)
+
+	// compute H(R, A, M)
+	e := hasher.Hash(cs, sig.R.X, sig.R.Y, pubKey.A.X, pubKey.A.Y, msg)
+
+	// S < Order, otherwise S+Order would be a second valid signature
+	var maxS big.Int
+	maxS.Sub(&curve.Order, big.NewInt(1))
+	cs.AssertIsLessOrEqual(sig.S, maxS)
+
+	// lhs = cofactor*S*Base
+	lhs := twistededwards.Point{}
+	lhs.ScalarMulFixedBaseWindowed(cs, curve.BaseX, curve.BaseY, sig.S, curve.Order.BitLen(), curve)
+	lhs.ClearCofactor(cs, &lhs, curve)
+
+	// rhs = cofactor*(R + H(R,A,M)*A)
+	rhs := twistededwards.Point{}
+	rhs.ScalarMulNonFixedBase(cs, &pubKey.A, e, curve).
+		AddGeneric(cs, &rhs, &sig.R, curve)
+	rhs.ClearCofactor(cs, &rhs, curve)
+
+	cs.AssertIsEqual(lhs.X, rhs.X)
+	cs.AssertIsEqual(lhs.Y, rhs.Y)
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schnorr
+
+import (
+	"fmt"
+	gohash "hash"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	mimc_bls377 "github.com/consensys/gnark/crypto/hash/mimc/bls377"
+	mimc_bls381 "github.com/consensys/gnark/crypto/hash/mimc/bls381"
+	mimc_bn256 "github.com/consensys/gnark/crypto/hash/mimc/bn256"
+	mimc_bw761 "github.com/consensys/gnark/crypto/hash/mimc/bw761"
+	poseidon_bls377 "github.com/consensys/gnark/crypto/hash/poseidon/bls377"
+	poseidon_bls381 "github.com/consensys/gnark/crypto/hash/poseidon/bls381"
+	poseidon_bn256 "github.com/consensys/gnark/crypto/hash/poseidon/bn256"
+	poseidon_bw761 "github.com/consensys/gnark/crypto/hash/poseidon/bw761"
+	schnorr_bls377 "github.com/consensys/gnark/crypto/signature/schnorr/bls377"
+	schnorr_bls381 "github.com/consensys/gnark/crypto/signature/schnorr/bls381"
+	schnorr_bn256 "github.com/consensys/gnark/crypto/signature/schnorr/bn256"
+	schnorr_bw761 "github.com/consensys/gnark/crypto/signature/schnorr/bw761"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/twistededwards"
+	"github.com/consensys/gnark/std/hash"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/hash/poseidon"
+	"github.com/consensys/gurvy"
+	fr_bls377 "github.com/consensys/gurvy/bls377/fr"
+	fr_bls381 "github.com/consensys/gurvy/bls381/fr"
+	fr_bn256 "github.com/consensys/gurvy/bn256/fr"
+	fr_bw761 "github.com/consensys/gurvy/bw761/fr"
+)
+
+const (
+	hashMiMC     = "mimc"
+	hashPoseidon = "poseidon"
+)
+
+type schnorrCircuit struct {
+	PublicKey PublicKey         `gnark:",public"`
+	Signature Signature         `gnark:",public"`
+	Message   frontend.Variable `gnark:",public"`
+
+	hash string
+}
+
+func (circuit *schnorrCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	params, err := twistededwards.NewEdCurve(curveID)
+	if err != nil {
+		return err
+	}
+	circuit.PublicKey.Curve = params
+
+	var hasher hash.Hash
+	switch circuit.hash {
+	case hashMiMC:
+		hasher, err = mimc.NewMiMC("seed", curveID)
+	case hashPoseidon:
+		hasher, err = poseidon.NewPoseidon("seed", curveID)
+	default:
+		err = fmt.Errorf("unknown hash function %q", circuit.hash)
+	}
+	if err != nil {
+		return err
+	}
+
+	Verify(cs, hasher, circuit.Signature, circuit.Message, circuit.PublicKey)
+
+	return nil
+}
+
+// signedMessage public key, message and signature produced by the go implementation
+type signedMessage struct {
+	AX, AY, RX, RY, S, Msg big.Int
+}
+
+// nativeHash returns the go implementation of the hash functions used in the circuit
+var nativeHash = map[gurvy.ID]map[string]func() gohash.Hash{
+	gurvy.BN256: {
+		hashMiMC:     func() gohash.Hash { return mimc_bn256.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bn256.NewPoseidon("seed") },
+	},
+	gurvy.BLS381: {
+		hashMiMC:     func() gohash.Hash { return mimc_bls381.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bls381.NewPoseidon("seed") },
+	},
+	gurvy.BLS377: {
+		hashMiMC:     func() gohash.Hash { return mimc_bls377.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bls377.NewPoseidon("seed") },
+	},
+	gurvy.BW761: {
+		hashMiMC:     func() gohash.Hash { return mimc_bw761.NewMiMC("seed") },
+		hashPoseidon: func() gohash.Hash { return poseidon_bw761.NewPoseidon("seed") },
+	},
+}
+
+// sign signs msg with the go implementation of the curve, checks the signature, and returns it
+var sign = map[gurvy.ID]func(t *testing.T, hFunc gohash.Hash, msg string) signedMessage{
+	gurvy.BN256: func(t *testing.T, hFunc gohash.Hash, msg string) (res signedMessage) {
+		pubKey, privKey := schnorr_bn256.New([32]byte{42}, hFunc)
+		var m fr_bn256.Element
+		m.SetString(msg)
+		signature, err := schnorr_bn256.Sign(m, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := schnorr_bn256.Verify(signature, m, pubKey); err != nil || !ok {
+			t.Fatal("Verifying the signature should return true")
+		}
+		pubKey.A.X.ToBigIntRegular(&res.AX)
+		pubKey.A.Y.ToBigIntRegular(&res.AY)
+		signature.R.X.ToBigIntRegular(&res.RX)
+		signature.R.Y.ToBigIntRegular(&res.RY)
+		signature.S.ToBigIntRegular(&res.S)
+		m.ToBigIntRegular(&res.Msg)
+		return
+	},
+	gurvy.BLS381: func(t *testing.T, hFunc gohash.Hash, msg string) (res signedMessage) {
+		pubKey, privKey := schnorr_bls381.New([32]byte{42}, hFunc)
+		var m fr_bls381.Element
+		m.SetString(msg)
+		signature, err := schnorr_bls381.Sign(m, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := schnorr_bls381.Verify(signature, m, pubKey); err != nil || !ok {
+			t.Fatal("Verifying the signature should return true")
+		}
+		pubKey.A.X.ToBigIntRegular(&res.AX)
+		pubKey.A.Y.ToBigIntRegular(&res.AY)
+		signature.R.X.ToBigIntRegular(&res.RX)
+		signature.R.Y.ToBigIntRegular(&res.RY)
+		signature.S.ToBigIntRegular(&res.S)
+		m.ToBigIntRegular(&res.Msg)
+		return
+	},
+	gurvy.BLS377: func(t *testing.T, hFunc gohash.Hash, msg string) (res signedMessage) {
+		pubKey, privKey := schnorr_bls377.New([32]byte{42}, hFunc)
+		var m fr_bls377.Element
+		m.SetString(msg)
+		signature, err := schnorr_bls377.Sign(m, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := schnorr_bls377.Verify(signature, m, pubKey); err != nil || !ok {
+			t.Fatal("Verifying the signature should return true")
+		}
+		pubKey.A.X.ToBigIntRegular(&res.AX)
+		pubKey.A.Y.ToBigIntRegular(&res.AY)
+		signature.R.X.ToBigIntRegular(&res.RX)
+		signature.R.Y.ToBigIntRegular(&res.RY)
+		signature.S.ToBigIntRegular(&res.S)
+		m.ToBigIntRegular(&res.Msg)
+		return
+	},
+	gurvy.BW761: func(t *testing.T, hFunc gohash.Hash, msg string) (res signedMessage) {
+		pubKey, privKey := schnorr_bw761.New([32]byte{42}, hFunc)
+		var m fr_bw761.Element
+		m.SetString(msg)
+		signature, err := schnorr_bw761.Sign(m, pubKey, privKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := schnorr_bw761.Verify(signature, m, pubKey); err != nil || !ok {
+			t.Fatal("Verifying the signature should return true")
+		}
+		pubKey.A.X.ToBigIntRegular(&res.AX)
+		pubKey.A.Y.ToBigIntRegular(&res.AY)
+		signature.R.X.ToBigIntRegular(&res.RX)
+		signature.R.Y.ToBigIntRegular(&res.RY)
+		signature.S.ToBigIntRegular(&res.S)
+		m.ToBigIntRegular(&res.Msg)
+		return
+	},
+}
+
+func TestSchnorr(t *testing.T) {
+
+	for _, id := range []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761} {
+		for _, h := range []string{hashMiMC, hashPoseidon} {
+			id, h := id, h
+			t.Run(fmt.Sprintf("%s/%s", id.String(), h), func(t *testing.T) {
+				assert := groth16.NewAssert(t)
+
+				signed := sign[id](t, nativeHash[id][h](), "12345678901234567890")
+
+				circuit := schnorrCircuit{hash: h}
+				r1cs, err := frontend.Compile(id, &circuit)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				witness := schnorrCircuit{hash: h}
+				witness.Message.Assign(signed.Msg)
+				witness.PublicKey.A.X.Assign(signed.AX)
+				witness.PublicKey.A.Y.Assign(signed.AY)
+				witness.Signature.R.X.Assign(signed.RX)
+				witness.Signature.R.Y.Assign(signed.RY)
+				witness.Signature.S.Assign(signed.S)
+
+				assert.SolvingSucceeded(r1cs, &witness)
+
+				// verification with S+Order, which is also a solution of the verification equation
+				var s big.Int
+				s.Add(&signed.S, &circuit.PublicKey.Curve.Order)
+				witness.Signature.S = frontend.Variable{}
+				witness.Signature.S.Assign(s)
+
+				assert.SolvingFailed(r1cs, &witness)
+
+				witness.Signature.S = frontend.Variable{}
+				witness.Signature.S.Assign(signed.S)
+
+				// verification with an incorrect Message
+				var msg big.Int
+				msg.Add(&signed.Msg, big.NewInt(1))
+				witness.Message = frontend.Variable{}
+				witness.Message.Assign(msg)
+
+				assert.SolvingFailed(r1cs, &witness)
+			})
+		}
+	}
+}
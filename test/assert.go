@@ -0,0 +1,223 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/r1cs"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/io"
+	"github.com/consensys/gurvy"
+	"github.com/stretchr/testify/require"
+)
+
+// Curves supported by the helpers, used when no curve is specified (cf WithCurves)
+var Curves = []gurvy.ID{gurvy.BN256, gurvy.BLS381, gurvy.BLS377, gurvy.BW761}
+
+// Assert is a helper to test circuits
+type Assert struct {
+	*require.Assertions
+}
+
+// NewAssert returns an Assert helper
+func NewAssert(t *testing.T) *Assert {
+	return &Assert{require.New(t)}
+}
+
+// Option modifies the checks of an Assert
+type Option func(*config)
+
+type config struct {
+	curves         []gurvy.ID
+	maxConstraints int // 0 if there is no budget
+}
+
+// WithCurves restricts the checks to the given curves
+func WithCurves(curves ...gurvy.ID) Option {
+	return func(c *config) {
+		c.curves = curves
+	}
+}
+
+// WithMaxConstraints checks that the compiled circuit has at most maxConstraints constraints
+func WithMaxConstraints(maxConstraints int) Option {
+	return func(c *config) {
+		c.maxConstraints = maxConstraints
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{curves: Curves}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// ProverSucceeded checks that witness solves the circuit, on every curve (cf WithCurves)
+//
+// circuit is compiled for each curve, on a copy whose variables are reset (it is left untouched,
+// and can be a witness).
+// witness must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+//
+// 1. Executes the circuit with the engine (cf IsSolved)
+//
+// 2. Compiles the circuit, and checks the number of constraints (cf WithMaxConstraints)
+//
+// 3. Solves the R1CS
+//
+// 4. Runs groth16.Setup(), groth16.Prove() and groth16.Verify()
+//
+// The R1CS, the keys and the proof go through a serialization round trip before being used.
+func (assert *Assert) ProverSucceeded(circuit frontend.Circuit, witness interface{}, opts ...Option) {
+	c := newConfig(opts)
+	for _, curveID := range c.curves {
+		assert.NoError(IsSolved(circuit, witness, curveID), "executing the circuit with good witness should not output an error (%s)", curveID.String())
+
+		ccs := assert.compile(circuit, curveID, c)
+		assert.NoError(ccs.IsSolved(assert.parseWitness(witness)), "solving with good witness should not output an error (%s)", curveID.String())
+
+		pk, vk := groth16.Setup(ccs)
+		pk = assert.roundTrip(pk).(groth16.ProvingKey)
+		vk = assert.roundTrip(vk).(groth16.VerifyingKey)
+
+		proof, err := groth16.Prove(ccs, pk, witness)
+		assert.NoError(err, "proving with good witness should not output an error (%s)", curveID.String())
+		proof = assert.roundTrip(proof).(groth16.Proof)
+
+		assert.NoError(groth16.Verify(proof, vk, witness), "verifying proof with good witness should not output an error (%s)", curveID.String())
+	}
+}
+
+// ProverFailed checks that witness does NOT solve the circuit, on every curve (cf WithCurves):
+// the execution of the circuit by the engine, the R1CS solver and groth16.Prove() must fail.
+//
+// circuit is compiled for each curve, on a copy whose variables are reset (it is left untouched,
+// and can be a witness).
+// witness must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) ProverFailed(circuit frontend.Circuit, witness interface{}, opts ...Option) {
+	c := newConfig(opts)
+	for _, curveID := range c.curves {
+		assert.Error(IsSolved(circuit, witness, curveID), "executing the circuit with bad witness should output an error (%s)", curveID.String())
+
+		ccs := assert.compile(circuit, curveID, c)
+		assert.Error(ccs.IsSolved(assert.parseWitness(witness)), "solving with bad witness should output an error (%s)", curveID.String())
+
+		pk := groth16.DummySetup(ccs)
+		_, err := groth16.Prove(ccs, pk, witness)
+		assert.Error(err, "proving with bad witness should output an error (%s)", curveID.String())
+	}
+}
+
+// SolvingSucceeded checks that witness solves the circuit on every curve (cf WithCurves),
+// by executing the circuit with the engine (cf IsSolved)
+//
+// witness must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) SolvingSucceeded(circuit frontend.Circuit, witness interface{}, opts ...Option) {
+	for _, curveID := range newConfig(opts).curves {
+		assert.NoError(IsSolved(circuit, witness, curveID), "solving with good witness should not output an error (%s)", curveID.String())
+	}
+}
+
+// SolvingFailed checks that witness does NOT solve the circuit on every curve (cf WithCurves),
+// by executing the circuit with the engine (cf IsSolved)
+//
+// witness must be map[string]interface{} or must implement frontend.Circuit
+// ( see frontend.ParseWitness )
+func (assert *Assert) SolvingFailed(circuit frontend.Circuit, witness interface{}, opts ...Option) {
+	for _, curveID := range newConfig(opts).curves {
+		assert.Error(IsSolved(circuit, witness, curveID), "solving with bad witness should output an error (%s)", curveID.String())
+	}
+}
+
+// compile compiles a copy of circuit (cf copyCircuit), checks the budget of constraints, and returns the R1CS after
+// a serialization round trip
+func (assert *Assert) compile(circuit frontend.Circuit, curveID gurvy.ID, c config) r1cs.R1CS {
+	ccs, err := frontend.Compile(curveID, copyCircuit(circuit))
+	assert.NoError(err, "compiling the circuit should not output an error (%s)", curveID.String())
+	if c.maxConstraints != 0 {
+		assert.LessOrEqual(ccs.GetNbConstraints(), c.maxConstraints, "the number of constraints is over the budget (%s)", curveID.String())
+	}
+	return assert.roundTrip(ccs).(r1cs.R1CS)
+}
+
+// roundTrip serializes and deserializes from, and returns the deserialized object
+func (assert *Assert) roundTrip(from io.CurveObject) io.CurveObject {
+	var buf bytes.Buffer
+	assert.NoError(io.Write(&buf, from), "serializing should not output an error")
+	into := reflect.New(reflect.TypeOf(from).Elem()).Interface().(io.CurveObject)
+	assert.NoError(io.Read(&buf, into), "deserializing should not output an error")
+	return into
+}
+
+func (assert *Assert) parseWitness(witness interface{}) map[string]interface{} {
+	_witness, err := frontend.ParseWitness(witness)
+	assert.NoError(err)
+	return _witness
+}
+
+// copyCircuit returns a copy of circuit to compile: the slices of variables are copied and the
+// variables are reset, so that compiling the copy doesn't modify circuit
+func copyCircuit(circuit frontend.Circuit) frontend.Circuit {
+	value := reflect.ValueOf(circuit)
+	if value.Kind() != reflect.Ptr {
+		return circuit
+	}
+	res := reflect.New(value.Elem().Type())
+	res.Elem().Set(value.Elem())
+	resetVariables(res.Elem())
+	return res.Interface().(frontend.Circuit)
+}
+
+var tVariable = reflect.TypeOf(frontend.Variable{})
+
+// resetVariables resets the variables of v (which can be set), the slices being replaced by copies
+func resetVariables(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == tVariable {
+			v.Set(reflect.Zero(tVariable))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				resetVariables(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		v.Set(s)
+		for i := 0; i < v.Len(); i++ {
+			resetVariables(v.Index(i))
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			resetVariables(v.Index(i))
+		}
+	}
+}
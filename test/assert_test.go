@@ -0,0 +1,103 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/consensys/gurvy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertCircuits(t *testing.T) {
+	assert := NewAssert(t)
+	var opts []Option
+	if testing.Short() {
+		opts = append(opts, WithCurves(gurvy.BN256))
+	}
+	for name, circuit := range circuits.Circuits {
+		t.Log(name)
+		assert.ProverSucceeded(circuit.Good, circuit.Good, opts...)
+		assert.ProverFailed(circuit.Bad, circuit.Bad, opts...)
+	}
+}
+
+type sliceCircuit struct {
+	X []frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+// Define declares Y == X[0] * X[1] * ...
+func (circuit *sliceCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	product := circuit.X[0]
+	for i := 1; i < len(circuit.X); i++ {
+		product = cs.Mul(product, circuit.X[i])
+	}
+	cs.AssertIsEqual(product, circuit.Y)
+	return nil
+}
+
+func TestAssertBudget(t *testing.T) {
+
+	circuit := sliceCircuit{X: make([]frontend.Variable, 3)}
+
+	good := sliceCircuit{X: make([]frontend.Variable, 3)}
+	bad := sliceCircuit{X: make([]frontend.Variable, 3)}
+	for i := 0; i < 3; i++ {
+		good.X[i].Assign(i + 2)
+		bad.X[i].Assign(i + 2)
+	}
+	good.Y.Assign(24)
+	bad.Y.Assign(25)
+
+	assert := NewAssert(t)
+	assert.ProverSucceeded(&circuit, &good, WithMaxConstraints(3))
+	assert.ProverFailed(&circuit, &bad, WithCurves(gurvy.BN256))
+
+	// the circuit is untouched, and is over a budget of 2 constraints
+	if _, err := frontend.Compile(gurvy.BN256, &circuit); err != nil {
+		t.Fatal(err)
+	}
+	var overBudget recordingT
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert := &Assert{require.New(&overBudget)}
+		assert.ProverSucceeded(&circuit, &good, WithMaxConstraints(2), WithCurves(gurvy.BN256))
+	}()
+	<-done
+	if !overBudget.failed {
+		t.Fatal("a circuit over the budget should fail")
+	}
+}
+
+// recordingT records the failure of an Assert
+type recordingT struct {
+	failed bool
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failed = true
+}
+
+func (t *recordingT) FailNow() {
+	t.failed = true
+	runtime.Goexit()
+}
@@ -0,0 +1,22 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package test provides helpers to test circuits from go test.
+//
+// The circuits are executed by an engine which runs Define on the values of the witness
+// (cf frontend.Execute): no R1CS nor setup is needed, and a failure is reported with the
+// Go call stack of the instruction of the circuit which failed.
+package test
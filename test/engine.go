@@ -0,0 +1,34 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gurvy"
+)
+
+// IsSolved executes circuit.Define on the values of witness, modulo the snark field of curveID,
+// and returns the error of the first constraint which is not satisfied, if any (cf frontend.Execute).
+//
+// witness must be map[string]interface{} or must implement frontend.Circuit ( see frontend.ParseWitness ).
+// It can be the circuit itself, when its variables are assigned.
+//
+// opts configure the solver, for example the output of the logs (see backend.WithLogWriter)
+func IsSolved(circuit frontend.Circuit, witness interface{}, curveID gurvy.ID, opts ...backend.SolveOption) error {
+	return frontend.Execute(curveID, circuit, witness, opts...)
+}
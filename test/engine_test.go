@@ -0,0 +1,124 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark/backend"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/internal/backend/circuits"
+	"github.com/consensys/gurvy"
+)
+
+func TestEngineCircuits(t *testing.T) {
+	assert := NewAssert(t)
+	for name, circuit := range circuits.Circuits {
+		t.Log(name)
+		assert.SolvingSucceeded(circuit.Good, circuit.Good)
+		assert.SolvingFailed(circuit.Bad, circuit.Bad)
+	}
+}
+
+type stackCircuit struct {
+	X, Y frontend.Variable
+	Z    frontend.Variable `gnark:",public"`
+}
+
+// checkProduct fails if x*y != z
+func checkProduct(cs *frontend.ConstraintSystem, x, y, z frontend.Variable) {
+	cs.AssertIsEqual(cs.Mul(x, y), z)
+}
+
+func (circuit *stackCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	bits := cs.ToBinary(circuit.X, 8)
+	checkProduct(cs, cs.FromBinary(bits...), circuit.Y, circuit.Z)
+	return nil
+}
+
+func TestEngineStack(t *testing.T) {
+
+	var circuit, witness stackCircuit
+	witness.X.Assign(3)
+	witness.Y.Assign(5)
+	witness.Z.Assign(16)
+
+	err := IsSolved(&circuit, &witness, gurvy.BN256)
+	if !errors.Is(err, backend.ErrUnsatisfiedConstraint) {
+		t.Fatal("the assertion should fail")
+	}
+
+	// the resolved assertion, and the stack up to Define
+	for _, s := range []string{"15 == 16", "test.checkProduct", "engine_test.go:", "(*stackCircuit).Define"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("the error should contain %q:\n%s", s, err.Error())
+		}
+	}
+
+	// the circuit is left untouched, it can still be compiled
+	if _, err := frontend.Compile(gurvy.BN256, &circuit); err != nil {
+		t.Fatal(err)
+	}
+
+	// x doesn't fit on 8 bits
+	var overflow stackCircuit
+	overflow.X.Assign(256)
+	overflow.Y.Assign(1)
+	overflow.Z.Assign(256)
+	err = IsSolved(&overflow, &overflow, gurvy.BN256)
+	if err == nil || !strings.Contains(err.Error(), "ToBinary") {
+		t.Fatal("the decomposition of a too large value should fail in ToBinary")
+	}
+
+	// missing input
+	if err := IsSolved(&overflow, map[string]interface{}{"X": 3, "Y": 5}, gurvy.BN256); !errors.Is(err, backend.ErrInputNotSet) {
+		t.Fatal("executing a circuit without a value for an input should fail")
+	}
+}
+
+type hintCircuit struct {
+	X, Y frontend.Variable
+}
+
+func (circuit *hintCircuit) Define(curveID gurvy.ID, cs *frontend.ConstraintSystem) error {
+	// Y = X / 2, computed by a hint, and a division by zero
+	half := cs.NewHint("test.half", 1, circuit.X)[0]
+	cs.AssertIsEqual(cs.Mul(half, 2), circuit.X)
+	cs.AssertIsEqual(half, circuit.Y)
+	cs.Inverse(cs.Sub(circuit.X, circuit.X))
+	return nil
+}
+
+func init() {
+	backend.RegisterHint("test.half", func(inputs, outputs []big.Int) error {
+		outputs[0].Rsh(&inputs[0], 1)
+		return nil
+	})
+}
+
+func TestEngineHint(t *testing.T) {
+	var witness hintCircuit
+	witness.X.Assign(42)
+	witness.Y.Assign(21)
+	err := IsSolved(&hintCircuit{}, &witness, gurvy.BLS381)
+	if err == nil || !strings.Contains(err.Error(), "division by zero") || !strings.Contains(err.Error(), "Inverse") {
+		t.Fatal("the inverse of zero should fail, after the hint")
+	}
+}